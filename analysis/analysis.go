@@ -2,6 +2,9 @@ package analysis
 
 import (
 	"fmt"
+	"net/url"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,6 +27,8 @@ const (
 	// DefaultErrorRetryInterval is the default interval to retry a measurement upon error, in the
 	// event an interval was not specified
 	DefaultErrorRetryInterval int32 = 10
+	// redactedSecret replaces the values of the secrets in the measurements
+	redactedSecret = "*****"
 )
 
 // Event reasons for analysis events
@@ -51,6 +56,9 @@ func (c *AnalysisController) reconcileAnalysisRun(origRun *v1alpha1.AnalysisRun)
 			MetricResults: make([]v1alpha1.MetricResult, 0),
 		}
 		err := analysisutil.ValidateAnalysisTemplateSpec(run.Spec.AnalysisSpec)
+		if err == nil {
+			_, err = analysisutil.ResolveArgs(run.Spec.AnalysisSpec.Args, run.Spec.Arguments)
+		}
 		if err != nil {
 			message := fmt.Sprintf("analysis spec invalid: %v", err)
 			log.Warn(message)
@@ -65,6 +73,14 @@ func (c *AnalysisController) reconcileAnalysisRun(origRun *v1alpha1.AnalysisRun)
 	c.runMeasurements(run, tasks)
 
 	newStatus := c.asssessRunStatus(run)
+	if newStatus == v1alpha1.AnalysisStatusRunning && failFastTriggered(run) {
+		// a fail-fast metric failed. the run is now terminating, so the remaining tasks are the
+		// in-flight measurements of the other metrics, which need to be terminated right away
+		tasks := generateMetricTasks(run)
+		log.Infof("fail-fast metric failed: terminating %d in-progress measurements", len(tasks))
+		c.runMeasurements(run, tasks)
+		newStatus = c.asssessRunStatus(run)
+	}
 	if newStatus != run.Status.Status {
 		message := fmt.Sprintf("analysis transitioned from %s -> %s", run.Status.Status, newStatus)
 		if newStatus.Completed() {
@@ -93,6 +109,23 @@ func (c *AnalysisController) reconcileAnalysisRun(origRun *v1alpha1.AnalysisRun)
 	return run
 }
 
+// failFastTriggered returns whether or not a metric configured to fail fast has completed as
+// Failed or Error
+func failFastTriggered(run *v1alpha1.AnalysisRun) bool {
+	for _, metric := range run.Spec.AnalysisSpec.Metrics {
+		if !metric.FailFast {
+			continue
+		}
+		if result := analysisutil.GetResult(run, metric.Name); result != nil {
+			switch result.Status {
+			case v1alpha1.AnalysisStatusFailed, v1alpha1.AnalysisStatusError:
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // generateMetricTasks generates a list of metrics tasks needed to be measured as part of this
 // sync, based on the last completion times that metric was measured (if ever). If the run is
 // terminating (e.g. due to manual termination or failing metric), will not schedule further
@@ -107,6 +140,10 @@ func generateMetricTasks(run *v1alpha1.AnalysisRun) []metricTask {
 		}
 		lastMeasurement := analysisutil.LastMeasurement(run, metric.Name)
 		if lastMeasurement != nil && lastMeasurement.FinishedAt == nil {
+			if !terminating && lastMeasurement.ResumeAt != nil && lastMeasurement.ResumeAt.After(time.Now()) {
+				// the provider asked to resume the in-progress measurement at a later time
+				continue
+			}
 			// last measurement is still in-progress. need to complete it
 			log.WithField("metric", metric.Name).Infof("resuming in-progress measurement")
 			tasks = append(tasks, metricTask{
@@ -154,6 +191,8 @@ func (c *AnalysisController) runMeasurements(run *v1alpha1.AnalysisRun, tasks []
 	// we are performing queries in parallel
 	var resultsLock sync.Mutex
 	terminating := analysisutil.IsTerminating(run)
+	// the values of the secrets referenced by the arguments are only passed to the providers
+	args, secrets, argsErr := c.resolveArgs(run)
 
 	for _, task := range tasks {
 		wg.Add(1)
@@ -175,6 +214,9 @@ func (c *AnalysisController) runMeasurements(run *v1alpha1.AnalysisRun, tasks []
 
 			var newMeasurement v1alpha1.Measurement
 			provider, err := c.newProvider(*log, t.metric)
+			if err == nil {
+				err = argsErr
+			}
 			if err != nil {
 				if t.incompleteMeasurement != nil {
 					newMeasurement = *t.incompleteMeasurement
@@ -186,20 +228,22 @@ func (c *AnalysisController) runMeasurements(run *v1alpha1.AnalysisRun, tasks []
 				newMeasurement.Message = err.Error()
 			} else {
 				if t.incompleteMeasurement == nil {
-					newMeasurement = provider.Run(run, t.metric, run.Spec.Arguments)
+					newMeasurement = provider.Run(run, t.metric, args)
 				} else {
 					// metric is incomplete. either terminate or resume it
 					if terminating {
 						log.Infof("terminating in-progress measurement")
-						newMeasurement = provider.Terminate(run, t.metric, run.Spec.Arguments, *t.incompleteMeasurement)
+						newMeasurement = provider.Terminate(run, t.metric, args, *t.incompleteMeasurement)
 						if newMeasurement.Status == v1alpha1.AnalysisStatusSuccessful {
 							newMeasurement.Message = "metric terminated"
 						}
 					} else {
-						newMeasurement = provider.Resume(run, t.metric, run.Spec.Arguments, *t.incompleteMeasurement)
+						newMeasurement = provider.Resume(run, t.metric, args, *t.incompleteMeasurement)
 					}
 				}
 			}
+			// the providers may report the values of the secrets, e.g. in the URL of a failed request
+			newMeasurement = redactSecrets(newMeasurement, secrets)
 
 			if newMeasurement.Status.Completed() {
 				log.Infof("measurement completed %s", newMeasurement.Status)
@@ -241,6 +285,70 @@ func (c *AnalysisController) runMeasurements(run *v1alpha1.AnalysisRun, tasks []
 	wg.Wait()
 }
 
+// resolveArgs returns the arguments of the run with the default values of the arguments which are not passed, and the
+// values of the secrets they reference. The values of the secrets are also returned on their own to be redacted from
+// the measurements.
+func (c *AnalysisController) resolveArgs(run *v1alpha1.AnalysisRun) ([]v1alpha1.Argument, []string, error) {
+	runArgs, err := analysisutil.ResolveArgs(run.Spec.AnalysisSpec.Args, run.Spec.Arguments)
+	if err != nil {
+		return nil, nil, err
+	}
+	args := make([]v1alpha1.Argument, 0, len(runArgs))
+	var secrets []string
+	for _, arg := range runArgs {
+		if arg.ValueFrom == nil || arg.ValueFrom.SecretKeyRef == nil {
+			args = append(args, arg)
+			continue
+		}
+		ref := arg.ValueFrom.SecretKeyRef
+		secret, err := c.kubeclientset.CoreV1().Secrets(run.Namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve argument '%s': %v", arg.Name, err)
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, nil, fmt.Errorf("failed to resolve argument '%s': key '%s' does not exist in secret '%s'", arg.Name, ref.Key, ref.Name)
+		}
+		args = append(args, v1alpha1.Argument{
+			Name:  arg.Name,
+			Value: string(value),
+		})
+		if len(value) > 0 {
+			secrets = append(secrets, string(value))
+		}
+	}
+	return args, secrets, nil
+}
+
+// redactSecrets replaces the values of the secrets, as is or escaped in a URL, in the message and the metadata of
+// the measurement
+func redactSecrets(measurement v1alpha1.Measurement, secrets []string) v1alpha1.Measurement {
+	if len(secrets) == 0 {
+		return measurement
+	}
+	// the longest secrets are replaced first in case a secret contains another one
+	secrets = append([]string(nil), secrets...)
+	sort.Slice(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+	var oldnew []string
+	for _, secret := range secrets {
+		for _, value := range []string{secret, url.QueryEscape(secret), url.PathEscape(secret)} {
+			oldnew = append(oldnew, value, redactedSecret)
+		}
+	}
+	replacer := strings.NewReplacer(oldnew...)
+	measurement.Message = replacer.Replace(measurement.Message)
+	if measurement.Metadata != nil {
+		metadata := make(map[string]string, len(measurement.Metadata))
+		for key, value := range measurement.Metadata {
+			metadata[key] = replacer.Replace(value)
+		}
+		measurement.Metadata = metadata
+	}
+	return measurement
+}
+
 // asssessRunStatus assesses the overall status of this AnalysisRun
 // If any metric is not yet completed, the AnalysisRun is still considered Running
 // Once all metrics are complete, the worst status is used as the overall AnalysisRun status
@@ -362,8 +470,13 @@ func calculateNextReconcileTime(run *v1alpha1.AnalysisRun) *time.Time {
 			continue
 		}
 		if lastMeasurement.FinishedAt == nil {
-			// unfinished in-flight measurement.
-			// TODO(jessesuen) perhaps ask provider for an appropriate time to poll?
+			// unfinished in-flight measurement. requeue at the time the provider asked to resume it
+			if lastMeasurement.ResumeAt != nil {
+				if reconcileTime == nil || reconcileTime.After(lastMeasurement.ResumeAt.Time) {
+					resumeTime := lastMeasurement.ResumeAt.Time
+					reconcileTime = &resumeTime
+				}
+			}
 			continue
 		}
 		metricResult := analysisutil.GetResult(run, metric.Name)
@@ -6,10 +6,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/utils/pointer"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
 )
 
 func timePtr(t metav1.Time) *metav1.Time {
@@ -312,6 +315,58 @@ func TestGenerateMetricTasksIncomplete(t *testing.T) {
 	}
 }
 
+func TestGenerateMetricTasksResumeAt(t *testing.T) {
+	future := metav1.NewTime(time.Now().Add(time.Minute))
+	run := &v1alpha1.AnalysisRun{
+		Spec: v1alpha1.AnalysisRunSpec{
+			AnalysisSpec: v1alpha1.AnalysisTemplateSpec{
+				Metrics: []v1alpha1.Metric{
+					{
+						Name: "success-rate",
+					},
+				},
+			},
+		},
+		Status: &v1alpha1.AnalysisRunStatus{
+			Status: v1alpha1.AnalysisStatusRunning,
+			MetricResults: []v1alpha1.MetricResult{
+				{
+					Name:   "success-rate",
+					Status: v1alpha1.AnalysisStatusRunning,
+					Measurements: []v1alpha1.Measurement{
+						{
+							Status:    v1alpha1.AnalysisStatusRunning,
+							StartedAt: timePtr(metav1.NewTime(time.Now().Add(-50 * time.Second))),
+							ResumeAt:  &future,
+						},
+					},
+				},
+			},
+		},
+	}
+	{
+		// ensure we don't resume the measurement before the resume time
+		tasks := generateMetricTasks(run)
+		assert.Equal(t, 0, len(tasks))
+	}
+	{
+		// ensure we resume the measurement once the resume time has passed
+		past := metav1.NewTime(time.Now().Add(-time.Second))
+		run.Status.MetricResults[0].Measurements[0].ResumeAt = &past
+		tasks := generateMetricTasks(run)
+		assert.Equal(t, 1, len(tasks))
+		assert.NotNil(t, tasks[0].incompleteMeasurement)
+	}
+	{
+		// ensure we resume the measurement immediately when terminating
+		run.Status.MetricResults[0].Measurements[0].ResumeAt = &future
+		run.Spec.Terminate = true
+		tasks := generateMetricTasks(run)
+		assert.Equal(t, 1, len(tasks))
+		assert.NotNil(t, tasks[0].incompleteMeasurement)
+	}
+}
+
 func TestGenerateMetricTasksError(t *testing.T) {
 	run := &v1alpha1.AnalysisRun{
 		Spec: v1alpha1.AnalysisRunSpec{
@@ -627,6 +682,10 @@ func TestCalculateNextReconcileTimeInterval(t *testing.T) {
 	run.Status.MetricResults[0].Measurements[0].FinishedAt = nil
 	run.Status.MetricResults[0].Measurements[0].Status = v1alpha1.AnalysisStatusRunning
 	assert.Nil(t, calculateNextReconcileTime(run))
+	// when in-flight measurement should be resumed later, we requeue at the resume time
+	nowPlus30 := metav1.NewTime(now.Add(time.Second * 30))
+	run.Status.MetricResults[0].Measurements[0].ResumeAt = &nowPlus30
+	assert.Equal(t, nowPlus30.Time, *calculateNextReconcileTime(run))
 	// do not queue completed metrics
 	nowMinus120 := metav1.NewTime(now.Add(time.Second * -120))
 	run.Status.MetricResults[0] = v1alpha1.MetricResult{
@@ -813,6 +872,163 @@ func TestReconcileAnalysisRunInitial(t *testing.T) {
 	}
 }
 
+func TestReconcileAnalysisRunResolvesSecretArguments(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "metrics",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string][]byte{
+			"token": []byte("my-token"),
+		},
+	}
+	f.kubeclient = k8sfake.NewSimpleClientset(secret)
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	run := &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1alpha1.AnalysisRunSpec{
+			AnalysisSpec: v1alpha1.AnalysisTemplateSpec{
+				Metrics: []v1alpha1.Metric{
+					{
+						Name:     "success-rate",
+						Interval: pointer.Int32Ptr(60),
+						Provider: v1alpha1.MetricProvider{
+							Prometheus: &v1alpha1.PrometheusMetric{},
+						},
+					},
+				},
+			},
+			Arguments: []v1alpha1.Argument{
+				{Name: "service", Value: "guestbook"},
+				{
+					Name: "token",
+					ValueFrom: &v1alpha1.ValueFrom{
+						SecretKeyRef: &v1alpha1.SecretKeyRef{Name: "metrics", Key: "token"},
+					},
+				},
+			},
+		},
+	}
+	expectedArgs := []v1alpha1.Argument{
+		{Name: "service", Value: "guestbook"},
+		{Name: "token", Value: "my-token"},
+	}
+	f.provider.On("Run", mock.Anything, mock.Anything, expectedArgs).Return(newMeasurement(v1alpha1.AnalysisStatusSuccessful), nil)
+	newRun := c.reconcileAnalysisRun(run)
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, newRun.Status.MetricResults[0].Measurements[0].Status)
+	// the secret value is never stored in the run
+	assert.Equal(t, run.Spec.Arguments, newRun.Spec.Arguments)
+	assert.Equal(t, "", newRun.Spec.Arguments[1].Value)
+
+	run.Spec.Arguments[1].ValueFrom.SecretKeyRef.Key = "missing"
+	newRun = c.reconcileAnalysisRun(run)
+	measurement := newRun.Status.MetricResults[0].Measurements[0]
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "failed to resolve argument 'token': key 'missing' does not exist in secret 'metrics'", measurement.Message)
+}
+
+func TestReconcileAnalysisRunRedactsSecretArguments(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "metrics",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string][]byte{
+			"token": []byte("my/token"),
+		},
+	}
+	f.kubeclient = k8sfake.NewSimpleClientset(secret)
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	run := &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1alpha1.AnalysisRunSpec{
+			AnalysisSpec: v1alpha1.AnalysisTemplateSpec{
+				Metrics: []v1alpha1.Metric{
+					{
+						Name:     "success-rate",
+						Interval: pointer.Int32Ptr(60),
+						Provider: v1alpha1.MetricProvider{
+							Web: &v1alpha1.WebMetric{},
+						},
+					},
+				},
+			},
+			Arguments: []v1alpha1.Argument{
+				{
+					Name: "token",
+					ValueFrom: &v1alpha1.ValueFrom{
+						SecretKeyRef: &v1alpha1.SecretKeyRef{Name: "metrics", Key: "token"},
+					},
+				},
+			},
+		},
+	}
+	measurement := newMeasurement(v1alpha1.AnalysisStatusError)
+	measurement.Message = "Get https://example.com/api?token=my%2Ftoken: dial tcp: connection refused"
+	measurement.Metadata = map[string]string{"header": "Bearer my/token"}
+	f.provider.On("Run", mock.Anything, mock.Anything, mock.Anything).Return(measurement, nil)
+	newRun := c.reconcileAnalysisRun(run)
+	newMeasurement := newRun.Status.MetricResults[0].Measurements[0]
+	assert.Equal(t, "Get https://example.com/api?token=*****: dial tcp: connection refused", newMeasurement.Message)
+	assert.Equal(t, map[string]string{"header": "Bearer *****"}, newMeasurement.Metadata)
+}
+
+func TestRedactSecrets(t *testing.T) {
+	measurement := v1alpha1.Measurement{Message: "token my-token-suffix, prefix my-token"}
+	assert.Equal(t, measurement, redactSecrets(measurement, nil))
+
+	redacted := redactSecrets(measurement, []string{"my-token", "my-token-suffix"})
+	assert.Equal(t, "token *****, prefix *****", redacted.Message)
+	assert.Equal(t, "token my-token-suffix, prefix my-token", measurement.Message)
+}
+
+func TestReconcileAnalysisRunWithUnresolvedArgument(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+	c, _, _ := f.newController(noResyncPeriodFunc)
+	defaultValue := "guestbook"
+	run := &v1alpha1.AnalysisRun{
+		Spec: v1alpha1.AnalysisRunSpec{
+			AnalysisSpec: v1alpha1.AnalysisTemplateSpec{
+				Metrics: []v1alpha1.Metric{
+					{
+						Name:     "success-rate",
+						Interval: pointer.Int32Ptr(60),
+						Provider: v1alpha1.MetricProvider{
+							Prometheus: &v1alpha1.PrometheusMetric{},
+						},
+					},
+				},
+				Args: []v1alpha1.AnalysisTemplateArgument{
+					{Name: "service"},
+					{Name: "app", Default: &defaultValue},
+				},
+			},
+		},
+	}
+	newRun := c.reconcileAnalysisRun(run)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, newRun.Status.Status)
+	assert.Equal(t, "analysis spec invalid: argument 'service' is required but was not passed", newRun.Status.Message)
+
+	run.Spec.Arguments = []v1alpha1.Argument{{Name: "service", Value: "guestbook-svc"}}
+	expectedArgs := []v1alpha1.Argument{
+		{Name: "service", Value: "guestbook-svc"},
+		{Name: "app", Value: "guestbook"},
+	}
+	f.provider.On("Run", mock.Anything, mock.Anything, expectedArgs).Return(newMeasurement(v1alpha1.AnalysisStatusSuccessful), nil)
+	newRun = c.reconcileAnalysisRun(run)
+	assert.Equal(t, v1alpha1.AnalysisStatusRunning, newRun.Status.Status)
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, newRun.Status.MetricResults[0].Measurements[0].Status)
+}
+
 func TestReconcileAnalysisRunInvalid(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -857,6 +1073,69 @@ func TestReconcileAnalysisRunTerminateSiblingAfterFail(t *testing.T) {
 	}
 }
 
+// TestReconcileAnalysisRunFailFast verifies we terminate the in-progress measurements of the other
+// metrics and complete the run as soon as a fail-fast metric fails
+func TestReconcileAnalysisRunFailFast(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+	c, _, _ := f.newController(noResyncPeriodFunc)
+
+	inProgress := v1alpha1.Measurement{
+		Status:    v1alpha1.AnalysisStatusRunning,
+		StartedAt: timePtr(metav1.NewTime(time.Now().Add(-60 * time.Second))),
+	}
+	run := v1alpha1.AnalysisRun{
+		Spec: v1alpha1.AnalysisRunSpec{
+			AnalysisSpec: v1alpha1.AnalysisTemplateSpec{
+				Metrics: []v1alpha1.Metric{
+					{
+						Name: "run-forever",
+						Provider: v1alpha1.MetricProvider{
+							Job: &v1alpha1.JobMetric{},
+						},
+					},
+					{
+						Name:     "failed-metric",
+						FailFast: true,
+						Provider: v1alpha1.MetricProvider{
+							Job: &v1alpha1.JobMetric{},
+						},
+					},
+				},
+			},
+		},
+		Status: &v1alpha1.AnalysisRunStatus{
+			Status: v1alpha1.AnalysisStatusRunning,
+			MetricResults: []v1alpha1.MetricResult{
+				{
+					Name:         "run-forever",
+					Status:       v1alpha1.AnalysisStatusRunning,
+					Measurements: []v1alpha1.Measurement{inProgress},
+				},
+			},
+		},
+	}
+
+	f.provider.On("Run", mock.Anything, mock.Anything, mock.Anything).Return(newMeasurement(v1alpha1.AnalysisStatusFailed), nil)
+	f.provider.On("Resume", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(inProgress, nil)
+	f.provider.On("Terminate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(newMeasurement(v1alpha1.AnalysisStatusSuccessful), nil)
+
+	newRun := c.reconcileAnalysisRun(&run)
+
+	assert.Equal(t, v1alpha1.AnalysisStatusFailed, newRun.Status.Status)
+	assert.Equal(t, v1alpha1.AnalysisStatusFailed, analysisutil.GetResult(newRun, "failed-metric").Status)
+	runForever := analysisutil.GetResult(newRun, "run-forever")
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, runForever.Status)
+	assert.Equal(t, "metric terminated", runForever.Measurements[0].Message)
+	f.provider.AssertCalled(t, "Terminate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// without fail fast, the run keeps running until the next reconciliation
+	run.Spec.AnalysisSpec.Metrics[1].FailFast = false
+	newRun = c.reconcileAnalysisRun(&run)
+	assert.Equal(t, v1alpha1.AnalysisStatusRunning, newRun.Status.Status)
+	assert.Equal(t, v1alpha1.AnalysisStatusRunning, analysisutil.GetResult(newRun, "run-forever").Status)
+}
+
 func TestReconcileAnalysisRunResumeInProgress(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/cmd"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+func main() {
+	o := options.NewArgoRolloutsOptions()
+	root := cmd.NewCmdArgoRollouts(o)
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -18,10 +19,13 @@ import (
 	"k8s.io/klog"
 
 	"github.com/argoproj/argo-rollouts/controller"
+	"github.com/argoproj/argo-rollouts/controller/webhook"
 	jobprovider "github.com/argoproj/argo-rollouts/metricproviders/job"
 	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
 	informers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions"
+	rolloutinformers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/pkg/signals"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
 )
 
 const (
@@ -40,6 +44,13 @@ func newCommand() *cobra.Command {
 		experimentThreads   int
 		analysisThreads     int
 		serviceThreads      int
+		notificationThreads int
+		smiAPIVersion       string
+		electOpts           = controller.NewLeaderElectionOptions()
+		webhookPort         int
+		webhookCertFile     string
+		webhookKeyFile      string
+		webhookRefWarnings  bool
 	)
 	var command = cobra.Command{
 		Use:   cliName,
@@ -51,6 +62,7 @@ func newCommand() *cobra.Command {
 			}
 			log.SetFormatter(formatter)
 			setGLogLevel(glogLevel)
+			defaults.SetSMIAPIVersion(smiAPIVersion)
 
 			// set up signals so we handle the first shutdown signal gracefully
 			stopCh := signals.SetupSignalHandler()
@@ -70,6 +82,8 @@ func newCommand() *cobra.Command {
 			checkError(err)
 			rolloutClient, err := clientset.NewForConfig(config)
 			checkError(err)
+			dynamicClient, err := dynamic.NewForConfig(config)
+			checkError(err)
 			resyncDuration := time.Duration(rolloutResyncPeriod) * time.Second
 			kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(
 				kubeClient,
@@ -85,24 +99,56 @@ func newCommand() *cobra.Command {
 				kubeinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
 					options.LabelSelector = jobprovider.AnalysisRunLabelKey
 				}))
-			cm := controller.NewManager(kubeClient, rolloutClient,
+			// The notification ConfigMap and Secret are read from the namespace of the controller
+			notificationInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(
+				kubeClient,
+				resyncDuration,
+				kubeinformers.WithNamespace(defaults.Namespace()))
+			// A controller limited to a namespace is not allowed to watch the cluster-scoped ClusterAnalysisTemplates
+			var clusterAnalysisTemplateInformer rolloutinformers.ClusterAnalysisTemplateInformer
+			if namespace == metav1.NamespaceAll {
+				clusterAnalysisTemplateInformer = argoRolloutsInformerFactory.Argoproj().V1alpha1().ClusterAnalysisTemplates()
+			}
+			cm := controller.NewManager(kubeClient, rolloutClient, dynamicClient,
 				kubeInformerFactory.Apps().V1().ReplicaSets(),
+				kubeInformerFactory.Apps().V1().Deployments(),
 				kubeInformerFactory.Core().V1().Services(),
 				jobInformerFactory.Batch().V1().Jobs(),
 				argoRolloutsInformerFactory.Argoproj().V1alpha1().Rollouts(),
 				argoRolloutsInformerFactory.Argoproj().V1alpha1().Experiments(),
 				argoRolloutsInformerFactory.Argoproj().V1alpha1().AnalysisRuns(),
 				argoRolloutsInformerFactory.Argoproj().V1alpha1().AnalysisTemplates(),
+				clusterAnalysisTemplateInformer,
+				notificationInformerFactory.Core().V1().ConfigMaps(),
+				notificationInformerFactory.Core().V1().Secrets(),
 				resyncDuration,
 				metricsPort)
 
+			var webhookServer *webhook.WebhookServer
+			if webhookPort > 0 {
+				webhookServer = webhook.NewWebhookServer(fmt.Sprintf("0.0.0.0:%d", webhookPort),
+					kubeInformerFactory.Core().V1().Services(),
+					argoRolloutsInformerFactory.Argoproj().V1alpha1().AnalysisTemplates(),
+					clusterAnalysisTemplateInformer,
+					webhookRefWarnings)
+			}
+
 			// notice that there is no need to run Start methods in a separate goroutine. (i.e. go kubeInformerFactory.Start(stopCh)
 			// Start method is non-blocking and runs all registered informers in a dedicated goroutine.
 			kubeInformerFactory.Start(stopCh)
 			argoRolloutsInformerFactory.Start(stopCh)
 			jobInformerFactory.Start(stopCh)
+			notificationInformerFactory.Start(stopCh)
+
+			if webhookServer != nil {
+				go func() {
+					if err := webhookServer.Run(webhookCertFile, webhookKeyFile, stopCh); err != nil {
+						log.Fatalf("Error running webhook server: %s", err.Error())
+					}
+				}()
+			}
 
-			if err = cm.Run(rolloutThreads, serviceThreads, experimentThreads, analysisThreads, stopCh); err != nil {
+			if err = cm.Run(rolloutThreads, serviceThreads, experimentThreads, analysisThreads, notificationThreads, electOpts, stopCh); err != nil {
 				log.Fatalf("Error running controller: %s", err.Error())
 			}
 			return nil
@@ -117,6 +163,18 @@ func newCommand() *cobra.Command {
 	command.Flags().IntVar(&experimentThreads, "experiment-threads", controller.DefaultExperimentThreads, "Set the number of worker threads for the Experiment controller")
 	command.Flags().IntVar(&analysisThreads, "analysis-threads", controller.DefaultAnalysisThreads, "Set the number of worker threads for the Experiment controller")
 	command.Flags().IntVar(&serviceThreads, "service-threads", controller.DefaultServiceThreads, "Set the number of worker threads for the Service controller")
+	command.Flags().IntVar(&notificationThreads, "notification-threads", controller.DefaultNotificationThreads, "Set the number of worker threads for the Notification controller")
+	command.Flags().StringVar(&smiAPIVersion, "traffic-split-api-version", defaults.DefaultSMITrafficSplitVersion, "Set the default version of the SMI TrafficSplit resource. One of: v1alpha1|v1alpha2")
+	command.Flags().IntVar(&webhookPort, "webhook-port", 0, "Set the port the validating admission webhook should be served over. The webhook is disabled when set to 0")
+	command.Flags().StringVar(&webhookCertFile, "webhook-tls-cert-file", "", "Path to the TLS certificate of the validating admission webhook")
+	command.Flags().StringVar(&webhookKeyFile, "webhook-tls-key-file", "", "Path to the TLS private key of the validating admission webhook")
+	command.Flags().BoolVar(&webhookRefWarnings, "webhook-reference-warnings", false, "If true, admit resources referencing missing Services or AnalysisTemplates and only log a warning")
+	command.Flags().BoolVar(&electOpts.LeaderElect, "leader-elect", electOpts.LeaderElect, "If true, elect a leader among the controller replicas and only run the workers on the leader")
+	command.Flags().StringVar(&electOpts.Namespace, "leader-election-namespace", electOpts.Namespace, "Set the namespace of the leader election lock")
+	command.Flags().StringVar(&electOpts.ResourceLock, "leader-election-resource-lock", electOpts.ResourceLock, "Set the type of resource used as the leader election lock. One of: leases|configmaps")
+	command.Flags().DurationVar(&electOpts.LeaseDuration, "leader-election-lease-duration", electOpts.LeaseDuration, "Set the duration non-leader candidates wait before trying to acquire the leader lease")
+	command.Flags().DurationVar(&electOpts.RenewDeadline, "leader-election-renew-deadline", electOpts.RenewDeadline, "Set the duration the leader retries renewing the lease before giving it up")
+	command.Flags().DurationVar(&electOpts.RetryPeriod, "leader-election-retry-period", electOpts.RetryPeriod, "Set the duration between the attempts to acquire or renew the leader lease")
 	return &command
 }
 
@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/pkg/errors"
@@ -9,7 +11,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	appsinformers "k8s.io/client-go/informers/apps/v1"
 	batchinformers "k8s.io/client-go/informers/batch/v1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
@@ -17,17 +21,21 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"github.com/argoproj/argo-rollouts/analysis"
 	"github.com/argoproj/argo-rollouts/controller/metrics"
 	"github.com/argoproj/argo-rollouts/experiments"
+	"github.com/argoproj/argo-rollouts/notifications"
 	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
 	rolloutscheme "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/scheme"
 	informers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/rollout"
 	"github.com/argoproj/argo-rollouts/service"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
 )
 
 const controllerAgentName = "rollouts-controller"
@@ -50,41 +58,104 @@ const (
 
 	// DefaultServiceThreads Default number of service worker threads to start with the controller
 	DefaultServiceThreads = 10
+
+	// DefaultNotificationThreads Default number of notification worker threads to start with the controller
+	DefaultNotificationThreads = 5
+
+	// DefaultLeaderElect Default value of whether to use leader election to run a single active controller. It is off
+	// by default so a controller installed without the RBAC rules of the lock keeps running.
+	DefaultLeaderElect = false
+
+	// DefaultLeaderElectionLockName Default name of the resource used as the leader election lock
+	DefaultLeaderElectionLockName = "argo-rollouts-controller-lock"
+
+	// DefaultLeaderElectionResourceLock Default type of the resource used as the leader election lock
+	DefaultLeaderElectionResourceLock = resourcelock.LeasesResourceLock
+
+	// DefaultLeaderElectionLeaseDuration Default duration non-leader candidates wait before trying to acquire the lease
+	DefaultLeaderElectionLeaseDuration = 15 * time.Second
+
+	// DefaultLeaderElectionRenewDeadline Default duration the leader retries renewing the lease before giving it up
+	DefaultLeaderElectionRenewDeadline = 10 * time.Second
+
+	// DefaultLeaderElectionRetryPeriod Default duration between the attempts to acquire or renew the lease
+	DefaultLeaderElectionRetryPeriod = 2 * time.Second
 )
 
+// LeaderElectionOptions configures the leader election of the controller
+type LeaderElectionOptions struct {
+	// LeaderElect enables the leader election. Only the leader runs the controllers' workers.
+	LeaderElect bool
+	// Namespace is the namespace of the leader election lock
+	Namespace string
+	// ResourceLock is the type of the resource used as the leader election lock (leases or configmaps)
+	ResourceLock string
+	// LeaseDuration is the duration non-leader candidates wait before trying to acquire the lease
+	LeaseDuration time.Duration
+	// RenewDeadline is the duration the leader retries renewing the lease before giving it up
+	RenewDeadline time.Duration
+	// RetryPeriod is the duration between the attempts to acquire or renew the lease
+	RetryPeriod time.Duration
+}
+
+// NewLeaderElectionOptions returns the default leader election options
+func NewLeaderElectionOptions() *LeaderElectionOptions {
+	return &LeaderElectionOptions{
+		LeaderElect:   DefaultLeaderElect,
+		Namespace:     defaults.Namespace(),
+		ResourceLock:  DefaultLeaderElectionResourceLock,
+		LeaseDuration: DefaultLeaderElectionLeaseDuration,
+		RenewDeadline: DefaultLeaderElectionRenewDeadline,
+		RetryPeriod:   DefaultLeaderElectionRetryPeriod,
+	}
+}
+
 // Manager is the controller implementation for Argo-Rollout resources
 type Manager struct {
-	metricsServer        *metrics.MetricsServer
-	rolloutController    *rollout.RolloutController
-	experimentController *experiments.ExperimentController
-	analysisController   *analysis.AnalysisController
-	serviceController    *service.ServiceController
-
-	rolloutSynced          cache.InformerSynced
-	experimentSynced       cache.InformerSynced
-	analysisRunSynced      cache.InformerSynced
-	analysisTemplateSynced cache.InformerSynced
-	serviceSynced          cache.InformerSynced
-	jobSynced              cache.InformerSynced
-	replicasSetSynced      cache.InformerSynced
-
-	rolloutWorkqueue     workqueue.RateLimitingInterface
-	serviceWorkqueue     workqueue.RateLimitingInterface
-	experimentWorkqueue  workqueue.RateLimitingInterface
-	analysisRunWorkqueue workqueue.RateLimitingInterface
+	kubeClientSet          kubernetes.Interface
+	recorder               record.EventRecorder
+	metricsServer          *metrics.MetricsServer
+	rolloutController      *rollout.RolloutController
+	experimentController   *experiments.ExperimentController
+	analysisController     *analysis.AnalysisController
+	serviceController      *service.ServiceController
+	notificationController *notifications.NotificationController
+
+	rolloutSynced                 cache.InformerSynced
+	experimentSynced              cache.InformerSynced
+	analysisRunSynced             cache.InformerSynced
+	analysisTemplateSynced        cache.InformerSynced
+	clusterAnalysisTemplateSynced cache.InformerSynced
+	serviceSynced                 cache.InformerSynced
+	jobSynced                     cache.InformerSynced
+	replicasSetSynced             cache.InformerSynced
+	deploymentSynced              cache.InformerSynced
+	configMapSynced               cache.InformerSynced
+	secretSynced                  cache.InformerSynced
+
+	rolloutWorkqueue      workqueue.RateLimitingInterface
+	serviceWorkqueue      workqueue.RateLimitingInterface
+	experimentWorkqueue   workqueue.RateLimitingInterface
+	analysisRunWorkqueue  workqueue.RateLimitingInterface
+	notificationWorkqueue workqueue.RateLimitingInterface
 }
 
 // NewManager returns a new manager to manage all the controllers
 func NewManager(
 	kubeclientset kubernetes.Interface,
 	argoprojclientset clientset.Interface,
+	dynamicclientset dynamic.Interface,
 	replicaSetInformer appsinformers.ReplicaSetInformer,
+	deploymentInformer appsinformers.DeploymentInformer,
 	servicesInformer coreinformers.ServiceInformer,
 	jobInformer batchinformers.JobInformer,
 	rolloutsInformer informers.RolloutInformer,
 	experimentsInformer informers.ExperimentInformer,
 	analysisRunInformer informers.AnalysisRunInformer,
 	analysisTemplateInformer informers.AnalysisTemplateInformer,
+	clusterAnalysisTemplateInformer informers.ClusterAnalysisTemplateInformer,
+	configMapInformer coreinformers.ConfigMapInformer,
+	secretInformer coreinformers.SecretInformer,
 	resyncPeriod time.Duration,
 	metricsPort int,
 ) *Manager {
@@ -105,16 +176,20 @@ func NewManager(
 	experimentWorkqueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Experiments")
 	analysisRunWorkqueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "AnalysisRuns")
 	serviceWorkqueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Services")
+	notificationWorkqueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Notifications")
 
 	metricsServer := metrics.NewMetricsServer(metricsAddr, rolloutsInformer.Lister())
 
 	rolloutController := rollout.NewRolloutController(
 		kubeclientset,
 		argoprojclientset,
+		dynamicclientset,
 		experimentsInformer,
 		analysisRunInformer,
 		analysisTemplateInformer,
+		clusterAnalysisTemplateInformer,
 		replicaSetInformer,
+		deploymentInformer,
 		servicesInformer,
 		rolloutsInformer,
 		resyncPeriod,
@@ -129,6 +204,8 @@ func NewManager(
 		replicaSetInformer,
 		rolloutsInformer,
 		experimentsInformer,
+		analysisTemplateInformer,
+		analysisRunInformer,
 		resyncPeriod,
 		rolloutWorkqueue,
 		experimentWorkqueue,
@@ -154,42 +231,145 @@ func NewManager(
 		serviceWorkqueue,
 		metricsServer)
 
+	// The informer is nil when the controller is limited to a namespace and cannot watch the ClusterAnalysisTemplates
+	clusterAnalysisTemplateSynced := func() bool { return true }
+	if clusterAnalysisTemplateInformer != nil {
+		clusterAnalysisTemplateSynced = clusterAnalysisTemplateInformer.Informer().HasSynced
+	}
+
+	notificationController := notifications.NewNotificationController(
+		argoprojclientset,
+		rolloutsInformer,
+		analysisRunInformer,
+		configMapInformer,
+		secretInformer,
+		defaults.Namespace(),
+		notificationWorkqueue,
+		metricsServer,
+		recorder)
+
 	cm := &Manager{
-		metricsServer:          metricsServer,
-		rolloutSynced:          rolloutsInformer.Informer().HasSynced,
-		serviceSynced:          servicesInformer.Informer().HasSynced,
-		jobSynced:              jobInformer.Informer().HasSynced,
-		experimentSynced:       experimentsInformer.Informer().HasSynced,
-		analysisRunSynced:      analysisRunInformer.Informer().HasSynced,
-		analysisTemplateSynced: analysisTemplateInformer.Informer().HasSynced,
-		replicasSetSynced:      replicaSetInformer.Informer().HasSynced,
-		rolloutWorkqueue:       rolloutWorkqueue,
-		experimentWorkqueue:    experimentWorkqueue,
-		analysisRunWorkqueue:   analysisRunWorkqueue,
-		serviceWorkqueue:       serviceWorkqueue,
-		rolloutController:      rolloutController,
-		serviceController:      serviceController,
-		experimentController:   experimentController,
-		analysisController:     analysisController,
+		kubeClientSet:                 kubeclientset,
+		recorder:                      recorder,
+		metricsServer:                 metricsServer,
+		rolloutSynced:                 rolloutsInformer.Informer().HasSynced,
+		serviceSynced:                 servicesInformer.Informer().HasSynced,
+		jobSynced:                     jobInformer.Informer().HasSynced,
+		experimentSynced:              experimentsInformer.Informer().HasSynced,
+		analysisRunSynced:             analysisRunInformer.Informer().HasSynced,
+		analysisTemplateSynced:        analysisTemplateInformer.Informer().HasSynced,
+		clusterAnalysisTemplateSynced: clusterAnalysisTemplateSynced,
+		replicasSetSynced:             replicaSetInformer.Informer().HasSynced,
+		deploymentSynced:              deploymentInformer.Informer().HasSynced,
+		configMapSynced:               configMapInformer.Informer().HasSynced,
+		secretSynced:                  secretInformer.Informer().HasSynced,
+		rolloutWorkqueue:              rolloutWorkqueue,
+		experimentWorkqueue:           experimentWorkqueue,
+		analysisRunWorkqueue:          analysisRunWorkqueue,
+		serviceWorkqueue:              serviceWorkqueue,
+		notificationWorkqueue:         notificationWorkqueue,
+		rolloutController:             rolloutController,
+		serviceController:             serviceController,
+		experimentController:          experimentController,
+		analysisController:            analysisController,
+		notificationController:        notificationController,
 	}
 
 	return cm
 }
 
 // Run will set up the event handlers for types we are interested in, as well
-// as syncing informer caches and starting workers. It will block until stopCh
-// is closed, at which point it will shutdown the workqueue and wait for
-// workers to finish processing their current work items.
-func (c *Manager) Run(rolloutThreadiness, serviceThreadiness, experimentThreadiness, analysisThreadiness int, stopCh <-chan struct{}) error {
-
+// as syncing informer caches and starting workers. When leader election is
+// enabled, the workers are only started once the lease is acquired. It will
+// block until stopCh is closed, at which point it will shutdown the workqueue
+// and wait for workers to finish processing their current work items.
+func (c *Manager) Run(rolloutThreadiness, serviceThreadiness, experimentThreadiness, analysisThreadiness, notificationThreadiness int, electOpts *LeaderElectionOptions, stopCh <-chan struct{}) error {
 	defer runtime.HandleCrash()
 	defer c.serviceWorkqueue.ShutDown()
 	defer c.rolloutWorkqueue.ShutDown()
 	defer c.experimentWorkqueue.ShutDown()
 	defer c.analysisRunWorkqueue.ShutDown()
+	defer c.notificationWorkqueue.ShutDown()
+
+	go func() {
+		log.Infof("Starting Metric Server at %s", c.metricsServer.Addr)
+		err := c.metricsServer.ListenAndServe()
+		if err != nil {
+			err = errors.Wrap(err, "Starting Metric Server")
+			log.Fatal(err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	if !electOpts.LeaderElect {
+		log.Info("Leader election is turned off. Running in single-instance mode")
+		return c.startLeading(ctx, rolloutThreadiness, serviceThreadiness, experimentThreadiness, analysisThreadiness, notificationThreadiness)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s_%s", hostname, uuid.NewUUID())
+	lock, err := resourcelock.New(
+		electOpts.ResourceLock,
+		electOpts.Namespace,
+		DefaultLeaderElectionLockName,
+		c.kubeClientSet.CoreV1(),
+		c.kubeClientSet.CoordinationV1(),
+		resourcelock.ResourceLockConfig{
+			Identity:      id,
+			EventRecorder: c.recorder,
+		})
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Waiting to acquire the leader lease %s/%s as %s", electOpts.Namespace, DefaultLeaderElectionLockName, id)
+	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		ReleaseOnCancel: true,
+		LeaseDuration:   electOpts.LeaseDuration,
+		RenewDeadline:   electOpts.RenewDeadline,
+		RetryPeriod:     electOpts.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				log.Infof("Acquired the leader lease as %s", id)
+				if err := c.startLeading(ctx, rolloutThreadiness, serviceThreadiness, experimentThreadiness, analysisThreadiness, notificationThreadiness); err != nil {
+					log.Fatalf("Error running controller: %s", err.Error())
+				}
+			},
+			OnStoppedLeading: func() {
+				select {
+				case <-stopCh:
+					log.Infof("Stopped leader election as %s", id)
+				default:
+					// The workers might still be processing items, so the process exits to let a standby take over
+					log.Fatalf("Lost the leader lease as %s", id)
+				}
+			},
+			OnNewLeader: func(identity string) {
+				if identity != id {
+					log.Infof("New leader elected: %s", identity)
+				}
+			},
+		},
+	})
+	return nil
+}
+
+// startLeading syncs the informer caches and starts the controllers' workers. It will block until ctx is done.
+func (c *Manager) startLeading(ctx context.Context, rolloutThreadiness, serviceThreadiness, experimentThreadiness, analysisThreadiness, notificationThreadiness int) error {
+	stopCh := ctx.Done()
 	// Wait for the caches to be synced before starting workers
 	log.Info("Waiting for controller's informer caches to sync")
-	if ok := cache.WaitForCacheSync(stopCh, c.serviceSynced, c.jobSynced, c.rolloutSynced, c.experimentSynced, c.analysisRunSynced, c.analysisTemplateSynced, c.replicasSetSynced); !ok {
+	if ok := cache.WaitForCacheSync(stopCh, c.serviceSynced, c.jobSynced, c.rolloutSynced, c.experimentSynced, c.analysisRunSynced, c.analysisTemplateSynced, c.clusterAnalysisTemplateSynced, c.replicasSetSynced, c.deploymentSynced, c.configMapSynced, c.secretSynced); !ok {
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
@@ -199,16 +379,9 @@ func (c *Manager) Run(rolloutThreadiness, serviceThreadiness, experimentThreadin
 	go wait.Until(func() { c.serviceController.Run(serviceThreadiness, stopCh) }, time.Second, stopCh)
 	go wait.Until(func() { c.experimentController.Run(experimentThreadiness, stopCh) }, time.Second, stopCh)
 	go wait.Until(func() { c.analysisController.Run(analysisThreadiness, stopCh) }, time.Second, stopCh)
+	go wait.Until(func() { c.notificationController.Run(notificationThreadiness, stopCh) }, time.Second, stopCh)
 	log.Info("Started controller")
 
-	go func() {
-		log.Infof("Starting Metric Server at %s", c.metricsServer.Addr)
-		err := c.metricsServer.ListenAndServe()
-		if err != nil {
-			err = errors.Wrap(err, "Starting Metric Server")
-			log.Fatal(err)
-		}
-	}()
 	<-stopCh
 	log.Info("Shutting down workers")
 
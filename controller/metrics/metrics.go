@@ -36,6 +36,9 @@ var (
 
 	descRolloutReconcilePhaseLabels = append(descRolloutWithStrategyLabels, "phase")
 
+	// A new slice, since appending to descRolloutWithStrategyLabels again would overwrite the phase label
+	descRolloutPauseReasonLabels = []string{"namespace", "name", "strategy", "reason"}
+
 	descRolloutInfo = prometheus.NewDesc(
 		"rollout_info",
 		"Information about rollout.",
@@ -56,8 +59,23 @@ var (
 		descRolloutReconcilePhaseLabels,
 		nil,
 	)
+
+	descRolloutPauseConditions = prometheus.NewDesc(
+		"rollout_pause_condition",
+		"Information on the reasons why the rollout is paused",
+		descRolloutPauseReasonLabels,
+		nil,
+	)
 )
 
+// pauseReasons the reasons reported by the rollout_pause_condition metric
+var pauseReasons = []v1alpha1.PauseReason{
+	v1alpha1.PauseReasonUser,
+	v1alpha1.PauseReasonCanaryPauseStep,
+	v1alpha1.PauseReasonBlueGreenPause,
+	v1alpha1.PauseReasonInconclusiveAnalysis,
+}
+
 // RolloutPhase the phases of a reconcile can have
 type RolloutPhase string
 
@@ -124,6 +142,16 @@ func (m *MetricsServer) IncError(namespace, name string) {
 	m.errorCounter.WithLabelValues(namespace, name).Inc()
 }
 
+// hasPauseCondition returns true if the rollout is paused for the given reason
+func hasPauseCondition(rollout *v1alpha1.Rollout, reason v1alpha1.PauseReason) bool {
+	for _, cond := range rollout.Status.PauseConditions {
+		if cond.Reason == reason {
+			return true
+		}
+	}
+	return false
+}
+
 // calculatePhase calculates where a Rollout is in a Completed, Paused, Error, Timeout, or InvalidSpec phase
 func calculatePhase(rollout *v1alpha1.Rollout) RolloutPhase {
 	phase := Progressing
@@ -142,6 +170,9 @@ func calculatePhase(rollout *v1alpha1.Rollout) RolloutPhase {
 			phase = Timeout
 		}
 	}
+	if phase == Progressing && len(rollout.Status.PauseConditions) > 0 {
+		phase = Paused
+	}
 	invalidSpec := conditions.GetRolloutCondition(rollout.Status, v1alpha1.InvalidSpec)
 	if invalidSpec != nil {
 		phase = InvalidSpec
@@ -173,6 +204,7 @@ func NewRolloutRegistry(rolloutLister rolloutlister.RolloutLister) *prometheus.R
 func (c *rolloutCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- descRolloutInfo
 	ch <- descRolloutCreated
+	ch <- descRolloutPauseConditions
 }
 
 // Collect implements the prometheus.Collector interface
@@ -215,4 +247,8 @@ func collectRollouts(ch chan<- prometheus.Metric, rollout *v1alpha1.Rollout) {
 	addGauge(descRolloutPhaseLabels, boolFloat64(calculatedPhase == Timeout), string(Timeout))
 	addGauge(descRolloutPhaseLabels, boolFloat64(calculatedPhase == Error), string(Error))
 	addGauge(descRolloutPhaseLabels, boolFloat64(calculatedPhase == InvalidSpec), string(InvalidSpec))
+
+	for _, reason := range pauseReasons {
+		addGauge(descRolloutPauseConditions, boolFloat64(hasPauseCondition(rollout, reason)), string(reason))
+	}
 }
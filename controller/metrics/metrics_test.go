@@ -118,3 +118,47 @@ func TestMetrics(t *testing.T) {
 		testRolloutDescribe(t, combination.rollout, combination.expectedResponse)
 	}
 }
+
+const fakePausedRollout = `
+apiVersion: argoproj.io/v1alpha1
+kind: Rollout
+metadata:
+  name: guestbook-canary
+  namespace: default
+spec:
+  paused: true
+  replicas: 1
+  selector:
+    matchLabels:
+      app: guestbook
+  template:
+    metadata:
+      labels:
+        app: guestbook
+    spec:
+      containers:
+      - name: guestbook
+        image: gcr.io/heptio-images/ks-guestbook-demo:0.1
+  strategy:
+    canary:
+      steps:
+      - setWeight: 20
+      - pause: {}
+status:
+  currentStepIndex: 1
+  pauseStartTime: "2020-03-01T00:00:00Z"
+  pauseConditions:
+  - reason: CanaryPauseStep
+    startTime: "2020-03-01T00:00:00Z"
+`
+
+const expectedPausedResponse = `rollout_pause_condition{name="guestbook-canary",namespace="default",reason="BlueGreenPause",strategy="canary"} 0
+rollout_pause_condition{name="guestbook-canary",namespace="default",reason="CanaryPauseStep",strategy="canary"} 1
+rollout_pause_condition{name="guestbook-canary",namespace="default",reason="InconclusiveAnalysisRun",strategy="canary"} 0
+rollout_pause_condition{name="guestbook-canary",namespace="default",reason="UserPause",strategy="canary"} 0
+rollout_phase{name="guestbook-canary",namespace="default",phase="Paused",strategy="canary"} 1
+rollout_phase{name="guestbook-canary",namespace="default",phase="Progressing",strategy="canary"} 0`
+
+func TestMetricsPauseConditions(t *testing.T) {
+	testRolloutDescribe(t, fakePausedRollout, expectedPausedResponse)
+}
@@ -0,0 +1,344 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	informers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
+	rolloutlisters "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
+	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+)
+
+const (
+	// ValidatePath is the endpoint the API server sends the AdmissionReviews to
+	ValidatePath = "/validate"
+
+	// ReferenceWarningAnnotationKey is the audit annotation recording the invalid references of an admitted resource
+	ReferenceWarningAnnotationKey = "argo-rollouts-reference-warning"
+)
+
+// WebhookServer serves a validating admission webhook for the Rollout, Experiment, AnalysisTemplate and
+// ClusterAnalysisTemplate resources
+type WebhookServer struct {
+	*http.Server
+	servicesLister                corev1listers.ServiceLister
+	analysisTemplateLister        rolloutlisters.AnalysisTemplateLister
+	clusterAnalysisTemplateLister rolloutlisters.ClusterAnalysisTemplateLister
+	cacheSynced                   []cache.InformerSynced
+	// referenceWarnings admits the resources referencing missing objects instead of rejecting them
+	referenceWarnings bool
+}
+
+// NewWebhookServer returns a new webhook server which validates the resources submitted to the API server
+func NewWebhookServer(
+	addr string,
+	servicesInformer coreinformers.ServiceInformer,
+	analysisTemplateInformer informers.AnalysisTemplateInformer,
+	clusterAnalysisTemplateInformer informers.ClusterAnalysisTemplateInformer,
+	referenceWarnings bool) *WebhookServer {
+
+	webhook := &WebhookServer{
+		servicesLister:         servicesInformer.Lister(),
+		analysisTemplateLister: analysisTemplateInformer.Lister(),
+		cacheSynced: []cache.InformerSynced{
+			servicesInformer.Informer().HasSynced,
+			analysisTemplateInformer.Informer().HasSynced,
+		},
+		referenceWarnings: referenceWarnings,
+	}
+	// The informer is nil when the controller is limited to a namespace and cannot watch the ClusterAnalysisTemplates
+	if clusterAnalysisTemplateInformer != nil {
+		webhook.clusterAnalysisTemplateLister = clusterAnalysisTemplateInformer.Lister()
+		webhook.cacheSynced = append(webhook.cacheSynced, clusterAnalysisTemplateInformer.Informer().HasSynced)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(ValidatePath, webhook.serveValidate)
+	webhook.Server = &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+	return webhook
+}
+
+// Run waits for the informer caches to sync and serves the webhook over TLS. It will block until stopCh is closed.
+func (w *WebhookServer) Run(certFile, keyFile string, stopCh <-chan struct{}) error {
+	log.Info("Waiting for webhook's informer caches to sync")
+	if ok := cache.WaitForCacheSync(stopCh, w.cacheSynced...); !ok {
+		return fmt.Errorf("failed to wait for caches to sync")
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Infof("Starting Webhook Server at %s", w.Addr)
+		errCh <- w.ListenAndServeTLS(certFile, keyFile)
+	}()
+	select {
+	case err := <-errCh:
+		return err
+	case <-stopCh:
+		log.Info("Shutting down Webhook Server")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return w.Shutdown(ctx)
+	}
+}
+
+func (w *WebhookServer) serveValidate(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, fmt.Sprintf("method %s not allowed", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	review := admissionv1beta1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil {
+		http.Error(rw, fmt.Sprintf("failed to decode AdmissionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(rw, "AdmissionReview has no request", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = w.validate(review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+	resp, err := json.Marshal(review)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if _, err := rw.Write(resp); err != nil {
+		log.Errorf("Failed to write AdmissionReview response: %v", err)
+	}
+}
+
+// validate admits or rejects the object of the request. Invalid specs are always rejected, while invalid references
+// to other objects are only recorded as an audit annotation when the webhook is configured with reference warnings.
+func (w *WebhookServer) validate(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	if req.Operation == admissionv1beta1.Delete || req.Object.Raw == nil {
+		return allowed()
+	}
+	// The resources have no status subresource, so the updates of the status and the metadata made by the controller
+	// and the CLI are admitted even if the spec admitted earlier is now invalid, e.g. a referenced Service was deleted
+	if req.Operation == admissionv1beta1.Update && !specChanged(req) {
+		return allowed()
+	}
+	logCtx := log.WithField("kind", req.Kind.Kind).WithField("namespace", req.Namespace).WithField("name", req.Name)
+
+	var specErr, referenceErr error
+	switch req.Kind.Kind {
+	case "Rollout":
+		rollout := &v1alpha1.Rollout{}
+		if err := json.Unmarshal(req.Object.Raw, rollout); err != nil {
+			return denied(err)
+		}
+		if rollout.Namespace == "" {
+			rollout.Namespace = req.Namespace
+		}
+		specErr = validateRolloutSpec(rollout)
+		if specErr == nil {
+			referenceErr = w.validateRolloutReferences(rollout)
+		}
+	case "Experiment":
+		experiment := &v1alpha1.Experiment{}
+		if err := json.Unmarshal(req.Object.Raw, experiment); err != nil {
+			return denied(err)
+		}
+		if experiment.Namespace == "" {
+			experiment.Namespace = req.Namespace
+		}
+		specErr = validateExperimentSpec(experiment)
+		if specErr == nil {
+			referenceErr = w.validateExperimentReferences(experiment)
+		}
+	case "AnalysisTemplate":
+		template := &v1alpha1.AnalysisTemplate{}
+		if err := json.Unmarshal(req.Object.Raw, template); err != nil {
+			return denied(err)
+		}
+		specErr = analysisutil.ValidateAnalysisTemplateSpec(template.Spec)
+	case "ClusterAnalysisTemplate":
+		template := &v1alpha1.ClusterAnalysisTemplate{}
+		if err := json.Unmarshal(req.Object.Raw, template); err != nil {
+			return denied(err)
+		}
+		specErr = analysisutil.ValidateAnalysisTemplateSpec(template.Spec)
+	default:
+		return allowed()
+	}
+
+	if specErr != nil {
+		logCtx.Infof("Rejected invalid spec: %v", specErr)
+		return denied(specErr)
+	}
+	if referenceErr != nil {
+		if !w.referenceWarnings {
+			logCtx.Infof("Rejected invalid reference: %v", referenceErr)
+			return denied(referenceErr)
+		}
+		logCtx.Warnf("Admitted with invalid reference: %v", referenceErr)
+		resp := allowed()
+		resp.AuditAnnotations = map[string]string{
+			ReferenceWarningAnnotationKey: referenceErr.Error(),
+		}
+		return resp
+	}
+	return allowed()
+}
+
+// specChanged returns whether the spec of the object of an update differs from the spec of the old object
+func specChanged(req *admissionv1beta1.AdmissionRequest) bool {
+	if req.OldObject.Raw == nil {
+		return true
+	}
+	var obj, oldObj struct {
+		Spec interface{} `json:"spec"`
+	}
+	if err := json.Unmarshal(req.Object.Raw, &obj); err != nil {
+		return true
+	}
+	if err := json.Unmarshal(req.OldObject.Raw, &oldObj); err != nil {
+		return true
+	}
+	return !reflect.DeepEqual(obj.Spec, oldObj.Spec)
+}
+
+func validateRolloutSpec(rollout *v1alpha1.Rollout) error {
+	if cond := conditions.VerifyRolloutSpec(rollout, nil); cond != nil {
+		return errors.New(cond.Message)
+	}
+	return nil
+}
+
+func validateExperimentSpec(experiment *v1alpha1.Experiment) error {
+	if cond := conditions.VerifyExperimentSpec(experiment, nil); cond != nil {
+		return errors.New(cond.Message)
+	}
+	return nil
+}
+
+// validateRolloutReferences verifies that the Services and the AnalysisTemplates referenced by the rollout exist
+func (w *WebhookServer) validateRolloutReferences(rollout *v1alpha1.Rollout) error {
+	var services []string
+	if blueGreen := rollout.Spec.Strategy.BlueGreenStrategy; blueGreen != nil {
+		services = append(services, blueGreen.ActiveService, blueGreen.PreviewService)
+	}
+	if canary := rollout.Spec.Strategy.CanaryStrategy; canary != nil {
+		services = append(services, canary.StableService, canary.CanaryService)
+		for _, step := range canary.Steps {
+			if step.Experiment == nil {
+				continue
+			}
+			for _, analysis := range step.Experiment.Analyses {
+				if err := w.validateAnalysisTemplateReference(rollout.Namespace, v1alpha1.RolloutAnalysisTemplate{TemplateName: analysis.TemplateName}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, service := range services {
+		if service == "" {
+			continue
+		}
+		if _, err := w.servicesLister.Services(rollout.Namespace).Get(service); err != nil {
+			if k8serrors.IsNotFound(err) {
+				return fmt.Errorf("Service '%s' not found", service)
+			}
+			return err
+		}
+	}
+
+	var referenceErr error
+	cond := conditions.VerifyRolloutAnalysisTemplates(rollout, nil, func(ref v1alpha1.RolloutAnalysisTemplate) (*v1alpha1.AnalysisTemplateSpec, error) {
+		spec, err := w.getAnalysisTemplateSpec(rollout.Namespace, ref)
+		if err != nil && referenceErr == nil {
+			referenceErr = err
+		}
+		return spec, err
+	})
+	if referenceErr != nil {
+		return referenceErr
+	}
+	if cond != nil {
+		return errors.New(cond.Message)
+	}
+	return nil
+}
+
+// validateExperimentReferences verifies that the AnalysisTemplates referenced by the experiment exist
+func (w *WebhookServer) validateExperimentReferences(experiment *v1alpha1.Experiment) error {
+	for _, analysis := range experiment.Spec.Analyses {
+		if err := w.validateAnalysisTemplateReference(experiment.Namespace, v1alpha1.RolloutAnalysisTemplate{TemplateName: analysis.TemplateName}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *WebhookServer) validateAnalysisTemplateReference(namespace string, ref v1alpha1.RolloutAnalysisTemplate) error {
+	_, err := w.getAnalysisTemplateSpec(namespace, ref)
+	return err
+}
+
+func (w *WebhookServer) getAnalysisTemplateSpec(namespace string, ref v1alpha1.RolloutAnalysisTemplate) (*v1alpha1.AnalysisTemplateSpec, error) {
+	if ref.ClusterScope {
+		if w.clusterAnalysisTemplateLister == nil {
+			return nil, fmt.Errorf("ClusterAnalysisTemplate '%s' cannot be used by a controller limited to a namespace", ref.TemplateName)
+		}
+		clusterTemplate, err := w.clusterAnalysisTemplateLister.Get(ref.TemplateName)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return nil, fmt.Errorf("ClusterAnalysisTemplate '%s' not found", ref.TemplateName)
+			}
+			return nil, err
+		}
+		return &clusterTemplate.Spec, nil
+	}
+	template, err := w.analysisTemplateLister.AnalysisTemplates(namespace).Get(ref.TemplateName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil, fmt.Errorf("AnalysisTemplate '%s' not found", ref.TemplateName)
+		}
+		return nil, err
+	}
+	return &template.Spec, nil
+}
+
+func allowed() *admissionv1beta1.AdmissionResponse {
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: true,
+	}
+}
+
+func denied(err error) *admissionv1beta1.AdmissionResponse {
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Message: err.Error(),
+			Reason:  metav1.StatusReasonInvalid,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}
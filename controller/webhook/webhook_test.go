@@ -0,0 +1,293 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
+	informers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions"
+)
+
+const (
+	validRollout = `{
+	"apiVersion": "argoproj.io/v1alpha1",
+	"kind": "Rollout",
+	"metadata": {"name": "guestbook", "namespace": "default"},
+	"spec": {
+		"selector": {"matchLabels": {"app": "guestbook"}},
+		"template": {
+			"metadata": {"labels": {"app": "guestbook"}},
+			"spec": {"containers": [{"name": "guestbook", "image": "guestbook:v1"}]}
+		},
+		"strategy": {
+			"blueGreen": {"activeService": "active", "previewService": "preview"}
+		}
+	}
+}`
+
+	rolloutWithoutSelector = `{
+	"apiVersion": "argoproj.io/v1alpha1",
+	"kind": "Rollout",
+	"metadata": {"name": "guestbook", "namespace": "default"},
+	"spec": {
+		"strategy": {
+			"blueGreen": {"activeService": "active"}
+		}
+	}
+}`
+
+	canaryRolloutWithAnalysis = `{
+	"apiVersion": "argoproj.io/v1alpha1",
+	"kind": "Rollout",
+	"metadata": {"name": "guestbook", "namespace": "default"},
+	"spec": {
+		"selector": {"matchLabels": {"app": "guestbook"}},
+		"template": {
+			"metadata": {"labels": {"app": "guestbook"}},
+			"spec": {"containers": [{"name": "guestbook", "image": "guestbook:v1"}]}
+		},
+		"strategy": {
+			"canary": {
+				"steps": [{"analysis": {"templateName": "success-rate"}}]
+			}
+		}
+	}
+}`
+
+	experimentWithAnalysis = `{
+	"apiVersion": "argoproj.io/v1alpha1",
+	"kind": "Experiment",
+	"metadata": {"name": "experiment", "namespace": "default"},
+	"spec": {
+		"templates": [{
+			"name": "baseline",
+			"selector": {"matchLabels": {"app": "baseline"}},
+			"template": {
+				"metadata": {"labels": {"app": "baseline"}},
+				"spec": {"containers": [{"name": "guestbook", "image": "guestbook:v1"}]}
+			}
+		}],
+		"analyses": [{"name": "analysis", "templateName": "success-rate"}]
+	}
+}`
+
+	analysisTemplateWithoutMetrics = `{
+	"apiVersion": "argoproj.io/v1alpha1",
+	"kind": "AnalysisTemplate",
+	"metadata": {"name": "success-rate", "namespace": "default"},
+	"spec": {"metrics": []}
+}`
+)
+
+func newService(name string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+}
+
+func newAnalysisTemplate(name string) *v1alpha1.AnalysisTemplate {
+	return &v1alpha1.AnalysisTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1alpha1.AnalysisTemplateSpec{
+			Metrics: []v1alpha1.Metric{{
+				Name: "success-rate",
+			}},
+		},
+	}
+}
+
+func newFakeWebhookServer(referenceWarnings bool, objects ...runtime.Object) *WebhookServer {
+	k8sI := kubeinformers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	i := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	for _, obj := range objects {
+		switch obj.(type) {
+		case *corev1.Service:
+			k8sI.Core().V1().Services().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.AnalysisTemplate:
+			i.Argoproj().V1alpha1().AnalysisTemplates().Informer().GetIndexer().Add(obj)
+		case *v1alpha1.ClusterAnalysisTemplate:
+			i.Argoproj().V1alpha1().ClusterAnalysisTemplates().Informer().GetIndexer().Add(obj)
+		}
+	}
+	return NewWebhookServer("localhost:8443",
+		k8sI.Core().V1().Services(),
+		i.Argoproj().V1alpha1().AnalysisTemplates(),
+		i.Argoproj().V1alpha1().ClusterAnalysisTemplates(),
+		referenceWarnings)
+}
+
+func newAdmissionReview(kind string, operation admissionv1beta1.Operation, object string) []byte {
+	review := admissionv1beta1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "admission.k8s.io/v1beta1",
+			Kind:       "AdmissionReview",
+		},
+		Request: &admissionv1beta1.AdmissionRequest{
+			UID:       types.UID("test-uid"),
+			Kind:      metav1.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: kind},
+			Namespace: metav1.NamespaceDefault,
+			Operation: operation,
+		},
+	}
+	if object != "" {
+		review.Request.Object = runtime.RawExtension{Raw: []byte(object)}
+	}
+	reviewBytes, err := json.Marshal(review)
+	if err != nil {
+		panic(err)
+	}
+	return reviewBytes
+}
+
+// setOldObject sets the old object of the AdmissionReview request of an update
+func setOldObject(reviewBytes []byte, oldObject string) []byte {
+	review := admissionv1beta1.AdmissionReview{}
+	if err := json.Unmarshal(reviewBytes, &review); err != nil {
+		panic(err)
+	}
+	review.Request.OldObject = runtime.RawExtension{Raw: []byte(oldObject)}
+	reviewBytes, err := json.Marshal(review)
+	if err != nil {
+		panic(err)
+	}
+	return reviewBytes
+}
+
+func sendAdmissionReview(t *testing.T, server *WebhookServer, body []byte) *admissionv1beta1.AdmissionResponse {
+	req, err := http.NewRequest(http.MethodPost, ValidatePath, bytes.NewReader(body))
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+	server.Handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	review := admissionv1beta1.AdmissionReview{}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &review))
+	assert.NotNil(t, review.Response)
+	assert.Equal(t, types.UID("test-uid"), review.Response.UID)
+	return review.Response
+}
+
+func TestValidateValidRollout(t *testing.T) {
+	server := newFakeWebhookServer(false, newService("active"), newService("preview"))
+	resp := sendAdmissionReview(t, server, newAdmissionReview("Rollout", admissionv1beta1.Create, validRollout))
+	assert.True(t, resp.Allowed)
+}
+
+func TestValidateInvalidRolloutSpec(t *testing.T) {
+	server := newFakeWebhookServer(true, newService("active"))
+	resp := sendAdmissionReview(t, server, newAdmissionReview("Rollout", admissionv1beta1.Create, rolloutWithoutSelector))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "Rollout has missing field '.Spec.Selector'", resp.Result.Message)
+	assert.Equal(t, int32(http.StatusUnprocessableEntity), resp.Result.Code)
+}
+
+func TestValidateRolloutMissingService(t *testing.T) {
+	server := newFakeWebhookServer(false, newService("active"))
+	resp := sendAdmissionReview(t, server, newAdmissionReview("Rollout", admissionv1beta1.Update, validRollout))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "Service 'preview' not found", resp.Result.Message)
+}
+
+func TestValidateRolloutMissingServiceWithReferenceWarnings(t *testing.T) {
+	server := newFakeWebhookServer(true, newService("active"))
+	resp := sendAdmissionReview(t, server, newAdmissionReview("Rollout", admissionv1beta1.Create, validRollout))
+	assert.True(t, resp.Allowed)
+	assert.Equal(t, "Service 'preview' not found", resp.AuditAnnotations[ReferenceWarningAnnotationKey])
+}
+
+func TestValidateRolloutMissingAnalysisTemplate(t *testing.T) {
+	server := newFakeWebhookServer(false)
+	resp := sendAdmissionReview(t, server, newAdmissionReview("Rollout", admissionv1beta1.Create, canaryRolloutWithAnalysis))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "AnalysisTemplate 'success-rate' not found", resp.Result.Message)
+
+	server = newFakeWebhookServer(false, newAnalysisTemplate("success-rate"))
+	resp = sendAdmissionReview(t, server, newAdmissionReview("Rollout", admissionv1beta1.Create, canaryRolloutWithAnalysis))
+	assert.True(t, resp.Allowed)
+}
+
+func TestValidateRolloutClusterAnalysisTemplateWithoutInformer(t *testing.T) {
+	k8sI := kubeinformers.NewSharedInformerFactory(k8sfake.NewSimpleClientset(), 0)
+	i := informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0)
+	server := NewWebhookServer("localhost:8443", k8sI.Core().V1().Services(), i.Argoproj().V1alpha1().AnalysisTemplates(), nil, false)
+	rollout := strings.Replace(canaryRolloutWithAnalysis, `"templateName": "success-rate"`, `"templateName": "success-rate", "clusterScope": true`, 1)
+	resp := sendAdmissionReview(t, server, newAdmissionReview("Rollout", admissionv1beta1.Create, rollout))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "ClusterAnalysisTemplate 'success-rate' cannot be used by a controller limited to a namespace", resp.Result.Message)
+}
+
+func TestValidateExperimentMissingAnalysisTemplate(t *testing.T) {
+	server := newFakeWebhookServer(false)
+	resp := sendAdmissionReview(t, server, newAdmissionReview("Experiment", admissionv1beta1.Create, experimentWithAnalysis))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "AnalysisTemplate 'success-rate' not found", resp.Result.Message)
+
+	server = newFakeWebhookServer(false, newAnalysisTemplate("success-rate"))
+	resp = sendAdmissionReview(t, server, newAdmissionReview("Experiment", admissionv1beta1.Create, experimentWithAnalysis))
+	assert.True(t, resp.Allowed)
+}
+
+func TestValidateInvalidAnalysisTemplate(t *testing.T) {
+	server := newFakeWebhookServer(false)
+	resp := sendAdmissionReview(t, server, newAdmissionReview("AnalysisTemplate", admissionv1beta1.Create, analysisTemplateWithoutMetrics))
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "no metrics specified", resp.Result.Message)
+}
+
+func TestValidateUpdate(t *testing.T) {
+	server := newFakeWebhookServer(false, newService("active"))
+
+	// An update of the status or the metadata is admitted even though the preview Service is missing
+	review := newAdmissionReview("Rollout", admissionv1beta1.Update, validRollout)
+	review = setOldObject(review, strings.Replace(validRollout, `"name": "guestbook",`, `"name": "guestbook", "annotations": {"foo": "bar"},`, 1))
+	resp := sendAdmissionReview(t, server, review)
+	assert.True(t, resp.Allowed)
+
+	// An update of the spec is validated
+	review = newAdmissionReview("Rollout", admissionv1beta1.Update, validRollout)
+	review = setOldObject(review, strings.Replace(validRollout, "guestbook:v1", "guestbook:v0", 1))
+	resp = sendAdmissionReview(t, server, review)
+	assert.False(t, resp.Allowed)
+	assert.Equal(t, "Service 'preview' not found", resp.Result.Message)
+}
+
+func TestValidateDeleteAllowed(t *testing.T) {
+	server := newFakeWebhookServer(false)
+	resp := sendAdmissionReview(t, server, newAdmissionReview("Rollout", admissionv1beta1.Delete, ""))
+	assert.True(t, resp.Allowed)
+}
+
+func TestValidateBadRequest(t *testing.T) {
+	server := newFakeWebhookServer(false)
+	req, err := http.NewRequest(http.MethodPost, ValidatePath, bytes.NewReader([]byte("not json")))
+	assert.NoError(t, err)
+	rr := httptest.NewRecorder()
+	server.Handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	req, err = http.NewRequest(http.MethodGet, ValidatePath, nil)
+	assert.NoError(t, err)
+	rr = httptest.NewRecorder()
+	server.Handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
@@ -0,0 +1,176 @@
+package experiments
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	patchtypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
+	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+)
+
+const (
+	terminateAnalysisRunPatch = `{
+		"spec": {
+			"terminate": true
+		}
+	}`
+)
+
+// getAnalysisRunsForExperiment returns the AnalysisRuns owned by the experiment mapped by the name of their analysis
+func (ec *ExperimentController) getAnalysisRunsForExperiment(experiment *v1alpha1.Experiment) (map[string]*v1alpha1.AnalysisRun, error) {
+	analysisRuns := make(map[string]*v1alpha1.AnalysisRun)
+	if len(experiment.Spec.Analyses) == 0 {
+		return analysisRuns, nil
+	}
+	ars, err := ec.analysisRunLister.AnalysisRuns(experiment.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for i := range ars {
+		ar := ars[i]
+		controllerRef := metav1.GetControllerOf(ar)
+		if controllerRef == nil || controllerRef.UID != experiment.UID {
+			continue
+		}
+		for _, analysis := range experiment.Spec.Analyses {
+			if ar.Name == experimentutil.AnalysisRunNameFromExperiment(experiment, analysis) {
+				analysisRuns[analysis.Name] = ar
+			}
+		}
+	}
+	return analysisRuns, nil
+}
+
+// reconcileAnalysisRuns creates the AnalysisRuns once all the templates are available, and terminates the AnalysisRuns
+// still running once the experiment has finished
+func (ec *ExperimentController) reconcileAnalysisRuns(experiment *v1alpha1.Experiment, templateRSs map[string]*appsv1.ReplicaSet, analysisRuns map[string]*v1alpha1.AnalysisRun) error {
+	logCtx := logutil.WithExperiment(experiment)
+	for _, analysis := range experiment.Spec.Analyses {
+		ar := analysisRuns[analysis.Name]
+		if experimentutil.HasFinished(experiment) {
+			if ar != nil && !ar.Spec.Terminate && (ar.Status == nil || !ar.Status.Status.Completed()) {
+				logCtx.WithField(logutil.AnalysisRunKey, ar.Name).Infof("Terminating AnalysisRun of analysis '%s'", analysis.Name)
+				_, err := ec.argoProjClientset.ArgoprojV1alpha1().AnalysisRuns(ar.Namespace).Patch(ar.Name, patchtypes.MergePatchType, []byte(terminateAnalysisRunPatch))
+				if err != nil && !k8serrors.IsNotFound(err) {
+					return err
+				}
+			}
+			continue
+		}
+		if ar != nil || experiment.Status.AvailableAt == nil {
+			continue
+		}
+		newAr, err := ec.newAnalysisRun(experiment, analysis, templateRSs)
+		if err != nil {
+			return err
+		}
+		ar, err = ec.argoProjClientset.ArgoprojV1alpha1().AnalysisRuns(newAr.Namespace).Create(newAr)
+		if err != nil {
+			return err
+		}
+		logCtx.WithField(logutil.AnalysisRunKey, ar.Name).Infof("Created AnalysisRun for analysis '%s'", analysis.Name)
+		analysisRuns[analysis.Name] = ar
+	}
+	return nil
+}
+
+// newAnalysisRun generates the AnalysisRun of an analysis from its AnalysisTemplate
+func (ec *ExperimentController) newAnalysisRun(experiment *v1alpha1.Experiment, analysis v1alpha1.ExperimentAnalysisTemplateRef, templateRSs map[string]*appsv1.ReplicaSet) (*v1alpha1.AnalysisRun, error) {
+	template, err := ec.analysisTemplateLister.AnalysisTemplates(experiment.Namespace).Get(analysis.TemplateName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			logutil.WithExperiment(experiment).Warnf("AnalysisTemplate '%s' not found", analysis.TemplateName)
+		}
+		return nil, err
+	}
+	ar := v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            experimentutil.AnalysisRunNameFromExperiment(experiment, analysis),
+			Namespace:       experiment.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(experiment, controllerKind)},
+		},
+		Spec: v1alpha1.AnalysisRunSpec{
+			AnalysisSpec: *template.Spec.DeepCopy(),
+			Arguments:    resolveArguments(analysis.Arguments, templateRSs),
+		},
+	}
+	return &ar, nil
+}
+
+// resolveArguments replaces the {{templates.<name>.podTemplateHash}} references in the values of the arguments with
+// the pod template hash of the template's ReplicaSet
+func resolveArguments(args []v1alpha1.Argument, templateRSs map[string]*appsv1.ReplicaSet) []v1alpha1.Argument {
+	resolved := make([]v1alpha1.Argument, 0, len(args))
+	for _, arg := range args {
+		value := arg.Value
+		for name, rs := range templateRSs {
+			if rs == nil {
+				continue
+			}
+			reference := fmt.Sprintf("{{templates.%s.podTemplateHash}}", name)
+			value = strings.Replace(value, reference, rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey], -1)
+		}
+		resolvedArg := arg.DeepCopy()
+		resolvedArg.Value = value
+		resolved = append(resolved, *resolvedArg)
+	}
+	return resolved
+}
+
+// assessAnalysisRuns returns the status of each analysis of the experiment and the status of the analyses as a whole.
+// It also returns whether the experiment should end, which happens once an analysis fails or errors, or once all the
+// analyses required for completion have finished.
+func assessAnalysisRuns(experiment *v1alpha1.Experiment, analysisRuns map[string]*v1alpha1.AnalysisRun) ([]v1alpha1.ExperimentAnalysisRunStatus, v1alpha1.AnalysisStatus, bool) {
+	var analysisRunStatuses []v1alpha1.ExperimentAnalysisRunStatus
+	worstStatus := v1alpha1.AnalysisStatusSuccessful
+	allCompleted := true
+	failed := false
+	requiredCount := 0
+	requiredCompletedCount := 0
+	for _, analysis := range experiment.Spec.Analyses {
+		analysisRunStatus := v1alpha1.ExperimentAnalysisRunStatus{
+			Name:        analysis.Name,
+			AnalysisRun: experimentutil.AnalysisRunNameFromExperiment(experiment, analysis),
+			Status:      v1alpha1.AnalysisStatusPending,
+		}
+		if ar := analysisRuns[analysis.Name]; ar != nil && ar.Status != nil && ar.Status.Status != "" {
+			analysisRunStatus.Status = ar.Status.Status
+			analysisRunStatus.Message = ar.Status.Message
+		}
+		analysisRunStatuses = append(analysisRunStatuses, analysisRunStatus)
+
+		if analysis.RequiredForCompletion {
+			requiredCount++
+		}
+		if !analysisRunStatus.Status.Completed() {
+			allCompleted = false
+			continue
+		}
+		if analysis.RequiredForCompletion {
+			requiredCompletedCount++
+		}
+		if analysisutil.IsWorse(worstStatus, analysisRunStatus.Status) {
+			worstStatus = analysisRunStatus.Status
+		}
+		if analysisRunStatus.Status == v1alpha1.AnalysisStatusFailed || analysisRunStatus.Status == v1alpha1.AnalysisStatusError {
+			failed = true
+		}
+	}
+
+	endExperiment := failed || (requiredCount > 0 && requiredCompletedCount == requiredCount)
+	if failed || allCompleted {
+		return analysisRunStatuses, worstStatus, endExperiment
+	}
+	if experiment.Status.AvailableAt == nil {
+		return analysisRunStatuses, v1alpha1.AnalysisStatusPending, endExperiment
+	}
+	return analysisRunStatuses, v1alpha1.AnalysisStatusRunning, endExperiment
+}
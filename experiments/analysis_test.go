@@ -0,0 +1,297 @@
+package experiments
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	experimentutil "github.com/argoproj/argo-rollouts/utils/experiment"
+)
+
+func newAnalysisTemplate(name string) *v1alpha1.AnalysisTemplate {
+	return &v1alpha1.AnalysisTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1alpha1.AnalysisTemplateSpec{
+			Metrics: []v1alpha1.Metric{{
+				Name:             "example",
+				SuccessCondition: "result > 0",
+			}},
+		},
+	}
+}
+
+func analysisTemplateToRun(ex *v1alpha1.Experiment, analysis v1alpha1.ExperimentAnalysisTemplateRef, template *v1alpha1.AnalysisTemplate, status v1alpha1.AnalysisStatus) *v1alpha1.AnalysisRun {
+	ar := &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            experimentutil.AnalysisRunNameFromExperiment(ex, analysis),
+			Namespace:       metav1.NamespaceDefault,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ex, controllerKind)},
+		},
+		Spec: v1alpha1.AnalysisRunSpec{
+			AnalysisSpec: template.Spec,
+			Arguments:    analysis.Arguments,
+		},
+	}
+	if status != "" {
+		ar.Status = &v1alpha1.AnalysisRunStatus{
+			Status: status,
+		}
+	}
+	return ar
+}
+
+// newRunningExperimentWithAnalyses returns an available experiment running the "bar" and "baz" templates with a
+// single analysis along with the ReplicaSets of the templates
+func newRunningExperimentWithAnalyses(requiredForCompletion bool) (*v1alpha1.Experiment, *appsv1.ReplicaSet, *appsv1.ReplicaSet) {
+	templates := generateTemplates("bar", "baz")
+	e := newExperiment("foo", templates, nil, pointer.BoolPtr(true))
+	e.Spec.Analyses = []v1alpha1.ExperimentAnalysisTemplateRef{{
+		Name:         "mann-whitney",
+		TemplateName: "mann-whitney-template",
+		Arguments: []v1alpha1.Argument{{
+			Name:  "baseline-hash",
+			Value: "{{templates.bar.podTemplateHash}}",
+		}},
+		RequiredForCompletion: requiredForCompletion,
+	}}
+	e.Status.Conditions = []v1alpha1.ExperimentCondition{{
+		Type:               v1alpha1.ExperimentProgressing,
+		Reason:             conditions.NewRSAvailableReason,
+		Message:            fmt.Sprintf(conditions.ExperimentRunningMessage, e.Name),
+		LastTransitionTime: metav1.Now(),
+		Status:             corev1.ConditionTrue,
+		LastUpdateTime:     metav1.Now(),
+	}}
+	now := metav1.Now()
+	e.Status.AvailableAt = &now
+	e.Status.TemplateStatuses = []v1alpha1.TemplateStatus{
+		generateTemplatesStatus("bar", 1, 1),
+		generateTemplatesStatus("baz", 1, 1),
+	}
+	rs1 := templateToRS(e, templates[0], 1)
+	rs2 := templateToRS(e, templates[1], 1)
+	return e, rs1, rs2
+}
+
+func TestCreateAnalysisRunOnceAvailable(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	e, rs1, rs2 := newRunningExperimentWithAnalyses(false)
+	at := newAnalysisTemplate("mann-whitney-template")
+
+	f.experimentLister = append(f.experimentLister, e)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.objects = append(f.objects, e, at)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+
+	createIndex := f.expectCreateAnalysisRunAction(analysisTemplateToRun(e, e.Spec.Analyses[0], at, ""))
+	patchIndex := f.expectPatchExperimentAction(e)
+	f.run(getKey(e, t))
+
+	createdAr := f.getCreatedAnalysisRun(createIndex)
+	assert.Equal(t, "foo-mann-whitney", createdAr.Name)
+	assert.Equal(t, at.Spec, createdAr.Spec.AnalysisSpec)
+	assert.Equal(t, []v1alpha1.Argument{{
+		Name:  "baseline-hash",
+		Value: rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey],
+	}}, createdAr.Spec.Arguments)
+	assert.Equal(t, e.UID, metav1.GetControllerOf(createdAr).UID)
+
+	patchedExperiment := v1alpha1.Experiment{}
+	err := json.Unmarshal([]byte(f.getPatchedExperiment(patchIndex)), &patchedExperiment)
+	assert.Nil(t, err)
+	assert.Equal(t, v1alpha1.AnalysisStatusRunning, patchedExperiment.Status.Status)
+	assert.Equal(t, []v1alpha1.ExperimentAnalysisRunStatus{{
+		Name:        "mann-whitney",
+		AnalysisRun: "foo-mann-whitney",
+		Status:      v1alpha1.AnalysisStatusPending,
+	}}, patchedExperiment.Status.AnalysisRuns)
+}
+
+func TestDoNotCreateAnalysisRunBeforeAvailable(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	e, rs1, rs2 := newRunningExperimentWithAnalyses(false)
+	e.Status.AvailableAt = nil
+	e.Status.TemplateStatuses = []v1alpha1.TemplateStatus{
+		generateTemplatesStatus("bar", 1, 0),
+		generateTemplatesStatus("baz", 1, 0),
+	}
+	rs1.Status.AvailableReplicas = 0
+	rs2.Status.AvailableReplicas = 0
+	at := newAnalysisTemplate("mann-whitney-template")
+
+	f.experimentLister = append(f.experimentLister, e)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.objects = append(f.objects, e, at)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+
+	patchIndex := f.expectPatchExperimentAction(e)
+	f.run(getKey(e, t))
+
+	patchedExperiment := v1alpha1.Experiment{}
+	err := json.Unmarshal([]byte(f.getPatchedExperiment(patchIndex)), &patchedExperiment)
+	assert.Nil(t, err)
+	assert.Equal(t, v1alpha1.AnalysisStatusPending, patchedExperiment.Status.Status)
+}
+
+func TestFailedAnalysisRunEndsExperiment(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	e, rs1, rs2 := newRunningExperimentWithAnalyses(false)
+	at := newAnalysisTemplate("mann-whitney-template")
+	ar := analysisTemplateToRun(e, e.Spec.Analyses[0], at, v1alpha1.AnalysisStatusFailed)
+	ar.Status.Message = "metric \"example\" assessed Failed"
+
+	f.experimentLister = append(f.experimentLister, e)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.analysisRunLister = append(f.analysisRunLister, ar)
+	f.objects = append(f.objects, e, at, ar)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+
+	patchIndex := f.expectPatchExperimentAction(e)
+	f.run(getKey(e, t))
+
+	patchedExperiment := v1alpha1.Experiment{}
+	err := json.Unmarshal([]byte(f.getPatchedExperiment(patchIndex)), &patchedExperiment)
+	assert.Nil(t, err)
+	assert.Equal(t, pointer.BoolPtr(false), patchedExperiment.Status.Running)
+	assert.Equal(t, v1alpha1.AnalysisStatusFailed, patchedExperiment.Status.Status)
+	assert.Equal(t, []v1alpha1.ExperimentAnalysisRunStatus{{
+		Name:        "mann-whitney",
+		AnalysisRun: "foo-mann-whitney",
+		Status:      v1alpha1.AnalysisStatusFailed,
+		Message:     ar.Status.Message,
+	}}, patchedExperiment.Status.AnalysisRuns)
+}
+
+func TestRequiredAnalysisRunCompletionEndsExperiment(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	e, rs1, rs2 := newRunningExperimentWithAnalyses(true)
+	at := newAnalysisTemplate("mann-whitney-template")
+	ar := analysisTemplateToRun(e, e.Spec.Analyses[0], at, v1alpha1.AnalysisStatusSuccessful)
+
+	f.experimentLister = append(f.experimentLister, e)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.analysisRunLister = append(f.analysisRunLister, ar)
+	f.objects = append(f.objects, e, at, ar)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+
+	patchIndex := f.expectPatchExperimentAction(e)
+	f.run(getKey(e, t))
+
+	patchedExperiment := v1alpha1.Experiment{}
+	err := json.Unmarshal([]byte(f.getPatchedExperiment(patchIndex)), &patchedExperiment)
+	assert.Nil(t, err)
+	assert.Equal(t, pointer.BoolPtr(false), patchedExperiment.Status.Running)
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, patchedExperiment.Status.Status)
+}
+
+func TestTerminateAnalysisRunAfterExperimentFinishes(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	e, rs1, rs2 := newRunningExperimentWithAnalyses(false)
+	e.Status.Running = pointer.BoolPtr(false)
+	at := newAnalysisTemplate("mann-whitney-template")
+	ar := analysisTemplateToRun(e, e.Spec.Analyses[0], at, v1alpha1.AnalysisStatusRunning)
+	rs1.Spec.Replicas = pointer.Int32Ptr(0)
+	rs2.Spec.Replicas = pointer.Int32Ptr(0)
+
+	f.experimentLister = append(f.experimentLister, e)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.analysisRunLister = append(f.analysisRunLister, ar)
+	f.objects = append(f.objects, e, at, ar)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+
+	f.expectPatchAnalysisRunAction(ar)
+	f.expectPatchExperimentAction(e)
+	f.run(getKey(e, t))
+}
+
+func TestResolveArguments(t *testing.T) {
+	templates := generateTemplates("bar", "baz")
+	e := newExperiment("foo", templates, nil, nil)
+	rs1 := templateToRS(e, templates[0], 1)
+	rs2 := templateToRS(e, templates[1], 1)
+	templateRSs := map[string]*appsv1.ReplicaSet{
+		"bar": rs1,
+		"baz": rs2,
+	}
+	args := []v1alpha1.Argument{
+		{Name: "baseline", Value: "{{templates.bar.podTemplateHash}}"},
+		{Name: "canary", Value: "hash={{templates.baz.podTemplateHash}}"},
+		{Name: "missing", Value: "{{templates.other.podTemplateHash}}"},
+		{Name: "plain", Value: "value"},
+	}
+	resolved := resolveArguments(args, templateRSs)
+	assert.Equal(t, []v1alpha1.Argument{
+		{Name: "baseline", Value: rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]},
+		{Name: "canary", Value: "hash=" + rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]},
+		{Name: "missing", Value: "{{templates.other.podTemplateHash}}"},
+		{Name: "plain", Value: "value"},
+	}, resolved)
+	// the original arguments are left untouched
+	assert.Equal(t, "{{templates.bar.podTemplateHash}}", args[0].Value)
+}
+
+func TestAssessAnalysisRuns(t *testing.T) {
+	e := newExperiment("foo", generateTemplates("bar"), nil, pointer.BoolPtr(true))
+	e.Spec.Analyses = []v1alpha1.ExperimentAnalysisTemplateRef{
+		{Name: "required", TemplateName: "template", RequiredForCompletion: true},
+		{Name: "optional", TemplateName: "template"},
+	}
+	at := newAnalysisTemplate("template")
+	newRuns := func(requiredStatus, optionalStatus v1alpha1.AnalysisStatus) map[string]*v1alpha1.AnalysisRun {
+		return map[string]*v1alpha1.AnalysisRun{
+			"required": analysisTemplateToRun(e, e.Spec.Analyses[0], at, requiredStatus),
+			"optional": analysisTemplateToRun(e, e.Spec.Analyses[1], at, optionalStatus),
+		}
+	}
+
+	_, status, end := assessAnalysisRuns(e, nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusPending, status)
+	assert.False(t, end)
+
+	now := metav1.Now()
+	e.Status.AvailableAt = &now
+	statuses, status, end := assessAnalysisRuns(e, newRuns(v1alpha1.AnalysisStatusRunning, ""))
+	assert.Equal(t, v1alpha1.AnalysisStatusRunning, status)
+	assert.False(t, end)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, v1alpha1.AnalysisStatusRunning, statuses[0].Status)
+	assert.Equal(t, v1alpha1.AnalysisStatusPending, statuses[1].Status)
+
+	_, status, end = assessAnalysisRuns(e, newRuns(v1alpha1.AnalysisStatusRunning, v1alpha1.AnalysisStatusError))
+	assert.Equal(t, v1alpha1.AnalysisStatusError, status)
+	assert.True(t, end)
+
+	_, status, end = assessAnalysisRuns(e, newRuns(v1alpha1.AnalysisStatusSuccessful, v1alpha1.AnalysisStatusRunning))
+	assert.Equal(t, v1alpha1.AnalysisStatusRunning, status)
+	assert.True(t, end)
+
+	_, status, end = assessAnalysisRuns(e, newRuns(v1alpha1.AnalysisStatusSuccessful, v1alpha1.AnalysisStatusInconclusive))
+	assert.Equal(t, v1alpha1.AnalysisStatusInconclusive, status)
+	assert.True(t, end)
+}
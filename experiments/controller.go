@@ -36,13 +36,17 @@ type ExperimentController struct {
 	// rsControl is used for adopting/releasing replica sets.
 	replicaSetControl controller.RSControlInterface
 
-	replicaSetLister  appslisters.ReplicaSetLister
-	rolloutsLister    listers.RolloutLister
-	experimentsLister listers.ExperimentLister
+	replicaSetLister       appslisters.ReplicaSetLister
+	rolloutsLister         listers.RolloutLister
+	experimentsLister      listers.ExperimentLister
+	analysisTemplateLister listers.AnalysisTemplateLister
+	analysisRunLister      listers.AnalysisRunLister
 
-	replicaSetSynced cache.InformerSynced
-	experimentSynced cache.InformerSynced
-	rolloutSynced    cache.InformerSynced
+	replicaSetSynced       cache.InformerSynced
+	experimentSynced       cache.InformerSynced
+	rolloutSynced          cache.InformerSynced
+	analysisTemplateSynced cache.InformerSynced
+	analysisRunSynced      cache.InformerSynced
 
 	metricsServer *metrics.MetricsServer
 
@@ -70,6 +74,8 @@ func NewExperimentController(
 	replicaSetInformer appsinformers.ReplicaSetInformer,
 	rolloutsInformer informers.RolloutInformer,
 	experimentsInformer informers.ExperimentInformer,
+	analysisTemplateInformer informers.AnalysisTemplateInformer,
+	analysisRunInformer informers.AnalysisRunInformer,
 	resyncPeriod time.Duration,
 	rolloutWorkQueue workqueue.RateLimitingInterface,
 	experimentWorkQueue workqueue.RateLimitingInterface,
@@ -82,21 +88,25 @@ func NewExperimentController(
 	}
 
 	controller := &ExperimentController{
-		kubeclientset:       kubeclientset,
-		argoProjClientset:   argoProjClientset,
-		replicaSetControl:   replicaSetControl,
-		replicaSetLister:    replicaSetInformer.Lister(),
-		rolloutsLister:      rolloutsInformer.Lister(),
-		experimentsLister:   experimentsInformer.Lister(),
-		metricsServer:       metricsServer,
-		rolloutWorkqueue:    rolloutWorkQueue,
-		experimentWorkqueue: experimentWorkQueue,
-
-		replicaSetSynced: replicaSetInformer.Informer().HasSynced,
-		experimentSynced: experimentsInformer.Informer().HasSynced,
-		rolloutSynced:    rolloutsInformer.Informer().HasSynced,
-		recorder:         recorder,
-		resyncPeriod:     resyncPeriod,
+		kubeclientset:          kubeclientset,
+		argoProjClientset:      argoProjClientset,
+		replicaSetControl:      replicaSetControl,
+		replicaSetLister:       replicaSetInformer.Lister(),
+		rolloutsLister:         rolloutsInformer.Lister(),
+		experimentsLister:      experimentsInformer.Lister(),
+		analysisTemplateLister: analysisTemplateInformer.Lister(),
+		analysisRunLister:      analysisRunInformer.Lister(),
+		metricsServer:          metricsServer,
+		rolloutWorkqueue:       rolloutWorkQueue,
+		experimentWorkqueue:    experimentWorkQueue,
+
+		replicaSetSynced:       replicaSetInformer.Informer().HasSynced,
+		experimentSynced:       experimentsInformer.Informer().HasSynced,
+		rolloutSynced:          rolloutsInformer.Informer().HasSynced,
+		analysisTemplateSynced: analysisTemplateInformer.Informer().HasSynced,
+		analysisRunSynced:      analysisRunInformer.Informer().HasSynced,
+		recorder:               recorder,
+		resyncPeriod:           resyncPeriod,
 	}
 
 	controller.enqueueExperiment = func(obj interface{}) {
@@ -162,6 +172,24 @@ func NewExperimentController(
 			controllerutil.EnqueueParentObject(obj, register.ExperimentKind, controller.enqueueExperiment)
 		},
 	})
+
+	analysisRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			controllerutil.EnqueueParentObject(obj, register.ExperimentKind, controller.enqueueExperiment)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			newAR := new.(*v1alpha1.AnalysisRun)
+			oldAR := old.(*v1alpha1.AnalysisRun)
+			if newAR.Status != nil && oldAR.Status != nil && newAR.Status.Status == oldAR.Status.Status {
+				// Only enqueue experiment if the status changed
+				return
+			}
+			controllerutil.EnqueueParentObject(new, register.ExperimentKind, controller.enqueueExperiment)
+		},
+		DeleteFunc: func(obj interface{}) {
+			controllerutil.EnqueueParentObject(obj, register.ExperimentKind, controller.enqueueExperiment)
+		},
+	})
 	return controller
 }
 
@@ -229,5 +257,10 @@ func (ec *ExperimentController) syncHandler(key string) error {
 		return err
 	}
 
-	return ec.reconcileExperiment(experiment, templateRSs)
+	analysisRuns, err := ec.getAnalysisRunsForExperiment(experiment)
+	if err != nil {
+		return err
+	}
+
+	return ec.reconcileExperiment(experiment, templateRSs, analysisRuns)
 }
@@ -53,8 +53,10 @@ type fixture struct {
 	kubeclient *k8sfake.Clientset
 	// Objects to put in the store.
 	// rolloutLister    []*v1alpha1.Rollout
-	experimentLister []*v1alpha1.Experiment
-	replicaSetLister []*appsv1.ReplicaSet
+	experimentLister       []*v1alpha1.Experiment
+	replicaSetLister       []*appsv1.ReplicaSet
+	analysisTemplateLister []*v1alpha1.AnalysisTemplate
+	analysisRunLister      []*v1alpha1.AnalysisRun
 	// Actions expected to happen on the client.
 	kubeactions []core.Action
 	actions     []core.Action
@@ -297,6 +299,8 @@ func (f *fixture) newController(resync resyncFunc) (*ExperimentController, infor
 		k8sI.Apps().V1().ReplicaSets(),
 		i.Argoproj().V1alpha1().Rollouts(),
 		i.Argoproj().V1alpha1().Experiments(),
+		i.Argoproj().V1alpha1().AnalysisTemplates(),
+		i.Argoproj().V1alpha1().AnalysisRuns(),
 		resync(),
 		rolloutWorkqueue,
 		experimentWorkqueue,
@@ -329,6 +333,14 @@ func (f *fixture) newController(resync resyncFunc) (*ExperimentController, infor
 		k8sI.Apps().V1().ReplicaSets().Informer().GetIndexer().Add(r)
 	}
 
+	for _, at := range f.analysisTemplateLister {
+		i.Argoproj().V1alpha1().AnalysisTemplates().Informer().GetIndexer().Add(at)
+	}
+
+	for _, ar := range f.analysisRunLister {
+		i.Argoproj().V1alpha1().AnalysisRuns().Informer().GetIndexer().Add(ar)
+	}
+
 	return c, i, k8sI
 }
 
@@ -349,7 +361,7 @@ func (f *fixture) runController(experimentName string, startInformers bool, expe
 		i.Start(stopCh)
 		k8sI.Start(stopCh)
 
-		assert.True(f.t, cache.WaitForCacheSync(stopCh, c.replicaSetSynced, c.rolloutSynced, c.experimentSynced))
+		assert.True(f.t, cache.WaitForCacheSync(stopCh, c.replicaSetSynced, c.rolloutSynced, c.experimentSynced, c.analysisTemplateSynced, c.analysisRunSynced))
 	}
 
 	err := c.syncHandler(experimentName)
@@ -423,7 +435,11 @@ func filterInformerActions(actions []core.Action) []core.Action {
 			action.Matches("list", "replicaSets") ||
 			action.Matches("watch", "replicaSets") ||
 			action.Matches("list", "experiments") ||
-			action.Matches("watch", "experiments") {
+			action.Matches("watch", "experiments") ||
+			action.Matches("list", "analysistemplates") ||
+			action.Matches("watch", "analysistemplates") ||
+			action.Matches("list", "analysisruns") ||
+			action.Matches("watch", "analysisruns") {
 			continue
 		}
 		ret = append(ret, action)
@@ -479,6 +495,36 @@ func (f *fixture) expectPatchExperimentAction(experiment *v1alpha1.Experiment) i
 	return len
 }
 
+func (f *fixture) expectCreateAnalysisRunAction(r *v1alpha1.AnalysisRun) int {
+	len := len(f.actions)
+	f.actions = append(f.actions, core.NewCreateAction(schema.GroupVersionResource{Resource: "analysisruns"}, r.Namespace, r))
+	return len
+}
+
+func (f *fixture) expectPatchAnalysisRunAction(r *v1alpha1.AnalysisRun) int {
+	analysisRunSchema := schema.GroupVersionResource{
+		Resource: "analysisruns",
+		Version:  "v1alpha1",
+	}
+	len := len(f.actions)
+	f.actions = append(f.actions, core.NewPatchAction(analysisRunSchema, r.Namespace, r.Name, types.MergePatchType, nil))
+	return len
+}
+
+func (f *fixture) getCreatedAnalysisRun(index int) *v1alpha1.AnalysisRun {
+	action := filterInformerActions(f.client.Actions())[index]
+	createAction, ok := action.(core.CreateAction)
+	if !ok {
+		assert.Failf(f.t, "Expected Created action, not %s", action.GetVerb())
+	}
+	obj := createAction.GetObject()
+	ar := &v1alpha1.AnalysisRun{}
+	converter := runtime.NewTestUnstructuredConverter(equality.Semantic)
+	objMap, _ := converter.ToUnstructured(obj)
+	runtime.NewTestUnstructuredConverter(equality.Semantic).FromUnstructured(objMap, ar)
+	return ar
+}
+
 func (f *fixture) getCreatedReplicaSet(index int) *appsv1.ReplicaSet {
 	action := filterInformerActions(f.kubeclient.Actions())[index]
 	createAction, ok := action.(core.CreateAction)
@@ -14,12 +14,12 @@ import (
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
 )
 
-func (ec *ExperimentController) reconcileExperiment(experiment *v1alpha1.Experiment, templateRSs map[string]*appsv1.ReplicaSet) error {
+func (ec *ExperimentController) reconcileExperiment(experiment *v1alpha1.Experiment, templateRSs map[string]*appsv1.ReplicaSet, analysisRuns map[string]*v1alpha1.AnalysisRun) error {
 	logCtx := logutil.WithExperiment(experiment)
 
 	if !experimentutil.HasStarted(experiment) {
 		logCtx.Info("Experiment has not started yet")
-		return ec.syncExperimentStatus(experiment, templateRSs)
+		return ec.syncExperimentStatus(experiment, templateRSs, analysisRuns)
 	}
 
 	passedDuration, _ := experimentutil.PassedDurations(experiment)
@@ -40,7 +40,12 @@ func (ec *ExperimentController) reconcileExperiment(experiment *v1alpha1.Experim
 		}
 	}
 
-	return ec.syncExperimentStatus(experiment, templateRSs)
+	err := ec.reconcileAnalysisRuns(experiment, templateRSs, analysisRuns)
+	if err != nil {
+		return err
+	}
+
+	return ec.syncExperimentStatus(experiment, templateRSs, analysisRuns)
 }
 
 func (ec *ExperimentController) reconcileTemplate(experiment *v1alpha1.Experiment, template v1alpha1.TemplateSpec, templateStatus v1alpha1.TemplateStatus, templateRSs map[string]*appsv1.ReplicaSet) (bool, error) {
@@ -78,7 +83,7 @@ func (ec *ExperimentController) checkEnqueueExperimentDuringRun(experiment *v1al
 	}
 }
 
-func (ec *ExperimentController) syncExperimentStatus(experiment *v1alpha1.Experiment, templateRSs map[string]*appsv1.ReplicaSet) error {
+func (ec *ExperimentController) syncExperimentStatus(experiment *v1alpha1.Experiment, templateRSs map[string]*appsv1.ReplicaSet, analysisRuns map[string]*v1alpha1.AnalysisRun) error {
 	newStatus := v1alpha1.ExperimentStatus{
 		Conditions: experiment.Status.Conditions,
 	}
@@ -126,6 +131,14 @@ func (ec *ExperimentController) syncExperimentStatus(experiment *v1alpha1.Experi
 		newStatus.AvailableAt = &now
 	}
 
+	if len(experiment.Spec.Analyses) > 0 {
+		var endExperiment bool
+		newStatus.AnalysisRuns, newStatus.Status, endExperiment = assessAnalysisRuns(experiment, analysisRuns)
+		if endExperiment && experimentutil.HasStarted(experiment) {
+			newStatus.Running = pointer.BoolPtr(false)
+		}
+	}
+
 	newStatus = ec.calculateExperimentConditions(experiment, newStatus, templateRSs)
 	return ec.persistExperimentStatus(experiment, &newStatus)
 }
@@ -15,10 +15,11 @@ import (
 )
 
 var crdPaths = map[string]string{
-	"Rollout":          "manifests/crds/rollout-crd.yaml",
-	"Experiment":       "manifests/crds/experiment-crd.yaml",
-	"AnalysisTemplate": "manifests/crds/analysis-template-crd.yaml",
-	"AnalysisRun":      "manifests/crds/analysis-run-crd.yaml",
+	"Rollout":                 "manifests/crds/rollout-crd.yaml",
+	"Experiment":              "manifests/crds/experiment-crd.yaml",
+	"AnalysisTemplate":        "manifests/crds/analysis-template-crd.yaml",
+	"ClusterAnalysisTemplate": "manifests/crds/cluster-analysis-template-crd.yaml",
+	"AnalysisRun":             "manifests/crds/analysis-run-crd.yaml",
 }
 
 func NewCustomResourceDefinition() []*extensionsobj.CustomResourceDefinition {
@@ -48,6 +49,9 @@ func NewCustomResourceDefinition() []*extensionsobj.CustomResourceDefinition {
 		removeResourceValidation(obj)
 		crd := toCRD(obj)
 		crd.Spec.Scope = "Namespaced"
+		if crd.Spec.Names.Kind == "ClusterAnalysisTemplate" {
+			crd.Spec.Scope = "Cluster"
+		}
 		crds = append(crds, crd)
 	}
 
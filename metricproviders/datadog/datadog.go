@@ -0,0 +1,215 @@
+package datadog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	"github.com/argoproj/argo-rollouts/utils/evaluate"
+	metricutil "github.com/argoproj/argo-rollouts/utils/metric"
+	"github.com/argoproj/argo-rollouts/utils/query"
+)
+
+const (
+	// ProviderType indicates the provider is datadog
+	ProviderType = "Datadog"
+	// DatadogTokensSecretName is the name of the secret in the controller namespace holding the datadog keys
+	DatadogTokensSecretName = "datadog"
+	// DefaultAddress is the address of the datadog API if the secret does not specify one
+	DefaultAddress = "https://api.datadoghq.com"
+	// DefaultInterval is the time window of the query if the metric does not specify one
+	DefaultInterval = "5m"
+
+	apiKeyKey  = "api-key"
+	appKeyKey  = "app-key"
+	addressKey = "address"
+)
+
+// Provider contains all the required components to run a datadog query
+type Provider struct {
+	client  *http.Client
+	address string
+	apiKey  string
+	appKey  string
+	logCtx  log.Entry
+}
+
+type datadogResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Series []struct {
+		Pointlist [][]*float64 `json:"pointlist"`
+	} `json:"series"`
+}
+
+// Type indicates provider is a datadog provider
+func (p *Provider) Type() string {
+	return ProviderType
+}
+
+// Run queries datadog for the metric
+func (p *Provider) Run(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args []v1alpha1.Argument) v1alpha1.Measurement {
+	startTime := metav1.Now()
+	newMeasurement := v1alpha1.Measurement{
+		StartedAt: &startTime,
+	}
+
+	request, err := p.newRequest(metric.Provider.Datadog, args, startTime.Time)
+	if err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, err)
+	}
+	response, err := p.client.Do(request)
+	if err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, err)
+	}
+	defer response.Body.Close()
+
+	newValue, newStatus, err := p.processResponse(metric, response)
+	if err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, err)
+	}
+	newMeasurement.Value = newValue
+	newMeasurement.Status = newStatus
+	finishedTime := metav1.Now()
+	newMeasurement.FinishedAt = &finishedTime
+	return newMeasurement
+}
+
+// newRequest builds the timeseries query request over the interval of the metric ending now
+func (p *Provider) newRequest(metric *v1alpha1.DatadogMetric, args []v1alpha1.Argument, now time.Time) (*http.Request, error) {
+	interval := metric.Interval
+	if interval == "" {
+		interval = DefaultInterval
+	}
+	duration, err := time.ParseDuration(interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval '%s': %v", interval, err)
+	}
+	queryStr, err := query.BuildQuery(metric.Query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint, err := url.Parse(p.address + "/api/v1/query")
+	if err != nil {
+		return nil, err
+	}
+	q := endpoint.Query()
+	q.Set("from", strconv.FormatInt(now.Add(-duration).Unix(), 10))
+	q.Set("to", strconv.FormatInt(now.Unix(), 10))
+	q.Set("query", queryStr)
+	endpoint.RawQuery = q.Encode()
+
+	request, err := http.NewRequest(http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("DD-API-KEY", p.apiKey)
+	request.Header.Set("DD-APPLICATION-KEY", p.appKey)
+	return request, nil
+}
+
+// processResponse reduces each series of the response to its most recent point. A single series is evaluated as a
+// number and multiple series as an array of numbers.
+func (p *Provider) processResponse(metric v1alpha1.Metric, response *http.Response) (string, v1alpha1.AnalysisStatus, error) {
+	bodyBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", v1alpha1.AnalysisStatusError, fmt.Errorf("failed to read response body: %v", err)
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return "", v1alpha1.AnalysisStatusError, fmt.Errorf("received non 2xx response code: %d %s", response.StatusCode, string(bodyBytes))
+	}
+	var res datadogResponse
+	if err := json.Unmarshal(bodyBytes, &res); err != nil {
+		return "", v1alpha1.AnalysisStatusError, fmt.Errorf("could not parse JSON body: %v", err)
+	}
+	if res.Status == "error" {
+		return "", v1alpha1.AnalysisStatusError, fmt.Errorf("datadog query failed: %s", res.Error)
+	}
+	if len(res.Series) == 0 {
+		return "", v1alpha1.AnalysisStatusError, fmt.Errorf("datadog returned no series")
+	}
+
+	values := make([]float64, 0, len(res.Series))
+	for _, series := range res.Series {
+		value, ok := lastValue(series.Pointlist)
+		if !ok {
+			return "", v1alpha1.AnalysisStatusError, fmt.Errorf("datadog returned a series without data points")
+		}
+		values = append(values, value)
+	}
+	if len(values) == 1 {
+		return strconv.FormatFloat(values[0], 'f', -1, 64), evaluate.EvaluateResult(values[0], metric, p.logCtx), nil
+	}
+	valueStr := "["
+	for i, value := range values {
+		if i > 0 {
+			valueStr += ","
+		}
+		valueStr += strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	valueStr += "]"
+	return valueStr, evaluate.EvaluateResult(values, metric, p.logCtx), nil
+}
+
+// lastValue returns the value of the most recent point of the series with a value. Each point is a pair of a
+// timestamp and a value which is null when there is no data.
+func lastValue(pointlist [][]*float64) (float64, bool) {
+	for i := len(pointlist) - 1; i >= 0; i-- {
+		point := pointlist[i]
+		if len(point) == 2 && point[1] != nil {
+			return *point[1], true
+		}
+	}
+	return 0, false
+}
+
+// Resume should not be used the datadog provider since all the work should occur in the Run method
+func (p *Provider) Resume(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args []v1alpha1.Argument, measurement v1alpha1.Measurement) v1alpha1.Measurement {
+	p.logCtx.Warn("Datadog provider should not execute the Resume method")
+	return measurement
+}
+
+// Terminate should not be used the datadog provider since all the work should occur in the Run method
+func (p *Provider) Terminate(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args []v1alpha1.Argument, measurement v1alpha1.Measurement) v1alpha1.Measurement {
+	p.logCtx.Warn("Datadog provider should not execute the Terminate method")
+	return measurement
+}
+
+// NewDatadogProvider creates a new datadog provider with the API and application keys of the datadog secret in the
+// controller namespace
+func NewDatadogProvider(logCtx log.Entry, kubeclientset kubernetes.Interface) (*Provider, error) {
+	secret, err := kubeclientset.CoreV1().Secrets(defaults.Namespace()).Get(DatadogTokensSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	apiKey := string(secret.Data[apiKeyKey])
+	appKey := string(secret.Data[appKeyKey])
+	if apiKey == "" || appKey == "" {
+		return nil, fmt.Errorf("secret '%s' must have the '%s' and '%s' keys", DatadogTokensSecretName, apiKeyKey, appKeyKey)
+	}
+	address := DefaultAddress
+	if secretAddress, ok := secret.Data[addressKey]; ok && len(secretAddress) > 0 {
+		address = string(secretAddress)
+	}
+	return &Provider{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		address: address,
+		apiKey:  apiKey,
+		appKey:  appKey,
+		logCtx:  logCtx,
+	}, nil
+}
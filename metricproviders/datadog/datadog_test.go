@@ -0,0 +1,161 @@
+package datadog
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+)
+
+func newSecret(address string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DatadogTokensSecretName,
+			Namespace: defaults.Namespace(),
+		},
+		Data: map[string][]byte{
+			"api-key": []byte("api-key"),
+			"app-key": []byte("app-key"),
+			"address": []byte(address),
+		},
+	}
+}
+
+func newMetric(query, interval string) v1alpha1.Metric {
+	return v1alpha1.Metric{
+		Name:             "foo",
+		SuccessCondition: "result < 0.05",
+		FailureCondition: "result >= 0.05",
+		Provider: v1alpha1.MetricProvider{
+			Datadog: &v1alpha1.DatadogMetric{
+				Query:    query,
+				Interval: interval,
+			},
+		},
+	}
+}
+
+func newProvider(t *testing.T, objects ...runtime.Object) *Provider {
+	p, err := NewDatadogProvider(*log.NewEntry(log.New()), fake.NewSimpleClientset(objects...))
+	assert.NoError(t, err)
+	return p
+}
+
+func TestType(t *testing.T) {
+	p := newProvider(t, newSecret(""))
+	assert.Equal(t, ProviderType, p.Type())
+	assert.Equal(t, DefaultAddress, p.address)
+}
+
+func TestRunSuccessfully(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query", r.URL.Path)
+		assert.Equal(t, "api-key", r.Header.Get("DD-API-KEY"))
+		assert.Equal(t, "app-key", r.Header.Get("DD-APPLICATION-KEY"))
+		assert.Equal(t, "avg:error_rate{service:guestbook}", r.URL.Query().Get("query"))
+		from, err := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+		assert.NoError(t, err)
+		to, err := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+		assert.NoError(t, err)
+		assert.Equal(t, int64((10 * time.Minute).Seconds()), to-from)
+		w.Write([]byte(`{"status": "ok", "series": [{"pointlist": [[1580000000000, 0.1], [1580000060000, 0.01], [1580000120000, null]]}]}`))
+	}))
+	defer server.Close()
+
+	p := newProvider(t, newSecret(server.URL))
+	metric := newMetric("avg:error_rate{service:{{input.service}}}", "10m")
+	measurement := p.Run(nil, metric, []v1alpha1.Argument{{Name: "service", Value: "guestbook"}})
+	assert.NotNil(t, measurement.StartedAt)
+	assert.NotNil(t, measurement.FinishedAt)
+	assert.Equal(t, "0.01", measurement.Value)
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, measurement.Status)
+}
+
+func TestRunMultipleSeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ok", "series": [{"pointlist": [[1580000000000, 0.01]]}, {"pointlist": [[1580000000000, 0.1]]}]}`))
+	}))
+	defer server.Close()
+
+	p := newProvider(t, newSecret(server.URL))
+	metric := newMetric("avg:error_rate{*} by {host}", "")
+	metric.SuccessCondition = "all(result, {# < 0.05})"
+	metric.FailureCondition = "any(result, {# >= 0.05})"
+	measurement := p.Run(nil, metric, nil)
+	assert.Equal(t, "[0.01,0.1]", measurement.Value)
+	assert.Equal(t, v1alpha1.AnalysisStatusFailed, measurement.Status)
+}
+
+func TestRunNoSeries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "ok", "series": []}`))
+	}))
+	defer server.Close()
+
+	p := newProvider(t, newSecret(server.URL))
+	measurement := p.Run(nil, newMetric("avg:error_rate{*}", ""), nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "datadog returned no series", measurement.Message)
+}
+
+func TestRunQueryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status": "error", "error": "invalid query"}`))
+	}))
+	defer server.Close()
+
+	p := newProvider(t, newSecret(server.URL))
+	measurement := p.Run(nil, newMetric("avg:error_rate{", ""), nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "datadog query failed: invalid query", measurement.Message)
+}
+
+func TestRunNon2xxResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors": ["Forbidden"]}`))
+	}))
+	defer server.Close()
+
+	p := newProvider(t, newSecret(server.URL))
+	measurement := p.Run(nil, newMetric("avg:error_rate{*}", ""), nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, `received non 2xx response code: 403 {"errors": ["Forbidden"]}`, measurement.Message)
+}
+
+func TestRunInvalidInterval(t *testing.T) {
+	p := newProvider(t, newSecret("http://localhost"))
+	measurement := p.Run(nil, newMetric("avg:error_rate{*}", "5 minutes"), nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Contains(t, measurement.Message, "invalid interval '5 minutes'")
+}
+
+func TestNewDatadogProviderMissingSecret(t *testing.T) {
+	_, err := NewDatadogProvider(log.Entry{}, fake.NewSimpleClientset())
+	assert.Error(t, err)
+}
+
+func TestNewDatadogProviderMissingKeys(t *testing.T) {
+	secret := newSecret("")
+	delete(secret.Data, "app-key")
+	_, err := NewDatadogProvider(log.Entry{}, fake.NewSimpleClientset(secret))
+	assert.EqualError(t, err, "secret 'datadog' must have the 'api-key' and 'app-key' keys")
+}
+
+func TestResumeAndTerminate(t *testing.T) {
+	p := newProvider(t, newSecret(""))
+	measurement := v1alpha1.Measurement{Value: "1"}
+	assert.Equal(t, measurement, p.Resume(nil, v1alpha1.Metric{}, nil, measurement))
+	assert.Equal(t, measurement, p.Terminate(nil, v1alpha1.Metric{}, nil, measurement))
+}
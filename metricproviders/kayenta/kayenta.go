@@ -0,0 +1,283 @@
+package kayenta
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	metricutil "github.com/argoproj/argo-rollouts/utils/metric"
+	"github.com/argoproj/argo-rollouts/utils/query"
+)
+
+const (
+	// ProviderType indicates the provider is kayenta
+	ProviderType = "Kayenta"
+	// CanaryAnalysisExecutionIDKey is the measurement's metadata key holding the id of the Kayenta canary analysis
+	CanaryAnalysisExecutionIDKey = "canaryAnalysisExecutionId"
+	// DefaultResumeDelay is the time to wait before polling an in-progress canary analysis
+	DefaultResumeDelay = time.Minute
+	// DefaultTimeout is the timeout of the requests to the Kayenta server
+	DefaultTimeout = 30 * time.Second
+
+	// executionStatusSucceeded is the execution status of a canary analysis which completed successfully
+	executionStatusSucceeded = "SUCCEEDED"
+)
+
+// Provider contains all the required components to run a Kayenta canary analysis
+type Provider struct {
+	client *http.Client
+	logCtx log.Entry
+}
+
+type canaryAnalysisRequest struct {
+	Scopes               []canaryAnalysisScope `json:"scopes"`
+	Thresholds           canaryThresholds      `json:"thresholds"`
+	LifetimeDuration     string                `json:"lifetimeDuration,omitempty"`
+	AnalysisIntervalMins int64                 `json:"analysisIntervalMins,omitempty"`
+}
+
+type canaryAnalysisScope struct {
+	ControlScope        string            `json:"controlScope"`
+	ControlLocation     string            `json:"controlLocation,omitempty"`
+	ExperimentScope     string            `json:"experimentScope"`
+	ExperimentLocation  string            `json:"experimentLocation,omitempty"`
+	StartTimeIso        string            `json:"startTimeIso,omitempty"`
+	EndTimeIso          string            `json:"endTimeIso,omitempty"`
+	Step                int64             `json:"step,omitempty"`
+	ExtendedScopeParams map[string]string `json:"extendedScopeParams,omitempty"`
+}
+
+type canaryThresholds struct {
+	Pass     int32 `json:"pass"`
+	Marginal int32 `json:"marginal"`
+}
+
+type canaryAnalysisResponse struct {
+	CanaryAnalysisExecutionID string `json:"canaryAnalysisExecutionId"`
+}
+
+type canaryAnalysisExecution struct {
+	Complete                      bool   `json:"complete"`
+	ExecutionStatus               string `json:"executionStatus"`
+	CanaryAnalysisExecutionResult *struct {
+		CanaryScores []float64 `json:"canaryScores"`
+	} `json:"canaryAnalysisExecutionResult"`
+}
+
+// Type indicates provider is a kayenta provider
+func (p *Provider) Type() string {
+	return ProviderType
+}
+
+// Run submits a standalone canary analysis to Kayenta and records its execution id
+func (p *Provider) Run(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args []v1alpha1.Argument) v1alpha1.Measurement {
+	startTime := metav1.Now()
+	newMeasurement := v1alpha1.Measurement{
+		StartedAt: &startTime,
+	}
+	kayenta := metric.Provider.Kayenta
+
+	body, err := newCanaryAnalysisRequest(kayenta, args, startTime.Time)
+	if err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, err)
+	}
+	endpoint, err := url.Parse(fmt.Sprintf("%s/standalone_canary_analysis/config/%s", kayenta.Address, url.PathEscape(kayenta.CanaryConfigName)))
+	if err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, err)
+	}
+	q := endpoint.Query()
+	q.Set("application", kayenta.Application)
+	q.Set("metricsAccountName", kayenta.MetricsAccountName)
+	q.Set("configurationAccountName", kayenta.ConfigurationAccountName)
+	q.Set("storageAccountName", kayenta.StorageAccountName)
+	endpoint.RawQuery = q.Encode()
+
+	bodyBytes, err := p.do(http.MethodPost, endpoint.String(), body)
+	if err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, err)
+	}
+	var res canaryAnalysisResponse
+	if err := json.Unmarshal(bodyBytes, &res); err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, fmt.Errorf("could not parse JSON body: %v", err))
+	}
+	if res.CanaryAnalysisExecutionID == "" {
+		return metricutil.MarkMeasurementError(newMeasurement, errors.New("kayenta returned no canary analysis execution id"))
+	}
+	p.logCtx.Infof("kayenta canary analysis %s started", res.CanaryAnalysisExecutionID)
+	newMeasurement.Metadata = map[string]string{
+		CanaryAnalysisExecutionIDKey: res.CanaryAnalysisExecutionID,
+	}
+	newMeasurement.Status = v1alpha1.AnalysisStatusRunning
+	resumeTime := metav1.NewTime(time.Now().Add(DefaultResumeDelay))
+	newMeasurement.ResumeAt = &resumeTime
+	return newMeasurement
+}
+
+// newCanaryAnalysisRequest builds the standalone canary analysis request. The args are injected in the scopes and
+// the scopes without a start time start at the beginning of the measurement.
+func newCanaryAnalysisRequest(kayenta *v1alpha1.KayentaMetric, args []v1alpha1.Argument, startTime time.Time) (*canaryAnalysisRequest, error) {
+	request := canaryAnalysisRequest{
+		Thresholds: canaryThresholds{
+			Pass:     kayenta.Threshold.Pass,
+			Marginal: kayenta.Threshold.Marginal,
+		},
+		LifetimeDuration:     kayenta.LifetimeDuration,
+		AnalysisIntervalMins: kayenta.AnalysisIntervalMins,
+	}
+	for _, scope := range kayenta.Scopes {
+		templated := canaryAnalysisScope{
+			Step:                scope.Step,
+			ExtendedScopeParams: map[string]string{},
+		}
+		fields := []struct {
+			dest     *string
+			template string
+		}{
+			{&templated.ControlScope, scope.ControlScope},
+			{&templated.ControlLocation, scope.ControlLocation},
+			{&templated.ExperimentScope, scope.ExperimentScope},
+			{&templated.ExperimentLocation, scope.ExperimentLocation},
+			{&templated.StartTimeIso, scope.StartTime},
+			{&templated.EndTimeIso, scope.EndTime},
+		}
+		for _, field := range fields {
+			value, err := query.BuildQuery(field.template, args)
+			if err != nil {
+				return nil, err
+			}
+			*field.dest = value
+		}
+		for key, template := range scope.ExtendedScopeParams {
+			value, err := query.BuildQuery(template, args)
+			if err != nil {
+				return nil, err
+			}
+			templated.ExtendedScopeParams[key] = value
+		}
+		if templated.StartTimeIso == "" {
+			templated.StartTimeIso = startTime.UTC().Format(time.RFC3339)
+		}
+		request.Scopes = append(request.Scopes, templated)
+	}
+	return &request, nil
+}
+
+// Resume polls the canary analysis and maps its score against the thresholds once it completes
+func (p *Provider) Resume(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args []v1alpha1.Argument, measurement v1alpha1.Measurement) v1alpha1.Measurement {
+	executionID, err := getExecutionID(measurement)
+	if err != nil {
+		return metricutil.MarkMeasurementError(measurement, err)
+	}
+	bodyBytes, err := p.do(http.MethodGet, fmt.Sprintf("%s/standalone_canary_analysis/%s", metric.Provider.Kayenta.Address, url.PathEscape(executionID)), nil)
+	if err != nil {
+		return metricutil.MarkMeasurementError(measurement, err)
+	}
+	var execution canaryAnalysisExecution
+	if err := json.Unmarshal(bodyBytes, &execution); err != nil {
+		return metricutil.MarkMeasurementError(measurement, fmt.Errorf("could not parse JSON body: %v", err))
+	}
+	if !execution.Complete {
+		resumeTime := metav1.NewTime(time.Now().Add(DefaultResumeDelay))
+		measurement.ResumeAt = &resumeTime
+		return measurement
+	}
+	if execution.ExecutionStatus != executionStatusSucceeded {
+		return metricutil.MarkMeasurementError(measurement, fmt.Errorf("kayenta canary analysis %s did not succeed: %s", executionID, execution.ExecutionStatus))
+	}
+	if execution.CanaryAnalysisExecutionResult == nil || len(execution.CanaryAnalysisExecutionResult.CanaryScores) == 0 {
+		return metricutil.MarkMeasurementError(measurement, fmt.Errorf("kayenta canary analysis %s returned no score", executionID))
+	}
+	scores := execution.CanaryAnalysisExecutionResult.CanaryScores
+	score := scores[len(scores)-1]
+	measurement.Value = strconv.FormatFloat(score, 'f', -1, 64)
+	measurement.Status = evaluateScore(score, metric.Provider.Kayenta.Threshold)
+	measurement.ResumeAt = nil
+	finishedTime := metav1.Now()
+	measurement.FinishedAt = &finishedTime
+	p.logCtx.Infof("kayenta canary analysis %s completed: %s", executionID, measurement.Status)
+	return measurement
+}
+
+// evaluateScore judges the canary score against the pass and marginal thresholds
+func evaluateScore(score float64, threshold v1alpha1.KayentaThreshold) v1alpha1.AnalysisStatus {
+	if score >= float64(threshold.Pass) {
+		return v1alpha1.AnalysisStatusSuccessful
+	}
+	if score >= float64(threshold.Marginal) {
+		return v1alpha1.AnalysisStatusInconclusive
+	}
+	return v1alpha1.AnalysisStatusFailed
+}
+
+// Terminate cancels the in-progress canary analysis
+func (p *Provider) Terminate(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args []v1alpha1.Argument, measurement v1alpha1.Measurement) v1alpha1.Measurement {
+	executionID, err := getExecutionID(measurement)
+	if err != nil {
+		return metricutil.MarkMeasurementError(measurement, err)
+	}
+	_, err = p.do(http.MethodPut, fmt.Sprintf("%s/standalone_canary_analysis/%s/cancel", metric.Provider.Kayenta.Address, url.PathEscape(executionID)), nil)
+	if err != nil {
+		return metricutil.MarkMeasurementError(measurement, err)
+	}
+	p.logCtx.Infof("kayenta canary analysis %s terminated", executionID)
+	now := metav1.Now()
+	measurement.FinishedAt = &now
+	measurement.ResumeAt = nil
+	measurement.Status = v1alpha1.AnalysisStatusSuccessful
+	return measurement
+}
+
+// do sends a request to the Kayenta server with the JSON encoded body and returns the body of the response
+func (p *Provider) do(method, endpoint string, body interface{}) ([]byte, error) {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+	request, err := http.NewRequest(method, endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	bodyBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("received non 2xx response code: %d %s", response.StatusCode, string(bodyBytes))
+	}
+	return bodyBytes, nil
+}
+
+func getExecutionID(measurement v1alpha1.Measurement) (string, error) {
+	if measurement.Metadata != nil && measurement.Metadata[CanaryAnalysisExecutionIDKey] != "" {
+		return measurement.Metadata[CanaryAnalysisExecutionIDKey], nil
+	}
+	return "", errors.New("kayenta canary analysis execution id missing")
+}
+
+// NewKayentaProvider creates a new kayenta provider
+func NewKayentaProvider(logCtx log.Entry, client *http.Client) *Provider {
+	return &Provider{
+		client: client,
+		logCtx: logCtx,
+	}
+}
@@ -0,0 +1,190 @@
+package kayenta
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newMetric(address string) v1alpha1.Metric {
+	return v1alpha1.Metric{
+		Name: "foo",
+		Provider: v1alpha1.MetricProvider{
+			Kayenta: &v1alpha1.KayentaMetric{
+				Address:                  address,
+				Application:              "guestbook",
+				CanaryConfigName:         "my-config",
+				MetricsAccountName:       "metrics",
+				ConfigurationAccountName: "configuration",
+				StorageAccountName:       "storage",
+				Threshold: v1alpha1.KayentaThreshold{
+					Pass:     90,
+					Marginal: 75,
+				},
+				Scopes: []v1alpha1.KayentaScope{
+					{
+						ControlScope:    "app=guestbook and rollouts-pod-template-hash={{input.stable-hash}}",
+						ExperimentScope: "app=guestbook and rollouts-pod-template-hash={{input.canary-hash}}",
+						Step:            60,
+						EndTime:         "2020-01-01T01:00:00Z",
+						ExtendedScopeParams: map[string]string{
+							"hash": "{{input.canary-hash}}",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newMeasurement() v1alpha1.Measurement {
+	return v1alpha1.Measurement{
+		Status: v1alpha1.AnalysisStatusRunning,
+		Metadata: map[string]string{
+			CanaryAnalysisExecutionIDKey: "01DS50WVHAWSTAQACJKB1VKDQB",
+		},
+	}
+}
+
+var args = []v1alpha1.Argument{
+	{Name: "stable-hash", Value: "xxxx"},
+	{Name: "canary-hash", Value: "yyyy"},
+}
+
+func TestType(t *testing.T) {
+	p := NewKayentaProvider(*log.NewEntry(log.New()), http.DefaultClient)
+	assert.Equal(t, ProviderType, p.Type())
+}
+
+func TestRunSuccessfully(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/standalone_canary_analysis/config/my-config", r.URL.Path)
+		assert.Equal(t, "guestbook", r.URL.Query().Get("application"))
+		assert.Equal(t, "metrics", r.URL.Query().Get("metricsAccountName"))
+		assert.Equal(t, "configuration", r.URL.Query().Get("configurationAccountName"))
+		assert.Equal(t, "storage", r.URL.Query().Get("storageAccountName"))
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		var request canaryAnalysisRequest
+		assert.NoError(t, json.Unmarshal(body, &request))
+		assert.Equal(t, canaryThresholds{Pass: 90, Marginal: 75}, request.Thresholds)
+		assert.Len(t, request.Scopes, 1)
+		scope := request.Scopes[0]
+		assert.Equal(t, "app=guestbook and rollouts-pod-template-hash=xxxx", scope.ControlScope)
+		assert.Equal(t, "app=guestbook and rollouts-pod-template-hash=yyyy", scope.ExperimentScope)
+		assert.Equal(t, "yyyy", scope.ExtendedScopeParams["hash"])
+		assert.Equal(t, int64(60), scope.Step)
+		assert.NotEmpty(t, scope.StartTimeIso)
+		assert.Equal(t, "2020-01-01T01:00:00Z", scope.EndTimeIso)
+		w.Write([]byte(`{"canaryAnalysisExecutionId": "01DS50WVHAWSTAQACJKB1VKDQB"}`))
+	}))
+	defer server.Close()
+
+	p := NewKayentaProvider(*log.NewEntry(log.New()), http.DefaultClient)
+	measurement := p.Run(nil, newMetric(server.URL), args)
+	assert.Equal(t, v1alpha1.AnalysisStatusRunning, measurement.Status)
+	assert.Equal(t, "01DS50WVHAWSTAQACJKB1VKDQB", measurement.Metadata[CanaryAnalysisExecutionIDKey])
+	assert.NotNil(t, measurement.StartedAt)
+	assert.NotNil(t, measurement.ResumeAt)
+	assert.Nil(t, measurement.FinishedAt)
+}
+
+func TestRunUnresolvedArgs(t *testing.T) {
+	p := NewKayentaProvider(*log.NewEntry(log.New()), http.DefaultClient)
+	measurement := p.Run(nil, newMetric("http://localhost"), nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "failed to resolve {{input.stable-hash}}", measurement.Message)
+}
+
+func TestRunBadResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`bad config`))
+	}))
+	defer server.Close()
+
+	p := NewKayentaProvider(*log.NewEntry(log.New()), http.DefaultClient)
+	measurement := p.Run(nil, newMetric(server.URL), args)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "received non 2xx response code: 400 bad config", measurement.Message)
+}
+
+func TestResume(t *testing.T) {
+	tests := []struct {
+		response string
+		status   v1alpha1.AnalysisStatus
+		value    string
+	}{
+		{`{"complete": true, "executionStatus": "SUCCEEDED", "canaryAnalysisExecutionResult": {"canaryScores": [80, 95]}}`, v1alpha1.AnalysisStatusSuccessful, "95"},
+		{`{"complete": true, "executionStatus": "SUCCEEDED", "canaryAnalysisExecutionResult": {"canaryScores": [80]}}`, v1alpha1.AnalysisStatusInconclusive, "80"},
+		{`{"complete": true, "executionStatus": "SUCCEEDED", "canaryAnalysisExecutionResult": {"canaryScores": [50.5]}}`, v1alpha1.AnalysisStatusFailed, "50.5"},
+	}
+	for _, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/standalone_canary_analysis/01DS50WVHAWSTAQACJKB1VKDQB", r.URL.Path)
+			w.Write([]byte(test.response))
+		}))
+
+		p := NewKayentaProvider(*log.NewEntry(log.New()), http.DefaultClient)
+		measurement := p.Resume(nil, newMetric(server.URL), args, newMeasurement())
+		assert.Equal(t, test.status, measurement.Status)
+		assert.Equal(t, test.value, measurement.Value)
+		assert.NotNil(t, measurement.FinishedAt)
+		assert.Nil(t, measurement.ResumeAt)
+		server.Close()
+	}
+}
+
+func TestResumeInProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"complete": false, "executionStatus": "RUNNING"}`))
+	}))
+	defer server.Close()
+
+	p := NewKayentaProvider(*log.NewEntry(log.New()), http.DefaultClient)
+	measurement := p.Resume(nil, newMetric(server.URL), args, newMeasurement())
+	assert.Equal(t, v1alpha1.AnalysisStatusRunning, measurement.Status)
+	assert.NotNil(t, measurement.ResumeAt)
+	assert.Nil(t, measurement.FinishedAt)
+}
+
+func TestResumeExecutionFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"complete": true, "executionStatus": "TERMINAL"}`))
+	}))
+	defer server.Close()
+
+	p := NewKayentaProvider(*log.NewEntry(log.New()), http.DefaultClient)
+	measurement := p.Resume(nil, newMetric(server.URL), args, newMeasurement())
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "kayenta canary analysis 01DS50WVHAWSTAQACJKB1VKDQB did not succeed: TERMINAL", measurement.Message)
+}
+
+func TestResumeMissingExecutionID(t *testing.T) {
+	p := NewKayentaProvider(*log.NewEntry(log.New()), http.DefaultClient)
+	measurement := p.Resume(nil, newMetric("http://localhost"), args, v1alpha1.Measurement{})
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "kayenta canary analysis execution id missing", measurement.Message)
+}
+
+func TestTerminate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/standalone_canary_analysis/01DS50WVHAWSTAQACJKB1VKDQB/cancel", r.URL.Path)
+	}))
+	defer server.Close()
+
+	p := NewKayentaProvider(*log.NewEntry(log.New()), http.DefaultClient)
+	measurement := p.Terminate(nil, newMetric(server.URL), args, newMeasurement())
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, measurement.Status)
+	assert.NotNil(t, measurement.FinishedAt)
+}
@@ -2,13 +2,17 @@ package metricproviders
 
 import (
 	"fmt"
+	"net/http"
 
 	log "github.com/sirupsen/logrus"
 	"k8s.io/client-go/kubernetes"
 	batchlisters "k8s.io/client-go/listers/batch/v1"
 
+	"github.com/argoproj/argo-rollouts/metricproviders/datadog"
 	"github.com/argoproj/argo-rollouts/metricproviders/job"
+	"github.com/argoproj/argo-rollouts/metricproviders/kayenta"
 	"github.com/argoproj/argo-rollouts/metricproviders/prometheus"
+	"github.com/argoproj/argo-rollouts/metricproviders/webmetric"
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 )
 
@@ -37,9 +41,19 @@ func (f *ProviderFactory) NewProvider(logCtx log.Entry, metric v1alpha1.Metric)
 		if err != nil {
 			return nil, err
 		}
-		return prometheus.NewPrometheusProvider(api, logCtx), nil
+		return prometheus.NewPrometheusProvider(api, logCtx, f.KubeClient), nil
 	} else if metric.Provider.Job != nil {
 		return job.NewJobProvider(logCtx, f.KubeClient, f.JobLister), nil
+	} else if metric.Provider.Web != nil {
+		client := webmetric.NewWebMetricHTTPClient(metric)
+		return webmetric.NewWebMetricProvider(logCtx, client, f.KubeClient), nil
+	} else if metric.Provider.Datadog != nil {
+		return datadog.NewDatadogProvider(logCtx, f.KubeClient)
+	} else if metric.Provider.Kayenta != nil {
+		client := &http.Client{
+			Timeout: kayenta.DefaultTimeout,
+		}
+		return kayenta.NewKayentaProvider(logCtx, client), nil
 	}
 	return nil, fmt.Errorf("no valid provider in metric '%s'", metric.Name)
 }
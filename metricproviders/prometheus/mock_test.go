@@ -54,7 +54,10 @@ func (m mockAPI) LabelValues(ctx context.Context, label string) (model.LabelValu
 
 // QueryRange performs a query for the given range.
 func (m mockAPI) QueryRange(ctx context.Context, query string, r v1.Range) (model.Value, error) {
-	return nil, nil
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.value, nil
 }
 
 // Series finds series by label matchers.
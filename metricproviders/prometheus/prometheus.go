@@ -2,7 +2,12 @@ package prometheus
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/api"
@@ -10,6 +15,7 @@ import (
 	"github.com/prometheus/common/model"
 	log "github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/utils/evaluate"
@@ -20,12 +26,21 @@ import (
 const (
 	//ProviderType indicates the provider is prometheus
 	ProviderType = "Prometheus"
+	// DefaultTimeoutSeconds is the default timeout of the prometheus query
+	DefaultTimeoutSeconds = 30
 )
 
+// headersKey is the context key of the HTTP headers added to the prometheus requests
+type headersKey struct{}
+
+// transportKey is the context key of the transport used to send the prometheus requests
+type transportKey struct{}
+
 // Provider contains all the required components to run a prometheus query
 type Provider struct {
-	api    v1.API
-	logCtx log.Entry
+	api           v1.API
+	kubeclientset kubernetes.Interface
+	logCtx        log.Entry
 }
 
 // Type incidates provider is a prometheus provider
@@ -40,20 +55,53 @@ func (p *Provider) Run(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args [
 		StartedAt: &startTime,
 	}
 
-	//TODO(dthomson) make timeout configuriable
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	prom := metric.Provider.Prometheus
+	timeoutSeconds := prom.TimeoutSeconds
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = DefaultTimeoutSeconds
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutSeconds)*time.Second)
 	defer cancel()
 
-	query, err := query.BuildQuery(metric.Provider.Prometheus.Query, args)
+	headers, err := p.requestHeaders(run, prom, args)
+	if err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, err)
+	}
+	if len(headers) > 0 {
+		ctx = context.WithValue(ctx, headersKey{}, headers)
+	}
+
+	transport, err := p.tlsTransport(run, prom)
 	if err != nil {
 		return metricutil.MarkMeasurementError(newMeasurement, err)
 	}
+	if transport != nil {
+		defer transport.CloseIdleConnections()
+		ctx = context.WithValue(ctx, transportKey{}, transport)
+	}
 
-	response, err := p.api.Query(ctx, query, time.Now())
+	query, err := query.BuildQuery(prom.Query, args)
 	if err != nil {
 		return metricutil.MarkMeasurementError(newMeasurement, err)
 	}
 
+	var response model.Value
+	if prom.Range != nil {
+		r, err := newRange(prom.Range, time.Now())
+		if err != nil {
+			return metricutil.MarkMeasurementError(newMeasurement, err)
+		}
+		response, err = p.api.QueryRange(ctx, query, r)
+		if err != nil {
+			return metricutil.MarkMeasurementError(newMeasurement, err)
+		}
+	} else {
+		response, err = p.api.Query(ctx, query, time.Now())
+		if err != nil {
+			return metricutil.MarkMeasurementError(newMeasurement, err)
+		}
+	}
+
 	newValue, newStatus, err := p.processResponse(metric, response)
 	if err != nil {
 		return metricutil.MarkMeasurementError(newMeasurement, err)
@@ -67,6 +115,130 @@ func (p *Provider) Run(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args [
 	return newMeasurement
 }
 
+// newRange converts the offsets of the range relative to now into the time range of the query
+func newRange(promRange *v1alpha1.PrometheusRange, now time.Time) (v1.Range, error) {
+	start, err := model.ParseDuration(promRange.Start)
+	if err != nil {
+		return v1.Range{}, fmt.Errorf("invalid range start '%s': %v", promRange.Start, err)
+	}
+	var end model.Duration
+	if promRange.End != "" {
+		end, err = model.ParseDuration(promRange.End)
+		if err != nil {
+			return v1.Range{}, fmt.Errorf("invalid range end '%s': %v", promRange.End, err)
+		}
+	}
+	step, err := model.ParseDuration(promRange.Step)
+	if err != nil {
+		return v1.Range{}, fmt.Errorf("invalid range step '%s': %v", promRange.Step, err)
+	}
+	if step <= 0 {
+		return v1.Range{}, fmt.Errorf("range step '%s' must be greater than 0", promRange.Step)
+	}
+	if end > start {
+		return v1.Range{}, fmt.Errorf("range end '%s' is before range start '%s'", promRange.End, promRange.Start)
+	}
+	return v1.Range{
+		Start: now.Add(-time.Duration(start)),
+		End:   now.Add(-time.Duration(end)),
+		Step:  time.Duration(step),
+	}, nil
+}
+
+// requestHeaders returns the custom headers and the authorization header to add to the prometheus requests
+func (p *Provider) requestHeaders(run *v1alpha1.AnalysisRun, prom *v1alpha1.PrometheusMetric, args []v1alpha1.Argument) (http.Header, error) {
+	headers := http.Header{}
+	for _, header := range prom.Headers {
+		var value string
+		var err error
+		if header.ValueFrom != nil && header.ValueFrom.SecretKeyRef != nil {
+			value, err = p.secretValue(run, *header.ValueFrom.SecretKeyRef)
+		} else {
+			value, err = query.BuildQuery(header.Value, args)
+		}
+		if err != nil {
+			return nil, err
+		}
+		headers.Set(header.Key, value)
+	}
+	if auth := prom.Authentication; auth != nil {
+		if auth.BearerToken != nil && auth.BasicAuth != nil {
+			return nil, fmt.Errorf("bearerToken and basicAuth are mutually exclusive")
+		}
+		if auth.BearerToken != nil {
+			token, err := p.secretValue(run, *auth.BearerToken)
+			if err != nil {
+				return nil, err
+			}
+			headers.Set("Authorization", "Bearer "+strings.TrimSpace(token))
+		}
+		if auth.BasicAuth != nil {
+			password, err := p.secretValue(run, auth.BasicAuth.Password)
+			if err != nil {
+				return nil, err
+			}
+			credentials := base64.StdEncoding.EncodeToString([]byte(auth.BasicAuth.Username + ":" + password))
+			headers.Set("Authorization", "Basic "+credentials)
+		}
+	}
+	return headers, nil
+}
+
+// tlsTransport returns a transport using the certificates of the TLS configuration of the metric, or nil if the
+// metric has no TLS configuration
+func (p *Provider) tlsTransport(run *v1alpha1.AnalysisRun, prom *v1alpha1.PrometheusMetric) (*http.Transport, error) {
+	if prom.TLS == nil {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: prom.Insecure}
+	if prom.TLS.CA != nil {
+		ca, err := p.secretValue(run, *prom.TLS.CA)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(ca)) {
+			return nil, fmt.Errorf("key '%s' of secret '%s' does not hold a PEM encoded certificate", prom.TLS.CA.Key, prom.TLS.CA.Name)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if (prom.TLS.Cert == nil) != (prom.TLS.Key == nil) {
+		return nil, fmt.Errorf("tls cert and key must be set together")
+	}
+	if prom.TLS.Cert != nil {
+		cert, err := p.secretValue(run, *prom.TLS.Cert)
+		if err != nil {
+			return nil, err
+		}
+		key, err := p.secretValue(run, *prom.TLS.Key)
+		if err != nil {
+			return nil, err
+		}
+		certificate, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
+	}, nil
+}
+
+// secretValue returns the value of the key of a secret in the namespace of the AnalysisRun
+func (p *Provider) secretValue(run *v1alpha1.AnalysisRun, ref v1alpha1.SecretKeyRef) (string, error) {
+	secret, err := p.kubeclientset.CoreV1().Secrets(run.Namespace).Get(ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	value, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key '%s' does not exist in secret '%s'", ref.Key, ref.Name)
+	}
+	return string(value), nil
+}
+
 // Resume should not be used the prometheus provider since all the work should occur in the Run method
 func (p *Provider) Resume(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args []v1alpha1.Argument, measurement v1alpha1.Measurement) v1alpha1.Measurement {
 	p.logCtx.Warn("Prometheus provider should not execute the Resume method")
@@ -80,26 +252,7 @@ func (p *Provider) Terminate(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric,
 }
 
 func (p *Provider) evaluateResult(result interface{}, metric v1alpha1.Metric) v1alpha1.AnalysisStatus {
-	successCondition, err := evaluate.EvalCondition(result, metric.SuccessCondition)
-	if err != nil {
-		p.logCtx.Warning(err.Error())
-		return v1alpha1.AnalysisStatusError
-	}
-
-	failCondition, err := evaluate.EvalCondition(result, metric.FailureCondition)
-	if err != nil {
-		return v1alpha1.AnalysisStatusError
-	}
-	if failCondition {
-		return v1alpha1.AnalysisStatusFailed
-	}
-
-	if !failCondition && !successCondition {
-		return v1alpha1.AnalysisStatusInconclusive
-	}
-
-	// If we reach this code path, failCondition is false and successCondition is true
-	return v1alpha1.AnalysisStatusSuccessful
+	return evaluate.EvaluateResult(result, metric, p.logCtx)
 }
 
 func (p *Provider) processResponse(metric v1alpha1.Metric, response model.Value) (string, v1alpha1.AnalysisStatus, error) {
@@ -125,24 +278,79 @@ func (p *Provider) processResponse(metric v1alpha1.Metric, response model.Value)
 		valueStr = valueStr + "]"
 		newStatus := p.evaluateResult(result, metric)
 		return valueStr, newStatus, nil
-	//TODO(dthomson) add other response types
+	case model.Matrix:
+		result := make([][]float64, 0, len(value))
+		seriesStrs := make([]string, 0, len(value))
+		for _, series := range value {
+			if series == nil {
+				continue
+			}
+			seriesResult := make([]float64, 0, len(series.Values))
+			valueStrs := make([]string, 0, len(series.Values))
+			for _, pair := range series.Values {
+				seriesResult = append(seriesResult, float64(pair.Value))
+				valueStrs = append(valueStrs, pair.Value.String())
+			}
+			result = append(result, seriesResult)
+			seriesStrs = append(seriesStrs, "["+strings.Join(valueStrs, ",")+"]")
+		}
+		valueStr := "[" + strings.Join(seriesStrs, ",") + "]"
+		newStatus := p.evaluateResult(result, metric)
+		return valueStr, newStatus, nil
 	default:
 		return "", v1alpha1.AnalysisStatusError, fmt.Errorf("Prometheus metric type not supported")
 	}
 }
 
 // NewPrometheusProvider Creates a new Prometheus client
-func NewPrometheusProvider(api v1.API, logCtx log.Entry) *Provider {
+func NewPrometheusProvider(api v1.API, logCtx log.Entry, kubeclientset kubernetes.Interface) *Provider {
 	return &Provider{
-		logCtx: logCtx,
-		api:    api,
+		logCtx:        logCtx,
+		api:           api,
+		kubeclientset: kubeclientset,
+	}
+}
+
+// headerRoundTripper adds the headers stored in the context of the request before sending it. The request is sent
+// with the transport stored in the context, if any.
+type headerRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := rt.next
+	if transport, ok := req.Context().Value(transportKey{}).(http.RoundTripper); ok {
+		next = transport
+	}
+	headers, ok := req.Context().Value(headersKey{}).(http.Header)
+	if !ok || len(headers) == 0 {
+		return next.RoundTrip(req)
 	}
+	// RoundTrippers must not modify the request so the headers are set on a copy
+	newReq := new(http.Request)
+	*newReq = *req
+	newReq.Header = make(http.Header, len(req.Header)+len(headers))
+	for key, values := range req.Header {
+		newReq.Header[key] = values
+	}
+	for key, values := range headers {
+		newReq.Header[key] = values
+	}
+	return next.RoundTrip(newReq)
 }
 
 // NewPrometheusAPI generates a prometheus API from the metric configuration
 func NewPrometheusAPI(metric v1alpha1.Metric) (v1.API, error) {
+	transport := api.DefaultRoundTripper
+	if metric.Provider.Prometheus.Insecure {
+		transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
 	client, err := api.NewClient(api.Config{
-		Address: metric.Provider.Prometheus.Server,
+		Address:      metric.Provider.Prometheus.Server,
+		RoundTripper: &headerRoundTripper{next: transport},
 	})
 	if err != nil {
 		return nil, err
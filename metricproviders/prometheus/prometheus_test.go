@@ -1,13 +1,20 @@
 package prometheus
 
 import (
+	"context"
+	"encoding/pem"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/prometheus/common/model"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 )
@@ -24,7 +31,7 @@ func TestType(t *testing.T) {
 	mock := mockAPI{
 		value: newScalar(10),
 	}
-	p := NewPrometheusProvider(mock, e)
+	p := NewPrometheusProvider(mock, e, nil)
 	assert.Equal(t, ProviderType, p.Type())
 }
 
@@ -33,7 +40,7 @@ func TestRunSuccessfully(t *testing.T) {
 	mock := mockAPI{
 		value: newScalar(10),
 	}
-	p := NewPrometheusProvider(mock, e)
+	p := NewPrometheusProvider(mock, e, nil)
 	metric := v1alpha1.Metric{
 		Name:             "foo",
 		SuccessCondition: "result == 10",
@@ -57,7 +64,7 @@ func TestRunWithQueryError(t *testing.T) {
 	mock := mockAPI{
 		err: expectedErr,
 	}
-	p := NewPrometheusProvider(mock, e)
+	p := NewPrometheusProvider(mock, e, nil)
 	metric := v1alpha1.Metric{
 		Name:             "foo",
 		SuccessCondition: "result == 10",
@@ -82,7 +89,7 @@ func TestRunWithBuildQueryError(t *testing.T) {
 	mock := mockAPI{
 		err: expectedErr,
 	}
-	p := NewPrometheusProvider(mock, e)
+	p := NewPrometheusProvider(mock, e, nil)
 	metric := v1alpha1.Metric{
 		Name: "foo",
 		Provider: v1alpha1.MetricProvider{
@@ -102,7 +109,7 @@ func TestRunWithBuildQueryError(t *testing.T) {
 func TestRunWithEvaluationError(t *testing.T) {
 	e := log.WithField("", "")
 	mock := mockAPI{}
-	p := NewPrometheusProvider(mock, *e)
+	p := NewPrometheusProvider(mock, *e, nil)
 	metric := v1alpha1.Metric{
 		Name:             "foo",
 		SuccessCondition: "result == 10",
@@ -124,7 +131,7 @@ func TestRunWithEvaluationError(t *testing.T) {
 func TestResume(t *testing.T) {
 	e := log.WithField("", "")
 	mock := mockAPI{}
-	p := NewPrometheusProvider(mock, *e)
+	p := NewPrometheusProvider(mock, *e, nil)
 	metric := v1alpha1.Metric{
 		Name:             "foo",
 		SuccessCondition: "result == 10",
@@ -147,7 +154,7 @@ func TestResume(t *testing.T) {
 func TestTerminate(t *testing.T) {
 	e := log.NewEntry(log.New())
 	mock := mockAPI{}
-	p := NewPrometheusProvider(mock, *e)
+	p := NewPrometheusProvider(mock, *e, nil)
 	metric := v1alpha1.Metric{}
 	now := metav1.Now()
 	previousMeasurement := v1alpha1.Measurement{
@@ -266,6 +273,242 @@ func TestProcessVectorResponse(t *testing.T) {
 
 }
 
+func TestProcessMatrixResponse(t *testing.T) {
+	logCtx := log.WithField("test", "test")
+	p := Provider{
+		logCtx: *logCtx,
+	}
+	metric := v1alpha1.Metric{
+		SuccessCondition: "len(result) == 2 && result[1][1] == 4",
+		FailureCondition: "false",
+	}
+
+	response := model.Matrix{
+		{
+			Values: []model.SamplePair{
+				{Value: model.SampleValue(1), Timestamp: model.Time(0)},
+				{Value: model.SampleValue(2), Timestamp: model.Time(1)},
+			},
+		},
+		{
+			Values: []model.SamplePair{
+				{Value: model.SampleValue(3), Timestamp: model.Time(0)},
+				{Value: model.SampleValue(4), Timestamp: model.Time(1)},
+			},
+		},
+	}
+	value, status, err := p.processResponse(metric, response)
+	assert.Nil(t, err)
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, status)
+	assert.Equal(t, "[[1,2],[3,4]]", value)
+}
+
+func TestRunRangeQuery(t *testing.T) {
+	e := log.Entry{}
+	mock := mockAPI{
+		value: model.Matrix{
+			{
+				Values: []model.SamplePair{
+					{Value: model.SampleValue(10), Timestamp: model.Time(0)},
+				},
+			},
+		},
+	}
+	p := NewPrometheusProvider(mock, e, nil)
+	metric := v1alpha1.Metric{
+		Name:             "foo",
+		SuccessCondition: "result[0][0] == 10",
+		FailureCondition: "result[0][0] != 10",
+		Provider: v1alpha1.MetricProvider{
+			Prometheus: &v1alpha1.PrometheusMetric{
+				Query: "test",
+				Range: &v1alpha1.PrometheusRange{
+					Start: "10m",
+					Step:  "1m",
+				},
+			},
+		},
+	}
+	measurement := p.Run(nil, metric, []v1alpha1.Argument{})
+	assert.Equal(t, "[[10]]", measurement.Value)
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, measurement.Status)
+
+	metric.Provider.Prometheus.Range.Step = "invalid"
+	measurement = p.Run(nil, metric, []v1alpha1.Argument{})
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Contains(t, measurement.Message, "invalid range step 'invalid'")
+}
+
+func TestNewRange(t *testing.T) {
+	now := time.Unix(10000, 0)
+	r, err := newRange(&v1alpha1.PrometheusRange{Start: "1h", End: "10m", Step: "30s"}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, now.Add(-time.Hour), r.Start)
+	assert.Equal(t, now.Add(-10*time.Minute), r.End)
+	assert.Equal(t, 30*time.Second, r.Step)
+
+	r, err = newRange(&v1alpha1.PrometheusRange{Start: "5m", Step: "1m"}, now)
+	assert.Nil(t, err)
+	assert.Equal(t, now, r.End)
+
+	_, err = newRange(&v1alpha1.PrometheusRange{Start: "5m", End: "10m", Step: "1m"}, now)
+	assert.EqualError(t, err, "range end '10m' is before range start '5m'")
+
+	_, err = newRange(&v1alpha1.PrometheusRange{Start: "5m", Step: "0s"}, now)
+	assert.EqualError(t, err, "range step '0s' must be greater than 0")
+}
+
+func TestRequestHeaders(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string][]byte{
+			"token":    []byte("my-token"),
+			"password": []byte("my-password"),
+			"tenant":   []byte("my-tenant"),
+		},
+	}
+	p := NewPrometheusProvider(mockAPI{}, log.Entry{}, k8sfake.NewSimpleClientset(secret))
+	run := &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	secretRef := func(key string) *v1alpha1.SecretKeyRef {
+		return &v1alpha1.SecretKeyRef{Name: "prometheus", Key: key}
+	}
+	args := []v1alpha1.Argument{{Name: "service", Value: "guestbook"}}
+
+	prom := &v1alpha1.PrometheusMetric{
+		Headers: []v1alpha1.WebMetricHeader{
+			{Key: "X-Scope-OrgID", ValueFrom: &v1alpha1.WebMetricHeaderValueFrom{SecretKeyRef: secretRef("tenant")}},
+			{Key: "X-Service", Value: "{{input.service}}"},
+		},
+		Authentication: &v1alpha1.PrometheusAuthentication{
+			BearerToken: secretRef("token"),
+		},
+	}
+	headers, err := p.requestHeaders(run, prom, args)
+	assert.Nil(t, err)
+	assert.Equal(t, "my-tenant", headers.Get("X-Scope-OrgID"))
+	assert.Equal(t, "guestbook", headers.Get("X-Service"))
+	assert.Equal(t, "Bearer my-token", headers.Get("Authorization"))
+
+	prom.Authentication = &v1alpha1.PrometheusAuthentication{
+		BasicAuth: &v1alpha1.PrometheusBasicAuth{
+			Username: "admin",
+			Password: *secretRef("password"),
+		},
+	}
+	headers, err = p.requestHeaders(run, prom, args)
+	assert.Nil(t, err)
+	req := &http.Request{Header: headers}
+	username, password, ok := req.BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "admin", username)
+	assert.Equal(t, "my-password", password)
+
+	prom.Authentication.BearerToken = secretRef("token")
+	_, err = p.requestHeaders(run, prom, args)
+	assert.EqualError(t, err, "bearerToken and basicAuth are mutually exclusive")
+
+	prom.Authentication = &v1alpha1.PrometheusAuthentication{
+		BearerToken: secretRef("missing"),
+	}
+	_, err = p.requestHeaders(run, prom, args)
+	assert.EqualError(t, err, "key 'missing' does not exist in secret 'prometheus'")
+}
+
+func TestRunWithTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(rw, `{"status":"success","data":{"resultType":"scalar","result":[0,"10"]}}`)
+	}))
+	defer ts.Close()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "prometheus-tls",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string][]byte{
+			"ca.crt":  ca,
+			"invalid": []byte("invalid"),
+		},
+	}
+	run := &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	metric := v1alpha1.Metric{
+		Name:             "foo",
+		SuccessCondition: "result == 10",
+		Provider: v1alpha1.MetricProvider{
+			Prometheus: &v1alpha1.PrometheusMetric{
+				Server: ts.URL,
+				Query:  "test",
+			},
+		},
+	}
+	api, err := NewPrometheusAPI(metric)
+	assert.Nil(t, err)
+	p := NewPrometheusProvider(api, log.Entry{}, k8sfake.NewSimpleClientset(secret))
+
+	// The certificate of the server is not signed by a system CA
+	measurement := p.Run(run, metric, []v1alpha1.Argument{})
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+
+	metric.Provider.Prometheus.TLS = &v1alpha1.PrometheusTLSConfig{
+		CA: &v1alpha1.SecretKeyRef{Name: "prometheus-tls", Key: "ca.crt"},
+	}
+	measurement = p.Run(run, metric, []v1alpha1.Argument{})
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, measurement.Status)
+	assert.Equal(t, "10", measurement.Value)
+
+	metric.Provider.Prometheus.TLS.CA.Key = "invalid"
+	measurement = p.Run(run, metric, []v1alpha1.Argument{})
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "key 'invalid' of secret 'prometheus-tls' does not hold a PEM encoded certificate", measurement.Message)
+
+	metric.Provider.Prometheus.TLS = &v1alpha1.PrometheusTLSConfig{
+		Cert: &v1alpha1.SecretKeyRef{Name: "prometheus-tls", Key: "ca.crt"},
+	}
+	measurement = p.Run(run, metric, []v1alpha1.Argument{})
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "tls cert and key must be set together", measurement.Message)
+
+	metric.Provider.Prometheus.TLS.Key = &v1alpha1.SecretKeyRef{Name: "prometheus-tls", Key: "invalid"}
+	measurement = p.Run(run, metric, []v1alpha1.Argument{})
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Contains(t, measurement.Message, "invalid client certificate")
+}
+
+func TestHeaderRoundTripper(t *testing.T) {
+	var receivedHeaders http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		receivedHeaders = req.Header
+	}))
+	defer ts.Close()
+
+	rt := &headerRoundTripper{next: http.DefaultTransport}
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer my-token")
+	ctx := context.WithValue(context.Background(), headersKey{}, headers)
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	assert.Nil(t, err)
+	req.Header.Set("Accept", "application/json")
+	resp, err := rt.RoundTrip(req.WithContext(ctx))
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "Bearer my-token", receivedHeaders.Get("Authorization"))
+	assert.Equal(t, "application/json", receivedHeaders.Get("Accept"))
+	// the original request is left untouched
+	assert.Equal(t, "", req.Header.Get("Authorization"))
+}
+
 func TestProcessInvalidResponse(t *testing.T) {
 	logCtx := log.WithField("test", "test")
 	p := Provider{
@@ -298,3 +541,16 @@ func TestNewPrometheusAPI(t *testing.T) {
 	_, err = NewPrometheusAPI(metric)
 	assert.Nil(t, err)
 }
+
+func TestNewPrometheusAPIInsecure(t *testing.T) {
+	metric := v1alpha1.Metric{
+		Provider: v1alpha1.MetricProvider{
+			Prometheus: &v1alpha1.PrometheusMetric{
+				Server:   "https://www.example.com",
+				Insecure: true,
+			},
+		},
+	}
+	_, err := NewPrometheusAPI(metric)
+	assert.Nil(t, err)
+}
@@ -0,0 +1,205 @@
+package webmetric
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/jsonpath"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/evaluate"
+	metricutil "github.com/argoproj/argo-rollouts/utils/metric"
+	"github.com/argoproj/argo-rollouts/utils/query"
+)
+
+const (
+	// ProviderType indicates the provider is a web metric
+	ProviderType = "Web"
+	// DefaultTimeoutSeconds is the default timeout of the web metric request
+	DefaultTimeoutSeconds = 10
+	// DefaultJSONPath is the default JSON Path expression which selects the whole response
+	DefaultJSONPath = "{$}"
+)
+
+// Provider contains all the required components to run a web metric
+type Provider struct {
+	client        *http.Client
+	kubeclientset kubernetes.Interface
+	logCtx        log.Entry
+}
+
+// Type indicates provider is a web metric provider
+func (p *Provider) Type() string {
+	return ProviderType
+}
+
+// Run performs the HTTP request and evaluates the value extracted from the JSON response
+func (p *Provider) Run(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args []v1alpha1.Argument) v1alpha1.Measurement {
+	startTime := metav1.Now()
+	newMeasurement := v1alpha1.Measurement{
+		StartedAt: &startTime,
+	}
+
+	request, err := p.newRequest(run, metric.Provider.Web, args)
+	if err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, err)
+	}
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return metricutil.MarkMeasurementError(newMeasurement, fmt.Errorf("received non 2xx response code: %d", response.StatusCode))
+	}
+
+	newValue, newStatus, err := p.processResponse(metric, response)
+	if err != nil {
+		return metricutil.MarkMeasurementError(newMeasurement, err)
+	}
+	newMeasurement.Value = newValue
+	newMeasurement.Status = newStatus
+	finishedTime := metav1.Now()
+	newMeasurement.FinishedAt = &finishedTime
+	return newMeasurement
+}
+
+// newRequest builds the HTTP request of the web metric. The args are injected in the URL, the header values and the body.
+func (p *Provider) newRequest(run *v1alpha1.AnalysisRun, web *v1alpha1.WebMetric, args []v1alpha1.Argument) (*http.Request, error) {
+	method := web.Method
+	if method == "" {
+		method = v1alpha1.WebMetricMethodGet
+	}
+	if method != v1alpha1.WebMetricMethodGet && method != v1alpha1.WebMetricMethodPost {
+		return nil, fmt.Errorf("unsupported method '%s'", method)
+	}
+	url, err := query.BuildQuery(web.URL, args)
+	if err != nil {
+		return nil, err
+	}
+	var body []byte
+	if method == v1alpha1.WebMetricMethodPost && web.Body != "" {
+		bodyStr, err := query.BuildQuery(web.Body, args)
+		if err != nil {
+			return nil, err
+		}
+		body = []byte(bodyStr)
+	}
+	request, err := http.NewRequest(string(method), url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for _, header := range web.Headers {
+		value, err := p.headerValue(run, header, args)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set(header.Key, value)
+	}
+	return request, nil
+}
+
+// headerValue returns the value of the header from the secret it references or its templated value
+func (p *Provider) headerValue(run *v1alpha1.AnalysisRun, header v1alpha1.WebMetricHeader, args []v1alpha1.Argument) (string, error) {
+	if header.ValueFrom != nil && header.ValueFrom.SecretKeyRef != nil {
+		ref := header.ValueFrom.SecretKeyRef
+		secret, err := p.kubeclientset.CoreV1().Secrets(run.Namespace).Get(ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			return "", fmt.Errorf("key '%s' does not exist in secret '%s'", ref.Key, ref.Name)
+		}
+		return string(value), nil
+	}
+	return query.BuildQuery(header.Value, args)
+}
+
+func (p *Provider) processResponse(metric v1alpha1.Metric, response *http.Response) (string, v1alpha1.AnalysisStatus, error) {
+	bodyBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", v1alpha1.AnalysisStatusError, fmt.Errorf("failed to read response body: %v", err)
+	}
+	var data interface{}
+	if err := json.Unmarshal(bodyBytes, &data); err != nil {
+		return "", v1alpha1.AnalysisStatusError, fmt.Errorf("could not parse JSON body: %v", err)
+	}
+
+	jsonPath := metric.Provider.Web.JSONPath
+	if jsonPath == "" {
+		jsonPath = DefaultJSONPath
+	}
+	jp := jsonpath.New("metrics")
+	if err := jp.Parse(jsonPath); err != nil {
+		return "", v1alpha1.AnalysisStatusError, err
+	}
+	fullResults, err := jp.FindResults(data)
+	if err != nil {
+		return "", v1alpha1.AnalysisStatusError, fmt.Errorf("could not find JSONPath in body: %v", err)
+	}
+	if len(fullResults) == 0 || len(fullResults[0]) == 0 {
+		return "", v1alpha1.AnalysisStatusError, fmt.Errorf("no results found for JSONPath '%s'", jsonPath)
+	}
+	result := fullResults[0][0].Interface()
+
+	var valueStr string
+	if str, ok := result.(string); ok {
+		valueStr = str
+	} else {
+		valueBytes, err := json.Marshal(result)
+		if err != nil {
+			return "", v1alpha1.AnalysisStatusError, err
+		}
+		valueStr = strings.TrimSpace(string(valueBytes))
+	}
+	return valueStr, evaluate.EvaluateResult(result, metric, p.logCtx), nil
+}
+
+// Resume should not be used the web metric provider since all the work should occur in the Run method
+func (p *Provider) Resume(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args []v1alpha1.Argument, measurement v1alpha1.Measurement) v1alpha1.Measurement {
+	p.logCtx.Warn("Web metric provider should not execute the Resume method")
+	return measurement
+}
+
+// Terminate should not be used the web metric provider since all the work should occur in the Run method
+func (p *Provider) Terminate(run *v1alpha1.AnalysisRun, metric v1alpha1.Metric, args []v1alpha1.Argument, measurement v1alpha1.Measurement) v1alpha1.Measurement {
+	p.logCtx.Warn("Web metric provider should not execute the Terminate method")
+	return measurement
+}
+
+// NewWebMetricHTTPClient returns an HTTP client configured with the timeout and TLS settings of the metric
+func NewWebMetricHTTPClient(metric v1alpha1.Metric) *http.Client {
+	timeoutSeconds := metric.Provider.Web.TimeoutSeconds
+	if timeoutSeconds <= 0 {
+		timeoutSeconds = DefaultTimeoutSeconds
+	}
+	client := &http.Client{
+		Timeout: time.Duration(timeoutSeconds) * time.Second,
+	}
+	if metric.Provider.Web.Insecure {
+		client.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
+	return client
+}
+
+// NewWebMetricProvider creates a new web metric provider
+func NewWebMetricProvider(logCtx log.Entry, client *http.Client, kubeclientset kubernetes.Interface) *Provider {
+	return &Provider{
+		client:        client,
+		kubeclientset: kubeclientset,
+		logCtx:        logCtx,
+	}
+}
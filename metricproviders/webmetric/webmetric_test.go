@@ -0,0 +1,203 @@
+package webmetric
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newAnalysisRun() *v1alpha1.AnalysisRun {
+	return &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "run",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+}
+
+func newMetric(web *v1alpha1.WebMetric) v1alpha1.Metric {
+	return v1alpha1.Metric{
+		Name:             "foo",
+		SuccessCondition: "result > 0.9",
+		FailureCondition: "result <= 0.9",
+		Provider: v1alpha1.MetricProvider{
+			Web: web,
+		},
+	}
+}
+
+func newProvider(metric v1alpha1.Metric, objects ...runtime.Object) *Provider {
+	kubeclientset := fake.NewSimpleClientset(objects...)
+	return NewWebMetricProvider(*log.NewEntry(log.New()), NewWebMetricHTTPClient(metric), kubeclientset)
+}
+
+func TestType(t *testing.T) {
+	p := NewWebMetricProvider(*log.NewEntry(log.New()), nil, nil)
+	assert.Equal(t, ProviderType, p.Type())
+}
+
+func TestRunSuccessfully(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/api/v1/success-rate/abc123", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Accept"))
+		w.Write([]byte(`{"data": {"ok": true, "successPercent": 0.95}}`))
+	}))
+	defer server.Close()
+
+	metric := newMetric(&v1alpha1.WebMetric{
+		URL:      server.URL + "/api/v1/success-rate/{{input.hash}}",
+		Headers:  []v1alpha1.WebMetricHeader{{Key: "Accept", Value: "application/json"}},
+		JSONPath: "{$.data.successPercent}",
+	})
+	p := newProvider(metric)
+	measurement := p.Run(newAnalysisRun(), metric, []v1alpha1.Argument{{Name: "hash", Value: "abc123"}})
+	assert.NotNil(t, measurement.StartedAt)
+	assert.NotNil(t, measurement.FinishedAt)
+	assert.Equal(t, "0.95", measurement.Value)
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, measurement.Status)
+}
+
+func TestRunFailedCondition(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"successPercent": 0.5}`))
+	}))
+	defer server.Close()
+
+	metric := newMetric(&v1alpha1.WebMetric{
+		URL:      server.URL,
+		JSONPath: "{$.successPercent}",
+	})
+	p := newProvider(metric)
+	measurement := p.Run(newAnalysisRun(), metric, nil)
+	assert.Equal(t, "0.5", measurement.Value)
+	assert.Equal(t, v1alpha1.AnalysisStatusFailed, measurement.Status)
+}
+
+func TestRunPostWithSecretHeader(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer secret-token", r.Header.Get("Authorization"))
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"service": "guestbook"}`, string(body))
+		w.Write([]byte(`{"successPercent": 1}`))
+	}))
+	defer server.Close()
+
+	metric := newMetric(&v1alpha1.WebMetric{
+		Method: v1alpha1.WebMetricMethodPost,
+		URL:    server.URL,
+		Body:   `{"service": "{{input.service}}"}`,
+		Headers: []v1alpha1.WebMetricHeader{{
+			Key: "Authorization",
+			ValueFrom: &v1alpha1.WebMetricHeaderValueFrom{
+				SecretKeyRef: &v1alpha1.SecretKeyRef{Name: "web-metric", Key: "token"},
+			},
+		}},
+		JSONPath: "{$.successPercent}",
+	})
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-metric", Namespace: metav1.NamespaceDefault},
+		Data:       map[string][]byte{"token": []byte("Bearer secret-token")},
+	}
+	p := newProvider(metric, secret)
+	measurement := p.Run(newAnalysisRun(), metric, []v1alpha1.Argument{{Name: "service", Value: "guestbook"}})
+	assert.Equal(t, "1", measurement.Value)
+	assert.Equal(t, v1alpha1.AnalysisStatusSuccessful, measurement.Status)
+}
+
+func TestRunMissingSecretKey(t *testing.T) {
+	metric := newMetric(&v1alpha1.WebMetric{
+		URL: "http://localhost",
+		Headers: []v1alpha1.WebMetricHeader{{
+			Key: "Authorization",
+			ValueFrom: &v1alpha1.WebMetricHeaderValueFrom{
+				SecretKeyRef: &v1alpha1.SecretKeyRef{Name: "web-metric", Key: "missing"},
+			},
+		}},
+	})
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-metric", Namespace: metav1.NamespaceDefault},
+		Data:       map[string][]byte{"token": []byte("token")},
+	}
+	p := newProvider(metric, secret)
+	measurement := p.Run(newAnalysisRun(), metric, nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "key 'missing' does not exist in secret 'web-metric'", measurement.Message)
+}
+
+func TestRunNon2xxResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	metric := newMetric(&v1alpha1.WebMetric{URL: server.URL})
+	p := newProvider(metric)
+	measurement := p.Run(newAnalysisRun(), metric, nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "received non 2xx response code: 500", measurement.Message)
+}
+
+func TestRunInvalidJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`not json`))
+	}))
+	defer server.Close()
+
+	metric := newMetric(&v1alpha1.WebMetric{URL: server.URL})
+	p := newProvider(metric)
+	measurement := p.Run(newAnalysisRun(), metric, nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Contains(t, measurement.Message, "could not parse JSON body")
+}
+
+func TestRunMissingJSONPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"successPercent": 1}`))
+	}))
+	defer server.Close()
+
+	metric := newMetric(&v1alpha1.WebMetric{URL: server.URL, JSONPath: "{$.missing}"})
+	p := newProvider(metric)
+	measurement := p.Run(newAnalysisRun(), metric, nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Contains(t, measurement.Message, "could not find JSONPath in body")
+}
+
+func TestRunUnsupportedMethod(t *testing.T) {
+	metric := newMetric(&v1alpha1.WebMetric{Method: "PUT", URL: "http://localhost"})
+	p := newProvider(metric)
+	measurement := p.Run(newAnalysisRun(), metric, nil)
+	assert.Equal(t, v1alpha1.AnalysisStatusError, measurement.Status)
+	assert.Equal(t, "unsupported method 'PUT'", measurement.Message)
+}
+
+func TestNewWebMetricHTTPClient(t *testing.T) {
+	client := NewWebMetricHTTPClient(newMetric(&v1alpha1.WebMetric{}))
+	assert.Equal(t, float64(DefaultTimeoutSeconds), client.Timeout.Seconds())
+	assert.Nil(t, client.Transport)
+
+	client = NewWebMetricHTTPClient(newMetric(&v1alpha1.WebMetric{TimeoutSeconds: 30, Insecure: true}))
+	assert.Equal(t, float64(30), client.Timeout.Seconds())
+	transport := client.Transport.(*http.Transport)
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify)
+}
+
+func TestResumeAndTerminate(t *testing.T) {
+	p := NewWebMetricProvider(*log.NewEntry(log.New()), nil, nil)
+	measurement := v1alpha1.Measurement{Value: "1"}
+	assert.Equal(t, measurement, p.Resume(nil, v1alpha1.Metric{}, nil, measurement))
+	assert.Equal(t, measurement, p.Terminate(nil, v1alpha1.Metric{}, nil, measurement))
+}
@@ -0,0 +1,154 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap holding the notification templates, triggers and services
+	ConfigMapName = "argo-rollouts-notification-configmap"
+	// SecretName is the name of the Secret holding the credentials referenced by the notification services
+	SecretName = "argo-rollouts-notification-secret"
+
+	templateKeyPrefix = "template."
+	triggerKeyPrefix  = "trigger."
+	serviceKeyPrefix  = "service."
+
+	// SlackServiceName is the name of the Slack service in the ConfigMap and the subscription annotations
+	SlackServiceName = "slack"
+	// EmailServiceName is the name of the email service in the ConfigMap and the subscription annotations
+	EmailServiceName = "email"
+	// webhookServicePrefix prefixes the name of the webhook services in the ConfigMap and the subscription annotations
+	webhookServicePrefix = "webhook."
+)
+
+// Template describes the content of a notification. All the fields are Go templates rendered with the rollout,
+// the AnalysisRun which fired the trigger (if any) and the name of the trigger.
+type Template struct {
+	// Message is the text of the notification sent to Slack and the body of the email
+	Message string `json:"message,omitempty"`
+	// Email customizes the notification sent by email
+	Email *EmailTemplate `json:"email,omitempty"`
+	// Webhook holds the requests sent to the webhook services keyed by the name of the service
+	Webhook map[string]WebhookTemplate `json:"webhook,omitempty"`
+}
+
+// EmailTemplate customizes the notification sent by email
+type EmailTemplate struct {
+	// Subject is the subject of the email
+	Subject string `json:"subject,omitempty"`
+}
+
+// WebhookTemplate describes the request sent to a webhook service
+type WebhookTemplate struct {
+	// Method is the HTTP method of the request. Defaults to POST.
+	Method string `json:"method,omitempty"`
+	// Path is appended to the URL of the webhook service
+	Path string `json:"path,omitempty"`
+	// Body is the body of the request
+	Body string `json:"body,omitempty"`
+}
+
+// Trigger lists the templates sent when the trigger fires
+type Trigger struct {
+	// Send is the list of the names of the templates sent
+	Send []string `json:"send"`
+}
+
+// Config is the notification configuration parsed from the notification ConfigMap and Secret
+type Config struct {
+	Templates map[string]Template
+	Triggers  map[string]Trigger
+	Services  map[string]Service
+}
+
+// ParseConfig parses the templates, triggers and services of the notification ConfigMap. The values of the service
+// settings starting with '$' are replaced with the value of the matching key of the Secret.
+func ParseConfig(cm *corev1.ConfigMap, secret *corev1.Secret) (*Config, error) {
+	cfg := &Config{
+		Templates: map[string]Template{},
+		Triggers:  map[string]Trigger{},
+		Services:  map[string]Service{},
+	}
+	for key, value := range cm.Data {
+		switch {
+		case strings.HasPrefix(key, templateKeyPrefix):
+			var template Template
+			if err := yaml.Unmarshal([]byte(value), &template); err != nil {
+				return nil, fmt.Errorf("failed to parse '%s': %v", key, err)
+			}
+			cfg.Templates[strings.TrimPrefix(key, templateKeyPrefix)] = template
+		case strings.HasPrefix(key, triggerKeyPrefix):
+			var trigger Trigger
+			if err := yaml.Unmarshal([]byte(value), &trigger); err != nil {
+				return nil, fmt.Errorf("failed to parse '%s': %v", key, err)
+			}
+			cfg.Triggers[strings.TrimPrefix(key, triggerKeyPrefix)] = trigger
+		case strings.HasPrefix(key, serviceKeyPrefix):
+			name := strings.TrimPrefix(key, serviceKeyPrefix)
+			service, err := parseService(name, value, secret)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse '%s': %v", key, err)
+			}
+			cfg.Services[name] = service
+		}
+	}
+	return cfg, nil
+}
+
+// parseService parses the settings of the service with the given name
+func parseService(name, value string, secret *corev1.Secret) (Service, error) {
+	switch {
+	case name == SlackServiceName:
+		var opts SlackOptions
+		if err := yaml.Unmarshal([]byte(value), &opts); err != nil {
+			return nil, err
+		}
+		opts.WebhookURL = resolveSecret(opts.WebhookURL, secret)
+		if opts.WebhookURL == "" {
+			return nil, fmt.Errorf("webhookURL is required")
+		}
+		return NewSlackService(opts), nil
+	case name == EmailServiceName:
+		var opts EmailOptions
+		if err := yaml.Unmarshal([]byte(value), &opts); err != nil {
+			return nil, err
+		}
+		opts.Username = resolveSecret(opts.Username, secret)
+		opts.Password = resolveSecret(opts.Password, secret)
+		if opts.Host == "" || opts.Port == 0 || opts.From == "" {
+			return nil, fmt.Errorf("host, port and from are required")
+		}
+		return NewEmailService(opts), nil
+	case strings.HasPrefix(name, webhookServicePrefix):
+		var opts WebhookOptions
+		if err := yaml.Unmarshal([]byte(value), &opts); err != nil {
+			return nil, err
+		}
+		opts.URL = resolveSecret(opts.URL, secret)
+		for i := range opts.Headers {
+			opts.Headers[i].Value = resolveSecret(opts.Headers[i].Value, secret)
+		}
+		if opts.URL == "" {
+			return nil, fmt.Errorf("url is required")
+		}
+		return NewWebhookService(strings.TrimPrefix(name, webhookServicePrefix), opts), nil
+	}
+	return nil, fmt.Errorf("unknown service '%s'", name)
+}
+
+// resolveSecret returns the value of the Secret's key referenced by a value starting with '$'. It returns an empty
+// string if the Secret or the key does not exist.
+func resolveSecret(value string, secret *corev1.Secret) string {
+	if !strings.HasPrefix(value, "$") {
+		return value
+	}
+	if secret == nil {
+		return ""
+	}
+	return string(secret.Data[strings.TrimPrefix(value, "$")])
+}
@@ -0,0 +1,117 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newConfigMap(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: data,
+	}
+}
+
+func newSecret(data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SecretName,
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string][]byte{},
+	}
+	for k, v := range data {
+		secret.Data[k] = []byte(v)
+	}
+	return secret
+}
+
+func TestParseConfig(t *testing.T) {
+	cm := newConfigMap(map[string]string{
+		"template.rollout-completed": `
+message: Rollout {{.Rollout.Name}} completed
+email:
+  subject: Rollout completed
+webhook:
+  github:
+    method: PUT
+    path: /status
+    body: '{"state": "success"}'
+`,
+		"trigger.on-rollout-completed": `
+send: [rollout-completed]
+`,
+		"service.slack": `
+webhookURL: $slack-url
+`,
+		"service.webhook.github": `
+url: https://api.github.com
+headers:
+- name: Authorization
+  value: $github-token
+`,
+		"service.email": `
+host: smtp.example.com
+port: 587
+from: rollouts@example.com
+username: $email-username
+password: $email-password
+`,
+		"unrelated": "value",
+	})
+	secret := newSecret(map[string]string{
+		"slack-url":      "https://hooks.slack.com/services/xxx",
+		"github-token":   "token",
+		"email-username": "user",
+		"email-password": "password",
+	})
+
+	cfg, err := ParseConfig(cm, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, Template{
+		Message: "Rollout {{.Rollout.Name}} completed",
+		Email:   &EmailTemplate{Subject: "Rollout completed"},
+		Webhook: map[string]WebhookTemplate{
+			"github": {Method: "PUT", Path: "/status", Body: `{"state": "success"}`},
+		},
+	}, cfg.Templates["rollout-completed"])
+	assert.Equal(t, Trigger{Send: []string{"rollout-completed"}}, cfg.Triggers[TriggerRolloutCompleted])
+	assert.Len(t, cfg.Services, 3)
+	assert.Equal(t, "https://hooks.slack.com/services/xxx", cfg.Services[SlackServiceName].(*slackService).opts.WebhookURL)
+	webhook := cfg.Services["webhook.github"].(*webhookService)
+	assert.Equal(t, "github", webhook.name)
+	assert.Equal(t, []WebhookHeader{{Name: "Authorization", Value: "token"}}, webhook.opts.Headers)
+	assert.Equal(t, EmailOptions{
+		Host:     "smtp.example.com",
+		Port:     587,
+		From:     "rollouts@example.com",
+		Username: "user",
+		Password: "password",
+	}, cfg.Services[EmailServiceName].(*emailService).opts)
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]string
+	}{
+		{name: "InvalidTemplate", data: map[string]string{"template.foo": "message: [invalid"}},
+		{name: "InvalidTrigger", data: map[string]string{"trigger.on-rollout-completed": "send: foo"}},
+		{name: "UnknownService", data: map[string]string{"service.foo": "url: http://foo"}},
+		{name: "SlackMissingURL", data: map[string]string{"service.slack": "webhookURL: $missing"}},
+		{name: "WebhookMissingURL", data: map[string]string{"service.webhook.foo": "headers: []"}},
+		{name: "EmailMissingHost", data: map[string]string{"service.email": "port: 25\nfrom: foo@example.com"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseConfig(newConfigMap(test.data), nil)
+			assert.Error(t, err)
+		})
+	}
+}
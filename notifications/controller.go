@@ -0,0 +1,267 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	patchtypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	v1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/argoproj/argo-rollouts/controller/metrics"
+	register "github.com/argoproj/argo-rollouts/pkg/apis/rollouts"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
+	informers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions/rollouts/v1alpha1"
+	listers "github.com/argoproj/argo-rollouts/pkg/client/listers/rollouts/v1alpha1"
+	controllerutil "github.com/argoproj/argo-rollouts/utils/controller"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+)
+
+const notifiedPatch = `{"metadata":{"annotations":{"%s":%s}}}`
+
+// NotificationController sends the notifications of the rollouts' lifecycle events to the services the rollouts
+// subscribe to
+type NotificationController struct {
+	argoprojclientset clientset.Interface
+	rolloutsLister    listers.RolloutLister
+	rolloutSynced     cache.InformerSynced
+	analysisRunLister listers.AnalysisRunLister
+	analysisRunSynced cache.InformerSynced
+	configMapLister   v1.ConfigMapLister
+	configMapSynced   cache.InformerSynced
+	secretLister      v1.SecretLister
+	secretSynced      cache.InformerSynced
+	namespace         string
+	workqueue         workqueue.RateLimitingInterface
+	recorder          record.EventRecorder
+
+	metricServer *metrics.MetricsServer
+	// now returns the time the notifications are recorded with. It is a field so the tests can replace it.
+	now func() time.Time
+}
+
+// NewNotificationController returns a new notification controller. The notification ConfigMap and Secret are read
+// from the given namespace.
+func NewNotificationController(
+	argoprojclientset clientset.Interface,
+	rolloutsInformer informers.RolloutInformer,
+	analysisRunInformer informers.AnalysisRunInformer,
+	configMapInformer coreinformers.ConfigMapInformer,
+	secretInformer coreinformers.SecretInformer,
+	namespace string,
+	notificationWorkQueue workqueue.RateLimitingInterface,
+	metricServer *metrics.MetricsServer,
+	recorder record.EventRecorder) *NotificationController {
+
+	controller := &NotificationController{
+		argoprojclientset: argoprojclientset,
+		rolloutsLister:    rolloutsInformer.Lister(),
+		rolloutSynced:     rolloutsInformer.Informer().HasSynced,
+		analysisRunLister: analysisRunInformer.Lister(),
+		analysisRunSynced: analysisRunInformer.Informer().HasSynced,
+		configMapLister:   configMapInformer.Lister(),
+		configMapSynced:   configMapInformer.Informer().HasSynced,
+		secretLister:      secretInformer.Lister(),
+		secretSynced:      secretInformer.Informer().HasSynced,
+		namespace:         namespace,
+		workqueue:         notificationWorkQueue,
+		recorder:          recorder,
+		metricServer:      metricServer,
+		now:               time.Now,
+	}
+
+	enqueue := func(obj interface{}) {
+		controllerutil.Enqueue(obj, notificationWorkQueue)
+	}
+	rolloutsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			enqueue(new)
+		},
+	})
+	analysisRunInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			controllerutil.EnqueueParentObject(obj, register.RolloutKind, enqueue)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			controllerutil.EnqueueParentObject(new, register.RolloutKind, enqueue)
+		},
+	})
+	return controller
+}
+
+// Run starts the notification workers. It will block until stopCh is closed.
+func (c *NotificationController) Run(threadiness int, stopCh <-chan struct{}) error {
+	log.Info("Starting Notification workers")
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(func() {
+			controllerutil.RunWorker(c.workqueue, logutil.RolloutKey, c.syncHandler, c.metricServer)
+		}, time.Second, stopCh)
+	}
+
+	log.Info("Started Notification workers")
+	<-stopCh
+	log.Info("Shutting down workers")
+
+	return nil
+}
+
+// syncHandler sends the notifications of the rollout's events which were not sent yet and records them in the
+// rollout's notified annotation. An error is returned when a notification fails to be sent so the rollout is
+// requeued and the notification retried.
+func (c *NotificationController) syncHandler(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	r, err := c.rolloutsLister.Rollouts(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	subscriptions := getSubscriptions(r)
+	if len(subscriptions) == 0 && r.Annotations[NotifiedAnnotation] == "" {
+		return nil
+	}
+	cfg, err := c.getConfig()
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		return nil
+	}
+	logCtx := logutil.WithRollout(r)
+
+	runs, err := c.analysisRunLister.AnalysisRuns(namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	events := getEvents(r, runs)
+	notified := getNotified(r)
+	newNotified := pruneNotified(notified, r, events)
+
+	var sendErr error
+	for _, e := range events {
+		trigger, ok := cfg.Triggers[e.trigger]
+		if !ok {
+			continue
+		}
+		for _, sub := range subscriptions[e.trigger] {
+			service, ok := cfg.Services[sub.service]
+			if !ok {
+				logCtx.Warnf("Notification service '%s' is not configured", sub.service)
+				continue
+			}
+			for _, templateName := range trigger.Send {
+				tmpl, ok := cfg.Templates[templateName]
+				if !ok {
+					logCtx.Warnf("Notification template '%s' is not configured", templateName)
+					continue
+				}
+				for _, recipient := range sub.recipients {
+					notifiedKey := strings.Join([]string{e.key, templateName, sub.service, recipient}, "|")
+					if _, ok := newNotified[notifiedKey]; ok {
+						continue
+					}
+					notification, err := render(tmpl, r, e)
+					if err == nil {
+						err = service.Send(notification, recipient)
+					}
+					if err != nil {
+						msg := fmt.Sprintf("Failed to send notification '%s' of trigger '%s' with service '%s': %v", templateName, e.trigger, sub.service, err)
+						logCtx.Warn(msg)
+						c.recorder.Event(r, corev1.EventTypeWarning, "NotificationFailed", msg)
+						sendErr = err
+						continue
+					}
+					logCtx.Infof("Sent notification '%s' of trigger '%s' with service '%s'", templateName, e.trigger, sub.service)
+					newNotified[notifiedKey] = c.now().Unix()
+				}
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(notified, newNotified) {
+		if err := c.patchNotified(r, newNotified); err != nil {
+			return err
+		}
+	}
+	return sendErr
+}
+
+// getConfig parses the notification ConfigMap and Secret. It returns nil if the ConfigMap does not exist.
+func (c *NotificationController) getConfig() (*Config, error) {
+	cm, err := c.configMapLister.ConfigMaps(c.namespace).Get(ConfigMapName)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	secret, err := c.secretLister.Secrets(c.namespace).Get(SecretName)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	return ParseConfig(cm, secret)
+}
+
+// getNotified returns the notifications recorded in the rollout's notified annotation with the unix time they were sent
+func getNotified(r *v1alpha1.Rollout) map[string]int64 {
+	notified := map[string]int64{}
+	value, ok := r.Annotations[NotifiedAnnotation]
+	if !ok {
+		return notified
+	}
+	if err := json.Unmarshal([]byte(value), &notified); err != nil {
+		logutil.WithRollout(r).Warnf("Ignoring invalid '%s' annotation: %v", NotifiedAnnotation, err)
+		return map[string]int64{}
+	}
+	return notified
+}
+
+// pruneNotified returns the recorded notifications of the current pod template hash and of the AnalysisRuns still
+// failing. The notifications of a pod template are not sent again until the template changes.
+func pruneNotified(notified map[string]int64, r *v1alpha1.Rollout, events []event) map[string]int64 {
+	active := map[string]bool{}
+	for _, e := range events {
+		active[e.key] = true
+	}
+	pruned := map[string]int64{}
+	for key, sentAt := range notified {
+		eventKey := strings.SplitN(key, "|", 2)[0]
+		if active[eventKey] || (!strings.HasPrefix(eventKey, analysisRunEventKeyPrefix) && strings.HasPrefix(eventKey, r.Status.CurrentPodHash+"/")) {
+			pruned[key] = sentAt
+		}
+	}
+	return pruned
+}
+
+// patchNotified records the sent notifications in the rollout's notified annotation
+func (c *NotificationController) patchNotified(r *v1alpha1.Rollout, notified map[string]int64) error {
+	value, err := json.Marshal(notified)
+	if err != nil {
+		return err
+	}
+	// The annotation value is a JSON string containing the JSON map of the notifications
+	quoted, err := json.Marshal(string(value))
+	if err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(notifiedPatch, NotifiedAnnotation, string(quoted))
+	_, err = c.argoprojclientset.ArgoprojV1alpha1().Rollouts(r.Namespace).Patch(r.Name, patchtypes.MergePatchType, []byte(patch))
+	return err
+}
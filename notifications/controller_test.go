@@ -0,0 +1,187 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/argoproj/argo-rollouts/controller/metrics"
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
+	informers "github.com/argoproj/argo-rollouts/pkg/client/informers/externalversions"
+)
+
+// slackReceiver records the messages posted to a Slack-compatible webhook
+type slackReceiver struct {
+	server   *httptest.Server
+	messages []string
+	status   int
+}
+
+func newSlackReceiver() *slackReceiver {
+	receiver := &slackReceiver{status: http.StatusOK}
+	receiver.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if receiver.status != http.StatusOK {
+			w.WriteHeader(receiver.status)
+			return
+		}
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		receiver.messages = append(receiver.messages, payload["channel"]+": "+payload["text"])
+	}))
+	return receiver
+}
+
+func newNotificationConfigMap() *corev1.ConfigMap {
+	return newConfigMap(map[string]string{
+		"template.rollout-completed":   "message: Rollout {{.Rollout.Name}} completed",
+		"template.rollout-aborted":     "message: Rollout {{.Rollout.Name}} aborted",
+		"trigger.on-rollout-completed": "send: [rollout-completed]",
+		"trigger.on-rollout-aborted":   "send: [rollout-aborted]",
+		"service.slack":                "webhookURL: $slack-url",
+	})
+}
+
+func newFakeNotificationController(r *v1alpha1.Rollout, cm *corev1.ConfigMap, secret *corev1.Secret, runs ...*v1alpha1.AnalysisRun) (*NotificationController, *fake.Clientset) {
+	client := fake.NewSimpleClientset(r)
+	kubeclient := k8sfake.NewSimpleClientset()
+	i := informers.NewSharedInformerFactory(client, 0)
+	k8sI := kubeinformers.NewSharedInformerFactory(kubeclient, 0)
+
+	c := NewNotificationController(client,
+		i.Argoproj().V1alpha1().Rollouts(),
+		i.Argoproj().V1alpha1().AnalysisRuns(),
+		k8sI.Core().V1().ConfigMaps(),
+		k8sI.Core().V1().Secrets(),
+		cm.Namespace,
+		workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Notifications"),
+		metrics.NewMetricsServer("localhost:8080", i.Argoproj().V1alpha1().Rollouts().Lister()),
+		record.NewFakeRecorder(10))
+	c.now = func() time.Time {
+		return time.Unix(1577836800, 0)
+	}
+
+	i.Argoproj().V1alpha1().Rollouts().Informer().GetIndexer().Add(r)
+	for _, run := range runs {
+		i.Argoproj().V1alpha1().AnalysisRuns().Informer().GetIndexer().Add(run)
+	}
+	k8sI.Core().V1().ConfigMaps().Informer().GetIndexer().Add(cm)
+	if secret != nil {
+		k8sI.Core().V1().Secrets().Informer().GetIndexer().Add(secret)
+	}
+	return c, client
+}
+
+// getPatchedNotified returns the notified annotation set by the patch of the rollout
+func getPatchedNotified(t *testing.T, client *fake.Clientset) map[string]int64 {
+	actions := client.Actions()
+	assert.Len(t, actions, 1)
+	patchAction, ok := actions[0].(k8stesting.PatchAction)
+	assert.True(t, ok)
+	patched := v1alpha1.Rollout{}
+	assert.NoError(t, json.Unmarshal(patchAction.GetPatch(), &patched))
+	return getNotified(&patched)
+}
+
+func TestSyncNotificationsSendsOnce(t *testing.T) {
+	receiver := newSlackReceiver()
+	defer receiver.server.Close()
+	r := newRollout()
+	completeRollout(r)
+	r.Annotations[SubscribeAnnotationPrefix+"on-rollout-completed.slack"] = "#rollouts;#team"
+	secret := newSecret(map[string]string{"slack-url": receiver.server.URL})
+	c, client := newFakeNotificationController(r, newNotificationConfigMap(), secret)
+
+	assert.NoError(t, c.syncHandler("default/foo"))
+	assert.Equal(t, []string{"#rollouts: Rollout foo completed", "#team: Rollout foo completed"}, receiver.messages)
+	notified := getPatchedNotified(t, client)
+	assert.Equal(t, map[string]int64{
+		"abcdef/completed|rollout-completed|slack|#rollouts": 1577836800,
+		"abcdef/completed|rollout-completed|slack|#team":     1577836800,
+	}, notified)
+
+	// The notifications recorded in the annotation are not sent again, e.g. after a restart of the controller
+	value, _ := json.Marshal(notified)
+	r.Annotations[NotifiedAnnotation] = string(value)
+	c, client = newFakeNotificationController(r, newNotificationConfigMap(), secret)
+	assert.NoError(t, c.syncHandler("default/foo"))
+	assert.Len(t, receiver.messages, 2)
+	assert.Len(t, client.Actions(), 0)
+}
+
+func TestSyncNotificationsRetriesFailures(t *testing.T) {
+	receiver := newSlackReceiver()
+	defer receiver.server.Close()
+	receiver.status = http.StatusInternalServerError
+	r := newRollout()
+	r.Status.Abort = true
+	r.Annotations[SubscribeAnnotationPrefix+"on-rollout-aborted.slack"] = "#rollouts"
+	secret := newSecret(map[string]string{"slack-url": receiver.server.URL})
+	c, client := newFakeNotificationController(r, newNotificationConfigMap(), secret)
+
+	assert.Error(t, c.syncHandler("default/foo"))
+	assert.Len(t, client.Actions(), 0)
+
+	receiver.status = http.StatusOK
+	assert.NoError(t, c.syncHandler("default/foo"))
+	assert.Equal(t, []string{"#rollouts: Rollout foo aborted"}, receiver.messages)
+	assert.Equal(t, map[string]int64{"abcdef/aborted|rollout-aborted|slack|#rollouts": 1577836800}, getPatchedNotified(t, client))
+}
+
+func TestSyncNotificationsPrunesNotified(t *testing.T) {
+	r := newRollout()
+	r.Status.CurrentPodHash = "new"
+	r.Annotations[NotifiedAnnotation] = `{
+		"abcdef/completed|rollout-completed|slack|#rollouts": 1,
+		"new/aborted|rollout-aborted|slack|#rollouts": 2,
+		"analysisrun/foo-abcdef-1|analysis-failed|slack|#rollouts": 3,
+		"analysisrun/foo-abcdef-2|analysis-failed|slack|#rollouts": 4
+	}`
+	failedRun := newAnalysisRun(r, "foo-abcdef-1", v1alpha1.AnalysisStatusFailed)
+	secret := newSecret(map[string]string{"slack-url": "http://localhost"})
+	c, client := newFakeNotificationController(r, newNotificationConfigMap(), secret, failedRun)
+
+	assert.NoError(t, c.syncHandler("default/foo"))
+	assert.Equal(t, map[string]int64{
+		"new/aborted|rollout-aborted|slack|#rollouts":              2,
+		"analysisrun/foo-abcdef-1|analysis-failed|slack|#rollouts": 3,
+	}, getPatchedNotified(t, client))
+}
+
+func TestSyncNotificationsWithoutSubscription(t *testing.T) {
+	r := newRollout()
+	completeRollout(r)
+	c, client := newFakeNotificationController(r, newNotificationConfigMap(), nil)
+
+	assert.NoError(t, c.syncHandler("default/foo"))
+	assert.Len(t, client.Actions(), 0)
+}
+
+func TestSyncNotificationsWithoutConfigMap(t *testing.T) {
+	r := newRollout()
+	completeRollout(r)
+	r.Annotations[SubscribeAnnotationPrefix+"on-rollout-completed.slack"] = "#rollouts"
+	cm := newNotificationConfigMap()
+	cm.Name = "other"
+	c, client := newFakeNotificationController(r, cm, nil)
+
+	assert.NoError(t, c.syncHandler("default/foo"))
+	assert.Len(t, client.Actions(), 0)
+}
+
+func TestSyncNotificationsMissingRollout(t *testing.T) {
+	c, client := newFakeNotificationController(newRollout(), newNotificationConfigMap(), nil)
+
+	assert.NoError(t, c.syncHandler("default/missing"))
+	assert.Len(t, client.Actions(), 0)
+}
@@ -0,0 +1,189 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultHTTPTimeout = 30 * time.Second
+
+// sendMail sends an email with SMTP. It is a variable so the tests can replace it.
+var sendMail = smtp.SendMail
+
+// Notification is a rendered notification template
+type Notification struct {
+	// Message is the text of the notification
+	Message string
+	// Subject is the subject of the email
+	Subject string
+	// Webhook holds the requests sent to the webhook services keyed by the name of the service
+	Webhook map[string]WebhookTemplate
+}
+
+// Service sends notifications to a recipient
+type Service interface {
+	Send(notification Notification, recipient string) error
+}
+
+// SlackOptions configures the Slack service
+type SlackOptions struct {
+	// WebhookURL is the URL of the Slack incoming webhook
+	WebhookURL string `json:"webhookURL"`
+}
+
+type slackService struct {
+	opts   SlackOptions
+	client *http.Client
+}
+
+// NewSlackService returns a service posting the notifications to a Slack-compatible incoming webhook. The recipients
+// are the channels the message is posted to. An empty recipient posts to the default channel of the webhook.
+func NewSlackService(opts SlackOptions) Service {
+	return &slackService{opts: opts, client: &http.Client{Timeout: defaultHTTPTimeout}}
+}
+
+func (s *slackService) Send(notification Notification, recipient string) error {
+	payload := map[string]string{"text": notification.Message}
+	if recipient != "" {
+		payload["channel"] = recipient
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := newRequest(http.MethodPost, s.opts.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return doRequest(s.client, req)
+}
+
+// WebhookHeader is a header of the requests sent to a webhook service
+type WebhookHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// WebhookOptions configures a webhook service
+type WebhookOptions struct {
+	// URL is the base URL of the requests
+	URL string `json:"url"`
+	// Headers are added to the requests
+	Headers []WebhookHeader `json:"headers,omitempty"`
+}
+
+type webhookService struct {
+	name   string
+	opts   WebhookOptions
+	client *http.Client
+}
+
+// NewWebhookService returns a service sending the requests of the notification templates to a generic HTTP
+// endpoint. The recipients are ignored.
+func NewWebhookService(name string, opts WebhookOptions) Service {
+	return &webhookService{name: name, opts: opts, client: &http.Client{Timeout: defaultHTTPTimeout}}
+}
+
+func (s *webhookService) Send(notification Notification, recipient string) error {
+	webhook, ok := notification.Webhook[s.name]
+	if !ok {
+		return nil
+	}
+	method := webhook.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := newRequest(method, strings.TrimSuffix(s.opts.URL, "/")+webhook.Path, strings.NewReader(webhook.Body))
+	if err != nil {
+		return err
+	}
+	for _, header := range s.opts.Headers {
+		req.Header.Set(header.Name, header.Value)
+	}
+	return doRequest(s.client, req)
+}
+
+// newRequest returns a new request. The URL of the services may hold credentials, such as the token of a Slack
+// webhook, so it is left out of the returned error.
+func newRequest(method, rawURL string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, rawURL, body)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("invalid request URL: %v", err)
+	}
+	return req, nil
+}
+
+// doRequest sends the request and returns an error if the response does not have a 2xx status code. Like the error of
+// a response, the error of a failed request only reports the host of the URL.
+func doRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("request to %s failed: %v", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("request to %s returned status %d: %s", req.URL.Host, resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// EmailOptions configures the email service
+type EmailOptions struct {
+	// Host is the host of the SMTP server
+	Host string `json:"host"`
+	// Port is the port of the SMTP server
+	Port int `json:"port"`
+	// From is the address the emails are sent from
+	From string `json:"from"`
+	// Username is used to authenticate to the SMTP server. Authentication is disabled if empty.
+	Username string `json:"username,omitempty"`
+	// Password is used to authenticate to the SMTP server
+	Password string `json:"password,omitempty"`
+}
+
+type emailService struct {
+	opts EmailOptions
+}
+
+// NewEmailService returns a service sending the notifications by email through an SMTP server. The recipients are
+// email addresses.
+func NewEmailService(opts EmailOptions) Service {
+	return &emailService{opts: opts}
+}
+
+func (s *emailService) Send(notification Notification, recipient string) error {
+	if recipient == "" {
+		return fmt.Errorf("email recipient is required")
+	}
+	// The recipient and the subject are written to the headers of the email
+	if strings.ContainsAny(recipient, "\r\n") {
+		return fmt.Errorf("email recipient must not contain line breaks")
+	}
+	if strings.ContainsAny(notification.Subject, "\r\n") {
+		return fmt.Errorf("email subject must not contain line breaks")
+	}
+	var auth smtp.Auth
+	if s.opts.Username != "" {
+		auth = smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host)
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		s.opts.From, recipient, notification.Subject, notification.Message)
+	addr := fmt.Sprintf("%s:%d", s.opts.Host, s.opts.Port)
+	return sendMail(addr, auth, s.opts.From, []string{recipient}, []byte(msg))
+}
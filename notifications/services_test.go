@@ -0,0 +1,152 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlackService(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		// Decoding into the map of the previous request would keep its keys
+		payload = map[string]string{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+	}))
+	defer server.Close()
+
+	service := NewSlackService(SlackOptions{WebhookURL: server.URL})
+	assert.NoError(t, service.Send(Notification{Message: "hello"}, "#rollouts"))
+	assert.Equal(t, map[string]string{"text": "hello", "channel": "#rollouts"}, payload)
+
+	assert.NoError(t, service.Send(Notification{Message: "hello"}, ""))
+	assert.Equal(t, map[string]string{"text": "hello"}, payload)
+}
+
+func TestSlackServiceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("channel_not_found"))
+	}))
+	defer server.Close()
+
+	service := NewSlackService(SlackOptions{WebhookURL: server.URL})
+	err := service.Send(Notification{Message: "hello"}, "#missing")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "channel_not_found")
+}
+
+func TestSlackServiceRequestErrorHidesURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	webhookURL := server.URL + "/services/T000/B000/secret-token"
+	host := strings.TrimPrefix(server.URL, "http://")
+	server.Close()
+
+	service := NewSlackService(SlackOptions{WebhookURL: webhookURL})
+	err := service.Send(Notification{Message: "hello"}, "#rollouts")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "request to "+host+" failed")
+	assert.NotContains(t, err.Error(), "secret-token")
+
+	service = NewSlackService(SlackOptions{WebhookURL: "http://example.com/secret-token\n"})
+	err = service.Send(Notification{Message: "hello"}, "#rollouts")
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret-token")
+}
+
+func TestWebhookService(t *testing.T) {
+	var method, path, auth, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+	}))
+	defer server.Close()
+
+	service := NewWebhookService("github", WebhookOptions{
+		URL:     server.URL + "/",
+		Headers: []WebhookHeader{{Name: "Authorization", Value: "token"}},
+	})
+	notification := Notification{
+		Webhook: map[string]WebhookTemplate{
+			"github": {Method: http.MethodPut, Path: "/status", Body: `{"state":"success"}`},
+		},
+	}
+	assert.NoError(t, service.Send(notification, ""))
+	assert.Equal(t, http.MethodPut, method)
+	assert.Equal(t, "/status", path)
+	assert.Equal(t, "token", auth)
+	assert.Equal(t, `{"state":"success"}`, body)
+
+	// The method defaults to POST
+	notification.Webhook["github"] = WebhookTemplate{Body: "{}"}
+	assert.NoError(t, service.Send(notification, ""))
+	assert.Equal(t, http.MethodPost, method)
+}
+
+func TestWebhookServiceWithoutTemplate(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	service := NewWebhookService("github", WebhookOptions{URL: server.URL})
+	assert.NoError(t, service.Send(Notification{Message: "hello"}, ""))
+	assert.False(t, called)
+}
+
+func TestEmailService(t *testing.T) {
+	defer func() { sendMail = smtp.SendMail }()
+	var addr, from string
+	var to []string
+	var msg string
+	var auth smtp.Auth
+	sendMail = func(a string, au smtp.Auth, f string, t []string, m []byte) error {
+		addr, auth, from, to, msg = a, au, f, t, string(m)
+		return nil
+	}
+
+	service := NewEmailService(EmailOptions{
+		Host:     "smtp.example.com",
+		Port:     587,
+		From:     "rollouts@example.com",
+		Username: "user",
+		Password: "password",
+	})
+	err := service.Send(Notification{Message: "Rollout completed", Subject: "foo completed"}, "dev@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.NotNil(t, auth)
+	assert.Equal(t, "rollouts@example.com", from)
+	assert.Equal(t, []string{"dev@example.com"}, to)
+	assert.True(t, strings.Contains(msg, "Subject: foo completed\r\n"))
+	assert.True(t, strings.HasSuffix(msg, "\r\n\r\nRollout completed\r\n"))
+
+	assert.Error(t, service.Send(Notification{Message: "Rollout completed"}, ""))
+
+	// line breaks would inject headers into the email
+	msg = ""
+	err = service.Send(Notification{Message: "Rollout completed"}, "dev@example.com\r\nBcc: attacker@example.com")
+	assert.EqualError(t, err, "email recipient must not contain line breaks")
+	err = service.Send(Notification{Message: "Rollout completed", Subject: "foo\nBcc: attacker@example.com"}, "dev@example.com")
+	assert.EqualError(t, err, "email subject must not contain line breaks")
+	assert.Empty(t, msg)
+
+	sendMail = func(string, smtp.Auth, string, []string, []byte) error {
+		return errors.New("connection refused")
+	}
+	assert.EqualError(t, service.Send(Notification{Message: "Rollout completed"}, "dev@example.com"), "connection refused")
+}
@@ -0,0 +1,147 @@
+package notifications
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+)
+
+const (
+	// TriggerRolloutCompleted fires once the rollout finished updating to a new pod template
+	TriggerRolloutCompleted = "on-rollout-completed"
+	// TriggerRolloutAborted fires when the rollout is aborted
+	TriggerRolloutAborted = "on-rollout-aborted"
+	// TriggerRolloutPaused fires when the rollout is paused
+	TriggerRolloutPaused = "on-rollout-paused"
+	// TriggerRolloutStepCompleted fires when the canary rollout completes a step
+	TriggerRolloutStepCompleted = "on-rollout-step-completed"
+	// TriggerAnalysisRunFailed fires when an AnalysisRun owned by the rollout fails or errors
+	TriggerAnalysisRunFailed = "on-analysis-run-failed"
+
+	// SubscribeAnnotationPrefix prefixes the rollout annotations subscribing to a trigger. The annotation key is
+	// followed by '<trigger>.<service>' and its value is the list of recipients separated by ';'.
+	SubscribeAnnotationPrefix = "notifications.argoproj.io/subscribe."
+	// NotifiedAnnotation records the notifications sent for the rollout so they are not sent again
+	NotifiedAnnotation = "notified.notifications.argoproj.io"
+
+	analysisRunEventKeyPrefix = "analysisrun/"
+)
+
+// event is an occurrence of a trigger. The key identifies the occurrence so its notifications are sent once.
+type event struct {
+	trigger     string
+	key         string
+	analysisRun *v1alpha1.AnalysisRun
+}
+
+// getEvents returns the occurrences of the triggers currently firing for the rollout and its AnalysisRuns. The keys
+// of the rollout's events start with the current pod template hash.
+func getEvents(r *v1alpha1.Rollout, runs []*v1alpha1.AnalysisRun) []event {
+	podHash := r.Status.CurrentPodHash
+	events := []event{}
+	if podHash != "" && conditions.RolloutComplete(r, &r.Status) {
+		events = append(events, event{trigger: TriggerRolloutCompleted, key: podHash + "/completed"})
+	}
+	if r.Status.Abort {
+		events = append(events, event{trigger: TriggerRolloutAborted, key: podHash + "/aborted"})
+	}
+	if len(r.Status.PauseConditions) > 0 {
+		startTime := r.Status.PauseConditions[0].StartTime
+		events = append(events, event{trigger: TriggerRolloutPaused, key: fmt.Sprintf("%s/paused/%d", podHash, startTime.Unix())})
+	}
+	if r.Spec.Strategy.CanaryStrategy != nil && r.Status.CurrentStepIndex != nil && *r.Status.CurrentStepIndex > 0 {
+		events = append(events, event{trigger: TriggerRolloutStepCompleted, key: fmt.Sprintf("%s/step/%d", podHash, *r.Status.CurrentStepIndex)})
+	}
+	for _, run := range runs {
+		if !metav1.IsControlledBy(run, r) || run.Status == nil {
+			continue
+		}
+		if run.Status.Status == v1alpha1.AnalysisStatusFailed || run.Status.Status == v1alpha1.AnalysisStatusError {
+			events = append(events, event{trigger: TriggerAnalysisRunFailed, key: analysisRunEventKeyPrefix + run.Name, analysisRun: run})
+		}
+	}
+	return events
+}
+
+// subscription is a subscription of recipients of a service to a trigger
+type subscription struct {
+	service    string
+	recipients []string
+}
+
+// getSubscriptions returns the subscriptions of the rollout's annotations keyed by trigger
+func getSubscriptions(r *v1alpha1.Rollout) map[string][]subscription {
+	subscriptions := map[string][]subscription{}
+	for key, value := range r.Annotations {
+		if !strings.HasPrefix(key, SubscribeAnnotationPrefix) {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(key, SubscribeAnnotationPrefix), ".", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		recipients := []string{}
+		for _, recipient := range strings.Split(value, ";") {
+			if recipient = strings.TrimSpace(recipient); recipient != "" {
+				recipients = append(recipients, recipient)
+			}
+		}
+		if len(recipients) == 0 {
+			// A service without recipients (e.g. a webhook or the default channel of Slack) is notified once
+			recipients = append(recipients, "")
+		}
+		subscriptions[parts[0]] = append(subscriptions[parts[0]], subscription{service: parts[1], recipients: recipients})
+	}
+	return subscriptions
+}
+
+// templateData is the data the notification templates are rendered with
+type templateData struct {
+	Rollout     *v1alpha1.Rollout
+	AnalysisRun *v1alpha1.AnalysisRun
+	Trigger     string
+}
+
+// render renders the notification template for the event of the rollout
+func render(tmpl Template, r *v1alpha1.Rollout, e event) (Notification, error) {
+	data := templateData{Rollout: r, AnalysisRun: e.analysisRun, Trigger: e.trigger}
+	notification := Notification{Webhook: map[string]WebhookTemplate{}}
+	var err error
+	if notification.Message, err = renderText(tmpl.Message, data); err != nil {
+		return notification, err
+	}
+	if tmpl.Email != nil {
+		if notification.Subject, err = renderText(tmpl.Email.Subject, data); err != nil {
+			return notification, err
+		}
+	}
+	for name, webhook := range tmpl.Webhook {
+		rendered := WebhookTemplate{Method: webhook.Method}
+		if rendered.Path, err = renderText(webhook.Path, data); err != nil {
+			return notification, err
+		}
+		if rendered.Body, err = renderText(webhook.Body, data); err != nil {
+			return notification, err
+		}
+		notification.Webhook[name] = rendered
+	}
+	return notification, nil
+}
+
+func renderText(text string, data templateData) (string, error) {
+	t, err := template.New("").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,151 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/utils/pointer"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+)
+
+var rolloutKind = v1alpha1.SchemeGroupVersion.WithKind("Rollout")
+
+// newRollout returns a canary rollout in the middle of its steps
+func newRollout() *v1alpha1.Rollout {
+	r := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Namespace:   metav1.NamespaceDefault,
+			UID:         uuid.NewUUID(),
+			Annotations: map[string]string{},
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Replicas: pointer.Int32Ptr(1),
+			Strategy: v1alpha1.RolloutStrategy{
+				CanaryStrategy: &v1alpha1.CanaryStrategy{
+					Steps: []v1alpha1.CanaryStep{
+						{SetWeight: pointer.Int32Ptr(50)},
+						{Pause: &v1alpha1.RolloutPause{}},
+					},
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "foo", Image: "foo:v2"}},
+				},
+			},
+		},
+		Status: v1alpha1.RolloutStatus{
+			CurrentPodHash:   "abcdef",
+			CurrentStepIndex: pointer.Int32Ptr(1),
+			Canary: v1alpha1.CanaryStatus{
+				StableRS: "123456",
+			},
+		},
+	}
+	return r
+}
+
+// completeRollout updates the status of the rollout so it completed updating to its current pod template
+func completeRollout(r *v1alpha1.Rollout) {
+	r.Status.CurrentStepIndex = pointer.Int32Ptr(int32(len(r.Spec.Strategy.CanaryStrategy.Steps)))
+	r.Status.Canary.StableRS = r.Status.CurrentPodHash
+	r.Status.Replicas = 1
+	r.Status.UpdatedReplicas = 1
+	r.Status.AvailableReplicas = 1
+	r.Status.ObservedGeneration = conditions.ComputeGenerationHash(r.Spec)
+}
+
+func newAnalysisRun(r *v1alpha1.Rollout, name string, status v1alpha1.AnalysisStatus) *v1alpha1.AnalysisRun {
+	return &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       r.Namespace,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(r, rolloutKind)},
+		},
+		Status: &v1alpha1.AnalysisRunStatus{
+			Status: status,
+		},
+	}
+}
+
+func eventTriggers(events []event) map[string]string {
+	triggers := map[string]string{}
+	for _, e := range events {
+		triggers[e.trigger] = e.key
+	}
+	return triggers
+}
+
+func TestGetEvents(t *testing.T) {
+	r := newRollout()
+	assert.Equal(t, map[string]string{TriggerRolloutStepCompleted: "abcdef/step/1"}, eventTriggers(getEvents(r, nil)))
+
+	pauseStartTime := metav1.Unix(1577836800, 0)
+	r.Status.PauseConditions = []v1alpha1.PauseCondition{{Reason: v1alpha1.PauseReasonCanaryPauseStep, StartTime: pauseStartTime}}
+	r.Status.Abort = true
+	failedRun := newAnalysisRun(r, "foo-abcdef-1", v1alpha1.AnalysisStatusFailed)
+	erroredRun := newAnalysisRun(r, "foo-abcdef-2", v1alpha1.AnalysisStatusError)
+	runningRun := newAnalysisRun(r, "foo-abcdef-3", v1alpha1.AnalysisStatusRunning)
+	otherRun := newAnalysisRun(newRollout(), "bar-abcdef-1", v1alpha1.AnalysisStatusFailed)
+	events := getEvents(r, []*v1alpha1.AnalysisRun{failedRun, erroredRun, runningRun, otherRun})
+	assert.Len(t, events, 5)
+	assert.Equal(t, "abcdef/aborted", eventTriggers(events)[TriggerRolloutAborted])
+	assert.Equal(t, "abcdef/paused/1577836800", eventTriggers(events)[TriggerRolloutPaused])
+	assert.Equal(t, event{trigger: TriggerAnalysisRunFailed, key: "analysisrun/foo-abcdef-1", analysisRun: failedRun}, events[3])
+	assert.Equal(t, event{trigger: TriggerAnalysisRunFailed, key: "analysisrun/foo-abcdef-2", analysisRun: erroredRun}, events[4])
+
+	completed := newRollout()
+	completeRollout(completed)
+	assert.Equal(t, map[string]string{
+		TriggerRolloutCompleted:     "abcdef/completed",
+		TriggerRolloutStepCompleted: "abcdef/step/2",
+	}, eventTriggers(getEvents(completed, nil)))
+}
+
+func TestGetSubscriptions(t *testing.T) {
+	r := newRollout()
+	r.Annotations = map[string]string{
+		SubscribeAnnotationPrefix + "on-rollout-completed.slack":          "#rollouts; #team",
+		SubscribeAnnotationPrefix + "on-rollout-completed.webhook.github": "",
+		SubscribeAnnotationPrefix + "on-rollout-aborted.email":            "dev@example.com",
+		SubscribeAnnotationPrefix + "invalid":                             "foo",
+		"unrelated":                                                       "foo",
+	}
+	subscriptions := getSubscriptions(r)
+	assert.Len(t, subscriptions, 2)
+	assert.ElementsMatch(t, []subscription{
+		{service: "slack", recipients: []string{"#rollouts", "#team"}},
+		{service: "webhook.github", recipients: []string{""}},
+	}, subscriptions[TriggerRolloutCompleted])
+	assert.Equal(t, []subscription{{service: "email", recipients: []string{"dev@example.com"}}}, subscriptions[TriggerRolloutAborted])
+}
+
+func TestRender(t *testing.T) {
+	r := newRollout()
+	run := newAnalysisRun(r, "foo-abcdef-1", v1alpha1.AnalysisStatusFailed)
+	tmpl := Template{
+		Message: "{{.Trigger}}: AnalysisRun {{.AnalysisRun.Name}} of rollout {{.Rollout.Name}} failed",
+		Email:   &EmailTemplate{Subject: "{{.Rollout.Namespace}}/{{.Rollout.Name}}"},
+		Webhook: map[string]WebhookTemplate{
+			"github": {Method: "POST", Path: "/{{.Rollout.Status.CurrentPodHash}}", Body: `{"state":"{{.AnalysisRun.Status.Status}}"}`},
+		},
+	}
+	notification, err := render(tmpl, r, event{trigger: TriggerAnalysisRunFailed, analysisRun: run})
+	assert.NoError(t, err)
+	assert.Equal(t, Notification{
+		Message: "on-analysis-run-failed: AnalysisRun foo-abcdef-1 of rollout foo failed",
+		Subject: "default/foo",
+		Webhook: map[string]WebhookTemplate{
+			"github": {Method: "POST", Path: "/abcdef", Body: `{"state":"Failed"}`},
+		},
+	}, notification)
+
+	_, err = render(Template{Message: "{{.Rollout.Name"}, r, event{trigger: TriggerRolloutCompleted})
+	assert.Error(t, err)
+}
@@ -23,10 +23,43 @@ type AnalysisTemplateList struct {
 	Items           []AnalysisTemplate `json:"items"`
 }
 
+// ClusterAnalysisTemplate holds the template for performing canary analysis which can be referenced by
+// rollouts in any namespace
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterAnalysisTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AnalysisTemplateSpec `json:"spec"`
+}
+
+// ClusterAnalysisTemplateList is a list of ClusterAnalysisTemplate resources
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ClusterAnalysisTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []ClusterAnalysisTemplate `json:"items"`
+}
+
 // AnalysisTemplateSpec is the specification for a AnalysisTemplate resource
 type AnalysisTemplateSpec struct {
 	// Metrics contains the list of metrics to query as part of an analysis run
 	Metrics []Metric `json:"metrics"`
+	// Args declares the arguments expected by the metrics. When set, the arguments passed to an analysis run are
+	// validated against them and the missing ones get their default value
+	Args []AnalysisTemplateArgument `json:"args,omitempty"`
+}
+
+// AnalysisTemplateArgument declares an argument expected by the metrics of an analysis template
+type AnalysisTemplateArgument struct {
+	// Name is the name of the argument, referenced as {{input.<name>}} by the metrics
+	Name string `json:"name"`
+	// Default is the value of the argument when it is not passed. An argument without a default is required
+	Default *string `json:"default,omitempty"`
+	// Description describes the argument
+	Description string `json:"description,omitempty"`
 }
 
 // Metric defines a metric in which to perform analysis
@@ -89,6 +122,12 @@ type MetricProvider struct {
 	Prometheus *PrometheusMetric `json:"prometheus,omitempty"`
 	// Job specifies the job metric run
 	Job *JobMetric `json:"job,omitempty"`
+	// Web specifies the HTTP request to perform
+	Web *WebMetric `json:"web,omitempty"`
+	// Datadog specifies the datadog metric to query
+	Datadog *DatadogMetric `json:"datadog,omitempty"`
+	// Kayenta specifies the Kayenta canary analysis to perform
+	Kayenta *KayentaMetric `json:"kayenta,omitempty"`
 }
 
 // AnalysisStatus is the overall status of an AnalysisRun, MetricResult, or Measurement
@@ -119,6 +158,65 @@ type PrometheusMetric struct {
 	Server string `json:"server,omitempty"`
 	// Query is a raw prometheus query to perform
 	Query string `json:"query,omitempty"`
+	// Range performs a range query over the given time range instead of an instant query
+	Range *PrometheusRange `json:"range,omitempty"`
+	// TimeoutSeconds is the timeout of the query in seconds (default: 30)
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+	// Authentication holds the credentials used to query the server
+	Authentication *PrometheusAuthentication `json:"authentication,omitempty"`
+	// Headers are optional HTTP headers to use in the queries
+	Headers []WebMetricHeader `json:"headers,omitempty"`
+	// Insecure skips the TLS verification of the server certificate
+	Insecure bool `json:"insecure,omitempty"`
+	// TLS holds the certificates used to connect to the server over TLS
+	TLS *PrometheusTLSConfig `json:"tls,omitempty"`
+}
+
+// PrometheusTLSConfig defines the certificates used to connect to a prometheus server over TLS. The certificates are
+// PEM encoded and read from keys of Secrets in the namespace of the AnalysisRun.
+type PrometheusTLSConfig struct {
+	// CA references the CA bundle used to verify the server certificate instead of the system CAs
+	CA *SecretKeyRef `json:"ca,omitempty"`
+	// Cert references the client certificate presented to the server. It requires Key.
+	Cert *SecretKeyRef `json:"cert,omitempty"`
+	// Key references the private key of the client certificate. It requires Cert.
+	Key *SecretKeyRef `json:"key,omitempty"`
+}
+
+// PrometheusRange defines the time range and resolution of a prometheus range query. The durations are relative to
+// the time of the measurement and use the prometheus duration format (e.g. 30s, 10m, 1h).
+type PrometheusRange struct {
+	// Start is how long before the measurement the range starts
+	Start string `json:"start"`
+	// End is how long before the measurement the range ends (default: the time of the measurement)
+	End string `json:"end,omitempty"`
+	// Step is the resolution of the query
+	Step string `json:"step"`
+}
+
+// PrometheusAuthentication defines the credentials used to query a prometheus server. The bearer token and the basic
+// authentication are mutually exclusive.
+type PrometheusAuthentication struct {
+	// BearerToken references the key of a Secret in the namespace of the AnalysisRun holding a bearer token
+	BearerToken *SecretKeyRef `json:"bearerToken,omitempty"`
+	// BasicAuth holds the username and password of a basic authentication
+	BasicAuth *PrometheusBasicAuth `json:"basicAuth,omitempty"`
+}
+
+// PrometheusBasicAuth defines the username and password of a basic authentication
+type PrometheusBasicAuth struct {
+	// Username is the username of the basic authentication
+	Username string `json:"username"`
+	// Password references the key of a Secret in the namespace of the AnalysisRun holding the password
+	Password SecretKeyRef `json:"password"`
+}
+
+// DatadogMetric defines the datadog query to perform canary analysis
+type DatadogMetric struct {
+	// Interval is the time window of the query ending now, expressed as a duration (default: 5m)
+	Interval string `json:"interval,omitempty"`
+	// Query is a raw datadog timeseries query to perform
+	Query string `json:"query"`
 }
 
 // JobMetric defines a job to run which acts as a metric
@@ -127,6 +225,109 @@ type JobMetric struct {
 	Spec     batchv1.JobSpec   `json:"spec"`
 }
 
+// KayentaMetric defines a Kayenta standalone canary analysis comparing the metrics of a control and an experiment
+type KayentaMetric struct {
+	// Address is the address of the Kayenta server
+	Address string `json:"address"`
+	// Application is the name of the application the analysis is performed for
+	Application string `json:"application"`
+	// CanaryConfigName is the name of the Kayenta canary config
+	CanaryConfigName string `json:"canaryConfigName"`
+	// MetricsAccountName is the name of the Kayenta account to query the metrics from
+	MetricsAccountName string `json:"metricsAccountName"`
+	// ConfigurationAccountName is the name of the Kayenta account holding the canary config
+	ConfigurationAccountName string `json:"configurationAccountName"`
+	// StorageAccountName is the name of the Kayenta account to store the results in
+	StorageAccountName string `json:"storageAccountName"`
+	// Threshold holds the scores used to judge the canary
+	Threshold KayentaThreshold `json:"threshold"`
+	// Scopes is the list of control and experiment scopes to compare
+	Scopes []KayentaScope `json:"scopes"`
+	// LifetimeDuration is the ISO 8601 duration of the analysis when the scopes have no end time (e.g. PT30M)
+	LifetimeDuration string `json:"lifetimeDuration,omitempty"`
+	// AnalysisIntervalMins is the number of minutes between the judgements of the analysis during its lifetime
+	AnalysisIntervalMins int64 `json:"analysisIntervalMins,omitempty"`
+}
+
+// KayentaThreshold defines the scores used to judge a Kayenta canary analysis
+type KayentaThreshold struct {
+	// Pass is the minimum score for the canary to be successful
+	Pass int32 `json:"pass"`
+	// Marginal is the minimum score for the canary to be inconclusive instead of failed
+	Marginal int32 `json:"marginal"`
+}
+
+// KayentaScope defines the control and experiment scopes of a Kayenta canary analysis
+type KayentaScope struct {
+	// ControlScope is the scope of the control metrics (e.g. the stable pod template hash)
+	ControlScope string `json:"controlScope"`
+	// ControlLocation is the location (e.g. region) of the control metrics
+	ControlLocation string `json:"controlLocation,omitempty"`
+	// ExperimentScope is the scope of the experiment metrics (e.g. the canary pod template hash)
+	ExperimentScope string `json:"experimentScope"`
+	// ExperimentLocation is the location (e.g. region) of the experiment metrics
+	ExperimentLocation string `json:"experimentLocation,omitempty"`
+	// Step is the interval in seconds between the data points of the metrics
+	Step int64 `json:"step,omitempty"`
+	// StartTime is the ISO 8601 start time of the metrics (default: the start of the analysis)
+	StartTime string `json:"startTime,omitempty"`
+	// EndTime is the ISO 8601 end time of the metrics
+	EndTime string `json:"endTime,omitempty"`
+	// ExtendedScopeParams are additional parameters of the scope passed to the metrics service
+	ExtendedScopeParams map[string]string `json:"extendedScopeParams,omitempty"`
+}
+
+// WebMetricMethod is the HTTP method of a web metric request
+type WebMetricMethod string
+
+// Possible WebMetricMethod values
+const (
+	WebMetricMethodGet  WebMetricMethod = "GET"
+	WebMetricMethodPost WebMetricMethod = "POST"
+)
+
+// WebMetric defines an HTTP request to perform whose JSON response is used as a metric
+type WebMetric struct {
+	// Method is the HTTP method of the request. One of GET or POST (default: GET)
+	Method WebMetricMethod `json:"method,omitempty"`
+	// URL is the address of the web metric
+	URL string `json:"url"`
+	// Headers are optional HTTP headers to use in the request
+	Headers []WebMetricHeader `json:"headers,omitempty"`
+	// Body is the body of a POST request
+	Body string `json:"body,omitempty"`
+	// TimeoutSeconds is the timeout of the request in seconds (default: 10)
+	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+	// JSONPath is a JSON Path expression to extract the result from the response (default: "{$}")
+	JSONPath string `json:"jsonPath,omitempty"`
+	// Insecure skips the TLS verification of the server certificate
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// WebMetricHeader defines an HTTP header of a web metric request
+type WebMetricHeader struct {
+	// Key is the name of the header
+	Key string `json:"key"`
+	// Value is the value of the header
+	Value string `json:"value,omitempty"`
+	// ValueFrom is a reference to where the value of the header is stored. This field is a one of field with value
+	ValueFrom *WebMetricHeaderValueFrom `json:"valueFrom,omitempty"`
+}
+
+// WebMetricHeaderValueFrom defines references to where the value of a header is stored
+type WebMetricHeaderValueFrom struct {
+	// SecretKeyRef references a key of a Secret in the namespace of the AnalysisRun
+	SecretKeyRef *SecretKeyRef `json:"secretKeyRef,omitempty"`
+}
+
+// SecretKeyRef references a key of a Secret
+type SecretKeyRef struct {
+	// Name is the name of the secret
+	Name string `json:"name"`
+	// Key is the key of the secret to select from
+	Key string `json:"key"`
+}
+
 // AnalysisRun is an instantiation of an AnalysisTemplate
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -161,8 +362,17 @@ type AnalysisRunSpec struct {
 type Argument struct {
 	// Name is the name of the argument
 	Name string `json:"name"`
-	// Value is the value of the argument
-	Value string `json:"value"`
+	// Value is the value of the argument. This field is a one of field with valueFrom
+	Value string `json:"value,omitempty"`
+	// ValueFrom is a reference to where the value of the argument is stored. This field is a one of field with value
+	ValueFrom *ValueFrom `json:"valueFrom,omitempty"`
+}
+
+// ValueFrom defines references to where the value of an argument is stored. The value is only read when the
+// measurements are taken and is never stored in the AnalysisRun
+type ValueFrom struct {
+	// SecretKeyRef references a key of a Secret in the namespace of the AnalysisRun
+	SecretKeyRef *SecretKeyRef `json:"secretKeyRef,omitempty"`
 }
 
 // AnalysisRunStatus is the status for a AnalysisRun resource
@@ -212,6 +422,8 @@ type Measurement struct {
 	StartedAt *metav1.Time `json:"startedAt,omitempty"`
 	// FinishedAt is the timestamp in which this measurement completed and value was collected
 	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+	// ResumeAt is the timestamp when the analysisRun should try to resume the in-progress measurement
+	ResumeAt *metav1.Time `json:"resumeAt,omitempty"`
 	// Value is the measured value of the metric
 	Value string `json:"value,omitempty"`
 	// Metadata stores additional metadata about this metric result, used by the different providers
@@ -32,6 +32,25 @@ type ExperimentSpec struct {
 	// Defaults to 600s.
 	// +optional
 	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+	// Analyses references AnalysisTemplates to run during the experiment. The experiment fails if any of
+	// the AnalysisRuns fails.
+	// +optional
+	Analyses []ExperimentAnalysisTemplateRef `json:"analyses,omitempty"`
+}
+
+// ExperimentAnalysisTemplateRef defines an AnalysisRun which is run during an experiment
+type ExperimentAnalysisTemplateRef struct {
+	// Name is the name of the analysis within the experiment
+	Name string `json:"name"`
+	// TemplateName reference of the AnalysisTemplate name used by the Experiment to create the run
+	TemplateName string `json:"templateName"`
+	// Arguments the arguments that will be added to the AnalysisRun. The value of an argument can reference the
+	// pod template hash of a template with {{templates.<name>.podTemplateHash}}
+	// +optional
+	Arguments []Argument `json:"arguments,omitempty"`
+	// RequiredForCompletion indicates the experiment completes once this analysis finishes
+	// +optional
+	RequiredForCompletion bool `json:"requiredForCompletion,omitempty"`
 }
 
 type TemplateSpec struct {
@@ -92,6 +111,25 @@ type ExperimentStatus struct {
 	// Conditions a list of conditions a experiment can have.
 	// +optional
 	Conditions []ExperimentCondition `json:"conditions,omitempty"`
+	// Status is the result of the analyses of the experiment. It is only set when the experiment has analyses.
+	// +optional
+	Status AnalysisStatus `json:"status,omitempty"`
+	// AnalysisRuns tracks the status of the AnalysisRuns created for the analyses of the experiment
+	// +optional
+	AnalysisRuns []ExperimentAnalysisRunStatus `json:"analysisRuns,omitempty"`
+}
+
+// ExperimentAnalysisRunStatus is the status of the AnalysisRun of an analysis of an Experiment
+type ExperimentAnalysisRunStatus struct {
+	// Name is the name of the analysis
+	Name string `json:"name"`
+	// AnalysisRun is the name of the AnalysisRun
+	AnalysisRun string `json:"analysisRun"`
+	// Status is the status of the AnalysisRun
+	Status AnalysisStatus `json:"status"`
+	// Message is a message explaining the current status
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // ExperimentConditionType defines the conditions of Experiment
@@ -36,7 +36,17 @@ type RolloutSpec struct {
 	// It must match the pod template's labels.
 	Selector *metav1.LabelSelector `json:"selector"`
 	// Template describes the pods that will be created.
+	// +optional
 	Template corev1.PodTemplateSpec `json:"template"`
+	// WorkloadRef references a workload in the same namespace whose pod template is used instead of the
+	// Template. The Template must be empty when WorkloadRef is set.
+	// +optional
+	WorkloadRef *WorkloadRef `json:"workloadRef,omitempty"`
+	// MigrateFromDeployment is the name of a Deployment in the same namespace whose ReplicaSet is adopted by the
+	// Rollout when its pod template matches the Rollout's template, so the first revision of the Rollout reuses the
+	// pods of the Deployment instead of creating new ones.
+	// +optional
+	MigrateFromDeployment string `json:"migrateFromDeployment,omitempty"`
 	// Minimum number of seconds for which a newly created pod should be ready
 	// without any of its container crashing, for it to be considered available.
 	// Defaults to 0 (pod will be considered available as soon as it is ready)
@@ -56,6 +66,35 @@ type RolloutSpec struct {
 	// Note that progress will not be estimated during the time a rollout is paused.
 	// Defaults to 600s.
 	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+	// RollbackTo restores the pod template of a previous revision of the Rollout. The controller clears the field
+	// once the rollback is done.
+	// +optional
+	RollbackTo *RollbackConfig `json:"rollbackTo,omitempty"`
+}
+
+// RollbackConfig describes the revision a Rollout is rolled back to
+type RollbackConfig struct {
+	// Revision of the ReplicaSet whose pod template is restored. Rolls back to the previous revision if 0.
+	// +optional
+	Revision int64 `json:"revision,omitempty"`
+	// SkipSteps skips the steps of a canary Rollout, so the revision rolled back to is scaled straight to 100%. It
+	// becomes the stable revision once it is fully available. The steps are always skipped when rolling back to the
+	// current stable revision.
+	// +optional
+	SkipSteps bool `json:"skipSteps,omitempty"`
+}
+
+// WorkloadRef references a workload whose pod template is used by the Rollout
+type WorkloadRef struct {
+	// APIVersion of the referenced workload. Only apps/v1 is supported
+	APIVersion string `json:"apiVersion"`
+	// Kind of the referenced workload. Only Deployment is supported
+	Kind string `json:"kind"`
+	// Name of the referenced workload
+	Name string `json:"name"`
+	// ScaleDown scales the referenced workload down to zero once the Rollout is healthy
+	// +optional
+	ScaleDown bool `json:"scaleDown,omitempty"`
 }
 
 const (
@@ -107,8 +146,38 @@ type BlueGreenStrategy struct {
 	// ScaleDownDelayRevisionLimit limits the number of old RS that can run at one time before getting scaled down
 	// +optional
 	ScaleDownDelayRevisionLimit *int32 `json:"scaleDownDelayRevisionLimit,omitempty"`
+	// PrePromotionAnalysis configuration to run analysis on the preview ReplicaSet before the active service
+	// switches to it. The active service is only switched once the analysis is successful.
+	// +optional
+	PrePromotionAnalysis *RolloutAnalysisStep `json:"prePromotionAnalysis,omitempty"`
+	// PostPromotionAnalysis configuration to run analysis on the new ReplicaSet after the active service switches
+	// to it. If the analysis fails, the active service is switched back to the previous ReplicaSet.
+	// +optional
+	PostPromotionAnalysis *RolloutAnalysisStep `json:"postPromotionAnalysis,omitempty"`
+	// AntiAffinity enables anti-affinity rules for Blue Green deployment
+	// +optional
+	AntiAffinity *AntiAffinity `json:"antiAffinity,omitempty"`
+}
+
+// AntiAffinity defines which inter-pod scheduling rule to use for anti-affinity injection
+type AntiAffinity struct {
+	// PreferredDuringSchedulingIgnoredDuringExecution prefers to schedule the new pods away from the stable pods
+	// +optional
+	PreferredDuringSchedulingIgnoredDuringExecution *PreferredDuringSchedulingIgnoredDuringExecution `json:"preferredDuringSchedulingIgnoredDuringExecution,omitempty"`
+	// RequiredDuringSchedulingIgnoredDuringExecution requires the new pods to be scheduled away from the stable pods
+	// +optional
+	RequiredDuringSchedulingIgnoredDuringExecution *RequiredDuringSchedulingIgnoredDuringExecution `json:"requiredDuringSchedulingIgnoredDuringExecution,omitempty"`
+}
+
+// PreferredDuringSchedulingIgnoredDuringExecution defines the weight of the anti-affinity injection
+type PreferredDuringSchedulingIgnoredDuringExecution struct {
+	// Weight associated with matching the corresponding podAffinityTerm, in the range 1-100.
+	Weight int32 `json:"weight"`
 }
 
+// RequiredDuringSchedulingIgnoredDuringExecution defines inter-pod scheduling rule to be RequiredDuringSchedulingIgnoredDuringExecution
+type RequiredDuringSchedulingIgnoredDuringExecution struct{}
+
 // CanaryStrategy defines parameters for a Replica Based Canary
 type CanaryStrategy struct {
 	// CanaryService holds the name of a service which selects pods with canary version and don't select any pods with stable version.
@@ -144,6 +213,58 @@ type CanaryStrategy struct {
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
 	// Analysis runs a separate analysisRun while all the steps execute. This is intended to be a continuous validation of the new ReplicaSet
 	Analysis *RolloutAnalysisStep `json:"analysis,omitempty"`
+	// StableService holds the name of a service which selects pods with stable version and don't select any pods with canary version.
+	// +optional
+	StableService string `json:"stableService,omitempty"`
+	// TrafficRouting hosts all the supported service meshes supported to enable more fine-grained traffic routing
+	// +optional
+	TrafficRouting *RolloutTrafficRouting `json:"trafficRouting,omitempty"`
+	// AntiAffinity enables anti-affinity rules for Canary deployment
+	// +optional
+	AntiAffinity *AntiAffinity `json:"antiAffinity,omitempty"`
+}
+
+// RolloutTrafficRouting hosts all the different configuration for supported service meshes to enable more fine-grained traffic routing
+type RolloutTrafficRouting struct {
+	// Istio holds Istio specific configuration to route traffic
+	Istio *IstioTrafficRouting `json:"istio,omitempty"`
+	// Nginx holds Nginx Ingress specific configuration to route traffic
+	Nginx *NginxTrafficRouting `json:"nginx,omitempty"`
+	// SMI holds TrafficSplit specific configuration to route traffic
+	SMI *SMITrafficRouting `json:"smi,omitempty"`
+}
+
+// SMITrafficRouting configuration for TrafficSplit Custom Resource to control traffic routing
+type SMITrafficRouting struct {
+	// RootService holds the name of the Service that clients use to communicate. Defaults to the StableService
+	// +optional
+	RootService string `json:"rootService,omitempty"`
+	// TrafficSplitName holds the name of the TrafficSplit. Defaults to the name of the Rollout
+	// +optional
+	TrafficSplitName string `json:"trafficSplitName,omitempty"`
+}
+
+// NginxTrafficRouting configuration for Nginx ingress controller to control traffic routing
+type NginxTrafficRouting struct {
+	// AnnotationPrefix has to match the configured annotation prefix on the nginx ingress controller
+	// +optional
+	AnnotationPrefix string `json:"annotationPrefix,omitempty"`
+	// StableIngress refers to the name of an `Ingress` resource in the same namespace as the `Rollout`
+	StableIngress string `json:"stableIngress"`
+}
+
+// IstioTrafficRouting configuration for Istio service mesh to enable fine grain configuration
+type IstioTrafficRouting struct {
+	// VirtualService references an Istio VirtualService to modify to shape traffic
+	VirtualService IstioVirtualService `json:"virtualService"`
+}
+
+// IstioVirtualService holds information on the virtual service the rollout needs to modify
+type IstioVirtualService struct {
+	// Name holds the name of the VirtualService
+	Name string `json:"name"`
+	// Routes list of HTTP routes within the VirtualService to edit
+	Routes []string `json:"routes"`
 }
 
 // RolloutExperimentStep defines a template that is used to create a experiment for a step
@@ -152,6 +273,9 @@ type RolloutExperimentStep struct {
 	Duration int32 `json:"duration"`
 	// Templates what templates that should be added to the experiment. Should be non-nil
 	Templates []RolloutExperimentTemplate `json:"templates"`
+	// Analyses what analyses to run during the experiment. The step fails if any of the analyses fails
+	// +optional
+	Analyses []ExperimentAnalysisTemplateRef `json:"analyses,omitempty"`
 	//ProgressingDeadlineSeconds Is it ncessary?
 }
 
@@ -205,11 +329,23 @@ type CanaryStep struct {
 // RolloutAnalysisStep defines a template that is used to create a analysisRun
 type RolloutAnalysisStep struct {
 	// TemplateName reference of the AnalysisTemplate name used by the Rollout to create the run
-	TemplateName string `json:"templateName"`
+	TemplateName string `json:"templateName,omitempty"`
+	// ClusterScope indicates the TemplateName references a ClusterAnalysisTemplate instead of an AnalysisTemplate
+	ClusterScope bool `json:"clusterScope,omitempty"`
+	// Templates references to the AnalysisTemplates whose metrics are merged to create the run
+	Templates []RolloutAnalysisTemplate `json:"templates,omitempty"`
 	// Arguments the arguments that will be added to the AnalysisRuns
 	Arguments []AnalysisRunArgument `json:"arguments,omitempty"`
 }
 
+// RolloutAnalysisTemplate references an AnalysisTemplate or a ClusterAnalysisTemplate used to create an analysisRun
+type RolloutAnalysisTemplate struct {
+	// TemplateName name of the AnalysisTemplate
+	TemplateName string `json:"templateName"`
+	// ClusterScope indicates the TemplateName references a ClusterAnalysisTemplate instead of an AnalysisTemplate
+	ClusterScope bool `json:"clusterScope,omitempty"`
+}
+
 // AnalysisRunArgument argument to add to analysisRun
 type AnalysisRunArgument struct {
 	// Name argument name
@@ -224,6 +360,18 @@ type AnalysisRunArgument struct {
 type ArgumentValueFrom struct {
 	// PodTemplateHashValue gets the value from one of the children ReplicaSet's Pod Template Hash
 	PodTemplateHashValue *ValueFromPodTemplateHash `json:"podTemplateHashValue,omitempty"`
+	// FieldRef gets the value from a field of the Rollout metadata
+	FieldRef *FieldRef `json:"fieldRef,omitempty"`
+	// SecretKeyRef gets the value from a key of a Secret in the namespace of the Rollout. The value is only read when
+	// the measurements are taken and is never stored in the AnalysisRun
+	SecretKeyRef *SecretKeyRef `json:"secretKeyRef,omitempty"`
+}
+
+// FieldRef selects a field of the Rollout metadata
+type FieldRef struct {
+	// FieldPath is the path of the field, one of metadata.name, metadata.namespace, metadata.uid,
+	// metadata.labels['<KEY>'] or metadata.annotations['<KEY>']
+	FieldPath string `json:"fieldPath"`
 }
 
 // ValueFromPodTemplateHash indicates which ReplicaSet pod template pod hash to use
@@ -243,6 +391,10 @@ const (
 	RolloutTypeStepLabel = "Step"
 	// RolloutTypeBackgroundRunLabel indicates that the analysisRun was created in Background to an execution
 	RolloutTypeBackgroundRunLabel = "Background"
+	// RolloutTypePrePromotionLabel indicates that the analysisRun was created before the active service promotion
+	RolloutTypePrePromotionLabel = "PrePromotion"
+	// RolloutTypePostPromotionLabel indicates that the analysisRun was created after the active service promotion
+	RolloutTypePostPromotionLabel = "PostPromotion"
 	// RolloutCanaryStepIndexLabel indicates which step created this analysisRun
 	RolloutCanaryStepIndexLabel = "step-index"
 )
@@ -254,6 +406,28 @@ type RolloutPause struct {
 	Duration *int32 `json:"duration,omitempty"`
 }
 
+// PauseReason reasons that the rollout can pause
+type PauseReason string
+
+const (
+	// PauseReasonUser means the rollout was paused by a user setting spec.paused
+	PauseReasonUser PauseReason = "UserPause"
+	// PauseReasonCanaryPauseStep means the rollout reached a pause step of the canary strategy
+	PauseReasonCanaryPauseStep PauseReason = "CanaryPauseStep"
+	// PauseReasonBlueGreenPause means the rollout is waiting for the new ReplicaSet to be promoted to the active service
+	PauseReasonBlueGreenPause PauseReason = "BlueGreenPause"
+	// PauseReasonInconclusiveAnalysis means an AnalysisRun of the rollout completed with an inconclusive status
+	PauseReasonInconclusiveAnalysis PauseReason = "InconclusiveAnalysisRun"
+)
+
+// PauseCondition the reason for a pause and when it started
+type PauseCondition struct {
+	// Reason why the rollout is paused
+	Reason PauseReason `json:"reason"`
+	// StartTime the time the pause condition was added
+	StartTime metav1.Time `json:"startTime"`
+}
+
 // RolloutStatus is the status for a Rollout resource
 type RolloutStatus struct {
 	// CurrentPodHash the hash of the current pod template
@@ -282,6 +456,9 @@ type RolloutStatus struct {
 	// PauseStartTime this field is set when the rollout is in a pause step and indicates the time the wait started at
 	// +optional
 	PauseStartTime *metav1.Time `json:"pauseStartTime,omitempty"`
+	// PauseConditions the reasons why the rollout is currently paused
+	// +optional
+	PauseConditions []PauseCondition `json:"pauseConditions,omitempty"`
 	// Count of hash collisions for the Rollout. The Rollout controller uses this
 	// field as a collision avoidance mechanism when it needs to create the name for the
 	// newest ReplicaSet.
@@ -305,6 +482,10 @@ type RolloutStatus struct {
 	// Selector that identifies the pods that are receiving active traffic
 	// +optional
 	Selector string `json:"selector,omitempty"`
+	// Abort cancels the current rollout progression. The controller sets this field when an AnalysisRun owned by
+	// the rollout fails, and it is cleared when the pod template changes or the rollout is retried.
+	// +optional
+	Abort bool `json:"abort,omitempty"`
 }
 
 // BlueGreenStatus status fields that only pertain to the blueGreen rollout
@@ -316,8 +497,7 @@ type BlueGreenStatus struct {
 	// +optional
 	ActiveSelector string `json:"activeSelector,omitempty"`
 	// PreviousActiveSelector indicates the last selector that the active service used. This is used to know which replicaset
-	// to avoid scaling down for the scale down delay
-	// Deprecated: PreviousActiveSelector is tracked with the replicaset now instead of the rollout. will remove in v0.6
+	// the active service switches back to if the post-promotion analysis fails
 	// +optional
 	PreviousActiveSelector string `json:"previousActiveSelector,omitempty"`
 	// ScaleDownDelayStartTime indicates the start of the scaleDownDelay
@@ -327,6 +507,12 @@ type BlueGreenStatus struct {
 	// ScaleUpPreviewCheckPoint indicates that the Replicaset receiving traffic from the preview service is ready to be scaled up after the rollout is unpaused
 	// +optional
 	ScaleUpPreviewCheckPoint bool `json:"scaleUpPreviewCheckPoint,omitempty"`
+	// PrePromotionAnalysisRun indicates the AnalysisRun run before the active service switches to the new ReplicaSet
+	// +optional
+	PrePromotionAnalysisRun string `json:"prePromotionAnalysisRun,omitempty"`
+	// PostPromotionAnalysisRun indicates the AnalysisRun run after the active service switches to the new ReplicaSet
+	// +optional
+	PostPromotionAnalysisRun string `json:"postPromotionAnalysisRun,omitempty"`
 }
 
 // CanaryStatus status fields that only pertain to the canary rollout
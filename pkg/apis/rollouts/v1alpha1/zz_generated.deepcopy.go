@@ -119,7 +119,9 @@ func (in *AnalysisRunSpec) DeepCopyInto(out *AnalysisRunSpec) {
 	if in.Arguments != nil {
 		in, out := &in.Arguments, &out.Arguments
 		*out = make([]Argument, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ReplicaSets != nil {
 		in, out := &in.ReplicaSets, &out.ReplicaSets
@@ -189,6 +191,27 @@ func (in *AnalysisTemplate) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalysisTemplateArgument) DeepCopyInto(out *AnalysisTemplateArgument) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalysisTemplateArgument.
+func (in *AnalysisTemplateArgument) DeepCopy() *AnalysisTemplateArgument {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalysisTemplateArgument)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AnalysisTemplateList) DeepCopyInto(out *AnalysisTemplateList) {
 	*out = *in
@@ -232,6 +255,13 @@ func (in *AnalysisTemplateSpec) DeepCopyInto(out *AnalysisTemplateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]AnalysisTemplateArgument, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -245,9 +275,40 @@ func (in *AnalysisTemplateSpec) DeepCopy() *AnalysisTemplateSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AntiAffinity) DeepCopyInto(out *AntiAffinity) {
+	*out = *in
+	if in.PreferredDuringSchedulingIgnoredDuringExecution != nil {
+		in, out := &in.PreferredDuringSchedulingIgnoredDuringExecution, &out.PreferredDuringSchedulingIgnoredDuringExecution
+		*out = new(PreferredDuringSchedulingIgnoredDuringExecution)
+		**out = **in
+	}
+	if in.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		in, out := &in.RequiredDuringSchedulingIgnoredDuringExecution, &out.RequiredDuringSchedulingIgnoredDuringExecution
+		*out = new(RequiredDuringSchedulingIgnoredDuringExecution)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AntiAffinity.
+func (in *AntiAffinity) DeepCopy() *AntiAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(AntiAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Argument) DeepCopyInto(out *Argument) {
 	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ValueFrom)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -269,6 +330,16 @@ func (in *ArgumentValueFrom) DeepCopyInto(out *ArgumentValueFrom) {
 		*out = new(ValueFromPodTemplateHash)
 		**out = **in
 	}
+	if in.FieldRef != nil {
+		in, out := &in.FieldRef, &out.FieldRef
+		*out = new(FieldRef)
+		**out = **in
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
 	return
 }
 
@@ -330,6 +401,21 @@ func (in *BlueGreenStrategy) DeepCopyInto(out *BlueGreenStrategy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.PrePromotionAnalysis != nil {
+		in, out := &in.PrePromotionAnalysis, &out.PrePromotionAnalysis
+		*out = new(RolloutAnalysisStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostPromotionAnalysis != nil {
+		in, out := &in.PostPromotionAnalysis, &out.PostPromotionAnalysis
+		*out = new(RolloutAnalysisStep)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AntiAffinity != nil {
+		in, out := &in.AntiAffinity, &out.AntiAffinity
+		*out = new(AntiAffinity)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -420,6 +506,16 @@ func (in *CanaryStrategy) DeepCopyInto(out *CanaryStrategy) {
 		*out = new(RolloutAnalysisStep)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TrafficRouting != nil {
+		in, out := &in.TrafficRouting, &out.TrafficRouting
+		*out = new(RolloutTrafficRouting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AntiAffinity != nil {
+		in, out := &in.AntiAffinity, &out.AntiAffinity
+		*out = new(AntiAffinity)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -433,6 +529,82 @@ func (in *CanaryStrategy) DeepCopy() *CanaryStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAnalysisTemplate) DeepCopyInto(out *ClusterAnalysisTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAnalysisTemplate.
+func (in *ClusterAnalysisTemplate) DeepCopy() *ClusterAnalysisTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAnalysisTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterAnalysisTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterAnalysisTemplateList) DeepCopyInto(out *ClusterAnalysisTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterAnalysisTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterAnalysisTemplateList.
+func (in *ClusterAnalysisTemplateList) DeepCopy() *ClusterAnalysisTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterAnalysisTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterAnalysisTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogMetric) DeepCopyInto(out *DatadogMetric) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogMetric.
+func (in *DatadogMetric) DeepCopy() *DatadogMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Experiment) DeepCopyInto(out *Experiment) {
 	*out = *in
@@ -461,6 +633,45 @@ func (in *Experiment) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExperimentAnalysisRunStatus) DeepCopyInto(out *ExperimentAnalysisRunStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentAnalysisRunStatus.
+func (in *ExperimentAnalysisRunStatus) DeepCopy() *ExperimentAnalysisRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExperimentAnalysisRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExperimentAnalysisTemplateRef) DeepCopyInto(out *ExperimentAnalysisTemplateRef) {
+	*out = *in
+	if in.Arguments != nil {
+		in, out := &in.Arguments, &out.Arguments
+		*out = make([]Argument, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExperimentAnalysisTemplateRef.
+func (in *ExperimentAnalysisTemplateRef) DeepCopy() *ExperimentAnalysisTemplateRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ExperimentAnalysisTemplateRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExperimentCondition) DeepCopyInto(out *ExperimentCondition) {
 	*out = *in
@@ -532,6 +743,13 @@ func (in *ExperimentSpec) DeepCopyInto(out *ExperimentSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Analyses != nil {
+		in, out := &in.Analyses, &out.Analyses
+		*out = make([]ExperimentAnalysisTemplateRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -571,6 +789,11 @@ func (in *ExperimentStatus) DeepCopyInto(out *ExperimentStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AnalysisRuns != nil {
+		in, out := &in.AnalysisRuns, &out.AnalysisRuns
+		*out = make([]ExperimentAnalysisRunStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -584,6 +807,60 @@ func (in *ExperimentStatus) DeepCopy() *ExperimentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldRef) DeepCopyInto(out *FieldRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldRef.
+func (in *FieldRef) DeepCopy() *FieldRef {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioTrafficRouting) DeepCopyInto(out *IstioTrafficRouting) {
+	*out = *in
+	in.VirtualService.DeepCopyInto(&out.VirtualService)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioTrafficRouting.
+func (in *IstioTrafficRouting) DeepCopy() *IstioTrafficRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioTrafficRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IstioVirtualService) DeepCopyInto(out *IstioVirtualService) {
+	*out = *in
+	if in.Routes != nil {
+		in, out := &in.Routes, &out.Routes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IstioVirtualService.
+func (in *IstioVirtualService) DeepCopy() *IstioVirtualService {
+	if in == nil {
+		return nil
+	}
+	out := new(IstioVirtualService)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JobMetric) DeepCopyInto(out *JobMetric) {
 	*out = *in
@@ -602,6 +879,69 @@ func (in *JobMetric) DeepCopy() *JobMetric {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KayentaMetric) DeepCopyInto(out *KayentaMetric) {
+	*out = *in
+	out.Threshold = in.Threshold
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]KayentaScope, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KayentaMetric.
+func (in *KayentaMetric) DeepCopy() *KayentaMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(KayentaMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KayentaScope) DeepCopyInto(out *KayentaScope) {
+	*out = *in
+	if in.ExtendedScopeParams != nil {
+		in, out := &in.ExtendedScopeParams, &out.ExtendedScopeParams
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KayentaScope.
+func (in *KayentaScope) DeepCopy() *KayentaScope {
+	if in == nil {
+		return nil
+	}
+	out := new(KayentaScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KayentaThreshold) DeepCopyInto(out *KayentaThreshold) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KayentaThreshold.
+func (in *KayentaThreshold) DeepCopy() *KayentaThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(KayentaThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Measurement) DeepCopyInto(out *Measurement) {
 	*out = *in
@@ -613,6 +953,10 @@ func (in *Measurement) DeepCopyInto(out *Measurement) {
 		in, out := &in.FinishedAt, &out.FinishedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.ResumeAt != nil {
+		in, out := &in.ResumeAt, &out.ResumeAt
+		*out = (*in).DeepCopy()
+	}
 	if in.Metadata != nil {
 		in, out := &in.Metadata, &out.Metadata
 		*out = make(map[string]string, len(*in))
@@ -666,13 +1010,28 @@ func (in *MetricProvider) DeepCopyInto(out *MetricProvider) {
 	if in.Prometheus != nil {
 		in, out := &in.Prometheus, &out.Prometheus
 		*out = new(PrometheusMetric)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Job != nil {
 		in, out := &in.Job, &out.Job
 		*out = new(JobMetric)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Web != nil {
+		in, out := &in.Web, &out.Web
+		*out = new(WebMetric)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Datadog != nil {
+		in, out := &in.Datadog, &out.Datadog
+		*out = new(DatadogMetric)
+		**out = **in
+	}
+	if in.Kayenta != nil {
+		in, out := &in.Kayenta, &out.Kayenta
+		*out = new(KayentaMetric)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -710,13 +1069,46 @@ func (in *MetricResult) DeepCopy() *MetricResult {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodTemplateMetadata) DeepCopyInto(out *PodTemplateMetadata) {
+func (in *NginxTrafficRouting) DeepCopyInto(out *NginxTrafficRouting) {
 	*out = *in
-	if in.Labels != nil {
-		in, out := &in.Labels, &out.Labels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NginxTrafficRouting.
+func (in *NginxTrafficRouting) DeepCopy() *NginxTrafficRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(NginxTrafficRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PauseCondition) DeepCopyInto(out *PauseCondition) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PauseCondition.
+func (in *PauseCondition) DeepCopy() *PauseCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(PauseCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodTemplateMetadata) DeepCopyInto(out *PodTemplateMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 	if in.Annotations != nil {
@@ -739,9 +1131,90 @@ func (in *PodTemplateMetadata) DeepCopy() *PodTemplateMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreferredDuringSchedulingIgnoredDuringExecution) DeepCopyInto(out *PreferredDuringSchedulingIgnoredDuringExecution) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreferredDuringSchedulingIgnoredDuringExecution.
+func (in *PreferredDuringSchedulingIgnoredDuringExecution) DeepCopy() *PreferredDuringSchedulingIgnoredDuringExecution {
+	if in == nil {
+		return nil
+	}
+	out := new(PreferredDuringSchedulingIgnoredDuringExecution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusAuthentication) DeepCopyInto(out *PrometheusAuthentication) {
+	*out = *in
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(PrometheusBasicAuth)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusAuthentication.
+func (in *PrometheusAuthentication) DeepCopy() *PrometheusAuthentication {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusAuthentication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusBasicAuth) DeepCopyInto(out *PrometheusBasicAuth) {
+	*out = *in
+	out.Password = in.Password
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusBasicAuth.
+func (in *PrometheusBasicAuth) DeepCopy() *PrometheusBasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusBasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PrometheusMetric) DeepCopyInto(out *PrometheusMetric) {
 	*out = *in
+	if in.Range != nil {
+		in, out := &in.Range, &out.Range
+		*out = new(PrometheusRange)
+		**out = **in
+	}
+	if in.Authentication != nil {
+		in, out := &in.Authentication, &out.Authentication
+		*out = new(PrometheusAuthentication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]WebMetricHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(PrometheusTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -755,6 +1228,69 @@ func (in *PrometheusMetric) DeepCopy() *PrometheusMetric {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRange) DeepCopyInto(out *PrometheusRange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRange.
+func (in *PrometheusRange) DeepCopy() *PrometheusRange {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusTLSConfig) DeepCopyInto(out *PrometheusTLSConfig) {
+	*out = *in
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.Cert != nil {
+		in, out := &in.Cert, &out.Cert
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusTLSConfig.
+func (in *PrometheusTLSConfig) DeepCopy() *PrometheusTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequiredDuringSchedulingIgnoredDuringExecution) DeepCopyInto(out *RequiredDuringSchedulingIgnoredDuringExecution) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequiredDuringSchedulingIgnoredDuringExecution.
+func (in *RequiredDuringSchedulingIgnoredDuringExecution) DeepCopy() *RequiredDuringSchedulingIgnoredDuringExecution {
+	if in == nil {
+		return nil
+	}
+	out := new(RequiredDuringSchedulingIgnoredDuringExecution)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Rollout) DeepCopyInto(out *Rollout) {
 	*out = *in
@@ -783,9 +1319,30 @@ func (in *Rollout) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackConfig) DeepCopyInto(out *RollbackConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackConfig.
+func (in *RollbackConfig) DeepCopy() *RollbackConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RolloutAnalysisStep) DeepCopyInto(out *RolloutAnalysisStep) {
 	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]RolloutAnalysisTemplate, len(*in))
+		copy(*out, *in)
+	}
 	if in.Arguments != nil {
 		in, out := &in.Arguments, &out.Arguments
 		*out = make([]AnalysisRunArgument, len(*in))
@@ -806,6 +1363,22 @@ func (in *RolloutAnalysisStep) DeepCopy() *RolloutAnalysisStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutAnalysisTemplate) DeepCopyInto(out *RolloutAnalysisTemplate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutAnalysisTemplate.
+func (in *RolloutAnalysisTemplate) DeepCopy() *RolloutAnalysisTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutAnalysisTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RolloutCondition) DeepCopyInto(out *RolloutCondition) {
 	*out = *in
@@ -834,6 +1407,13 @@ func (in *RolloutExperimentStep) DeepCopyInto(out *RolloutExperimentStep) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Analyses != nil {
+		in, out := &in.Analyses, &out.Analyses
+		*out = make([]ExperimentAnalysisTemplateRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -932,6 +1512,11 @@ func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.Template.DeepCopyInto(&out.Template)
+	if in.WorkloadRef != nil {
+		in, out := &in.WorkloadRef, &out.WorkloadRef
+		*out = new(WorkloadRef)
+		**out = **in
+	}
 	in.Strategy.DeepCopyInto(&out.Strategy)
 	if in.RevisionHistoryLimit != nil {
 		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
@@ -943,6 +1528,11 @@ func (in *RolloutSpec) DeepCopyInto(out *RolloutSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.RollbackTo != nil {
+		in, out := &in.RollbackTo, &out.RollbackTo
+		*out = new(RollbackConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -968,6 +1558,13 @@ func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
 		in, out := &in.PauseStartTime, &out.PauseStartTime
 		*out = (*in).DeepCopy()
 	}
+	if in.PauseConditions != nil {
+		in, out := &in.PauseConditions, &out.PauseConditions
+		*out = make([]PauseCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.CollisionCount != nil {
 		in, out := &in.CollisionCount, &out.CollisionCount
 		*out = new(int32)
@@ -1021,6 +1618,69 @@ func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutTrafficRouting) DeepCopyInto(out *RolloutTrafficRouting) {
+	*out = *in
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(IstioTrafficRouting)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Nginx != nil {
+		in, out := &in.Nginx, &out.Nginx
+		*out = new(NginxTrafficRouting)
+		**out = **in
+	}
+	if in.SMI != nil {
+		in, out := &in.SMI, &out.SMI
+		*out = new(SMITrafficRouting)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutTrafficRouting.
+func (in *RolloutTrafficRouting) DeepCopy() *RolloutTrafficRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutTrafficRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SMITrafficRouting) DeepCopyInto(out *SMITrafficRouting) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SMITrafficRouting.
+func (in *SMITrafficRouting) DeepCopy() *SMITrafficRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(SMITrafficRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretKeyRef) DeepCopyInto(out *SecretKeyRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretKeyRef.
+func (in *SecretKeyRef) DeepCopy() *SecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TemplateSpec) DeepCopyInto(out *TemplateSpec) {
 	*out = *in
@@ -1068,3 +1728,105 @@ func (in *TemplateStatus) DeepCopy() *TemplateStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValueFrom) DeepCopyInto(out *ValueFrom) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueFrom.
+func (in *ValueFrom) DeepCopy() *ValueFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ValueFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebMetric) DeepCopyInto(out *WebMetric) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]WebMetricHeader, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebMetric.
+func (in *WebMetric) DeepCopy() *WebMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(WebMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebMetricHeader) DeepCopyInto(out *WebMetricHeader) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(WebMetricHeaderValueFrom)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebMetricHeader.
+func (in *WebMetricHeader) DeepCopy() *WebMetricHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(WebMetricHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebMetricHeaderValueFrom) DeepCopyInto(out *WebMetricHeaderValueFrom) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(SecretKeyRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebMetricHeaderValueFrom.
+func (in *WebMetricHeaderValueFrom) DeepCopy() *WebMetricHeaderValueFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(WebMetricHeaderValueFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadRef) DeepCopyInto(out *WorkloadRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadRef.
+func (in *WorkloadRef) DeepCopy() *WorkloadRef {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadRef)
+	in.DeepCopyInto(out)
+	return out
+}
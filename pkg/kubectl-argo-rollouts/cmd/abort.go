@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+const abortPatch = `{"status":{"abort":true}}`
+
+// NewCmdAbort returns a new instance of a `rollouts abort` command
+func NewCmdAbort(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "abort ROLLOUT_NAME",
+		Short: "Abort a rollout",
+		Long: "Abort a rollout and shift all the traffic back to the stable version. The rollout stays aborted until " +
+			"the pod template changes or the rollout is retried.",
+		Example:      "  kubectl argo rollouts abort guestbook",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return c.Usage()
+			}
+			for _, name := range args {
+				ro, err := patchRollout(o, name, types.MergePatchType, []byte(abortPatch))
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(o.Out, "rollout '%s' aborted\n", ro.Name)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestAbortCmd(t *testing.T) {
+	o, out := newTestOptions([]runtime.Object{newRollout("guestbook")}, nil)
+	cmd := NewCmdAbort(o)
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "rollout 'guestbook' aborted\n", out.String())
+
+	ro, err := o.RolloutsClient.ArgoprojV1alpha1().Rollouts(metav1.NamespaceDefault).Get("guestbook", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, ro.Status.Abort)
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+const example = `
+  # Get the state of a rollout
+  kubectl argo rollouts get rollout guestbook
+
+  # Promote a paused rollout to the next step
+  kubectl argo rollouts promote guestbook
+
+  # Update the image of a rollout
+  kubectl argo rollouts set image guestbook guestbook=argoproj/rollouts-demo:yellow
+
+  # Roll back a rollout to the previous revision
+  kubectl argo rollouts undo guestbook`
+
+// NewCmdArgoRollouts returns the root command of the kubectl-argo-rollouts plugin
+func NewCmdArgoRollouts(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "kubectl-argo-rollouts COMMAND",
+		Short:        "Manage argo rollouts",
+		Long:         "This command consists of multiple subcommands which can be used to manage Argo Rollouts.",
+		Example:      example,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+	}
+	o.AddKubectlFlags(cmd)
+	cmd.AddCommand(NewCmdGet(o))
+	cmd.AddCommand(NewCmdList(o))
+	cmd.AddCommand(NewCmdPromote(o))
+	cmd.AddCommand(NewCmdAbort(o))
+	cmd.AddCommand(NewCmdRetry(o))
+	cmd.AddCommand(NewCmdPause(o))
+	cmd.AddCommand(NewCmdResume(o))
+	cmd.AddCommand(NewCmdSet(o))
+	cmd.AddCommand(NewCmdUndo(o))
+	return cmd
+}
+
+// patchRollout applies the patch to the rollout in the current namespace
+func patchRollout(o *options.ArgoRolloutsOptions, name string, patchType types.PatchType, patch []byte) (*v1alpha1.Rollout, error) {
+	namespace, err := o.Namespace()
+	if err != nil {
+		return nil, err
+	}
+	client, err := o.RolloutsClientset()
+	if err != nil {
+		return nil, err
+	}
+	return client.ArgoprojV1alpha1().Rollouts(namespace).Patch(name, patchType, patch)
+}
@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"bytes"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned/fake"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+func newTestOptions(rolloutObjs []runtime.Object, kubeObjs []runtime.Object) (*options.ArgoRolloutsOptions, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	o := &options.ArgoRolloutsOptions{
+		RolloutsClient: fake.NewSimpleClientset(rolloutObjs...),
+		KubeClient:     k8sfake.NewSimpleClientset(kubeObjs...),
+		In:             &bytes.Buffer{},
+		Out:            out,
+		ErrOut:         &bytes.Buffer{},
+	}
+	return o, out
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/info"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+// NewCmdGet returns a new instance of a `rollouts get` command
+func NewCmdGet(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "get RESOURCE",
+		Short:        "Get details about rollouts",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+	}
+	cmd.AddCommand(NewCmdGetRollout(o))
+	return cmd
+}
+
+// NewCmdGetRollout returns a new instance of a `rollouts get rollout` command
+func NewCmdGetRollout(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "rollout ROLLOUT_NAME",
+		Aliases:      []string{"ro", "rollouts"},
+		Short:        "Get details about a rollout",
+		Long:         "Get details about a rollout and the ReplicaSets, Pods, Experiments and AnalysisRuns it owns.",
+		Example:      "  kubectl argo rollouts get rollout guestbook",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return c.Usage()
+			}
+			namespace, err := o.Namespace()
+			if err != nil {
+				return err
+			}
+			rolloutsClient, err := o.RolloutsClientset()
+			if err != nil {
+				return err
+			}
+			kubeClient, err := o.KubeClientset()
+			if err != nil {
+				return err
+			}
+			ro, err := rolloutsClient.ArgoprojV1alpha1().Rollouts(namespace).Get(args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			rsList, err := kubeClient.AppsV1().ReplicaSets(namespace).List(metav1.ListOptions{})
+			if err != nil {
+				return err
+			}
+			podList, err := kubeClient.CoreV1().Pods(namespace).List(metav1.ListOptions{})
+			if err != nil {
+				return err
+			}
+			exList, err := rolloutsClient.ArgoprojV1alpha1().Experiments(namespace).List(metav1.ListOptions{})
+			if err != nil {
+				return err
+			}
+			arList, err := rolloutsClient.ArgoprojV1alpha1().AnalysisRuns(namespace).List(metav1.ListOptions{})
+			if err != nil {
+				return err
+			}
+
+			var allReplicaSets []*appsv1.ReplicaSet
+			for i := range rsList.Items {
+				allReplicaSets = append(allReplicaSets, &rsList.Items[i])
+			}
+			var allPods []*corev1.Pod
+			for i := range podList.Items {
+				allPods = append(allPods, &podList.Items[i])
+			}
+			var allExperiments []*v1alpha1.Experiment
+			for i := range exList.Items {
+				allExperiments = append(allExperiments, &exList.Items[i])
+			}
+			var allAnalysisRuns []*v1alpha1.AnalysisRun
+			for i := range arList.Items {
+				allAnalysisRuns = append(allAnalysisRuns, &arList.Items[i])
+			}
+
+			printRolloutSummary(o.Out, ro)
+			fmt.Fprintln(o.Out)
+			tree := info.NewRolloutTree(ro, allReplicaSets, allPods, allExperiments, allAnalysisRuns)
+			printTree(o.Out, tree)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func printRolloutSummary(out io.Writer, ro *v1alpha1.Rollout) {
+	status, message := info.RolloutStatus(ro)
+	statusIcon := info.RolloutStatusIcon(status)
+	if message != "" {
+		status = fmt.Sprintf("%s (%s)", status, message)
+	}
+	desired := int32(1)
+	if ro.Spec.Replicas != nil {
+		desired = *ro.Spec.Replicas
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", ro.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", ro.Namespace)
+	fmt.Fprintf(w, "Status:\t%s %s\n", statusIcon, status)
+	fmt.Fprintf(w, "Strategy:\t%s\n", info.Strategy(ro))
+	if ro.Spec.Strategy.CanaryStrategy != nil {
+		fmt.Fprintf(w, "  Step:\t%s\n", info.CanaryStep(ro))
+		fmt.Fprintf(w, "  SetWeight:\t%s\n", info.SetWeight(ro))
+	}
+	fmt.Fprintf(w, "Images:\t%s\n", strings.Join(info.Images(ro), ", "))
+	fmt.Fprintf(w, "Replicas:\t\n")
+	fmt.Fprintf(w, "  Desired:\t%d\n", desired)
+	fmt.Fprintf(w, "  Current:\t%d\n", ro.Status.Replicas)
+	fmt.Fprintf(w, "  Updated:\t%d\n", ro.Status.UpdatedReplicas)
+	fmt.Fprintf(w, "  Ready:\t%d\n", ro.Status.ReadyReplicas)
+	fmt.Fprintf(w, "  Available:\t%d\n", ro.Status.AvailableReplicas)
+	_ = w.Flush()
+}
+
+func printTree(out io.Writer, root *info.Node) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "NAME\tKIND\tSTATUS\tAGE\tINFO\n")
+	printNode(w, root, "", "")
+	_ = w.Flush()
+}
+
+// printNode prints the node and its children, drawing the branches of the tree with box-drawing characters
+func printNode(w io.Writer, node *info.Node, prefix, childPrefix string) {
+	name := fmt.Sprintf("%s%s %s", prefix, node.Icon, node.Name)
+	status := node.Status
+	if node.StatusIcon != "" {
+		status = fmt.Sprintf("%s %s", node.StatusIcon, node.Status)
+	}
+	age := info.Age(node.CreationTimestamp)
+	if node.Kind == "Revision" {
+		status, age = "", ""
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, node.Kind, status, age, strings.Join(node.Info, ","))
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			printNode(w, child, childPrefix+"└──", childPrefix+"    ")
+		} else {
+			printNode(w, child, childPrefix+"├──", childPrefix+"│   ")
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+)
+
+func TestGetRolloutCmd(t *testing.T) {
+	ro := newCanaryRollout("guestbook", pointer.Int32Ptr(1))
+	ro.UID = "ro-uid"
+	ro.Spec.Paused = true
+	ro.Spec.Template.Spec.Containers = []corev1.Container{{Name: "guestbook", Image: "argoproj/rollouts-demo:blue"}}
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "guestbook-abc123",
+			Namespace:       metav1.NamespaceDefault,
+			UID:             "rs-uid",
+			Labels:          map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "abc123"},
+			Annotations:     map[string]string{annotations.RevisionAnnotation: "1"},
+			OwnerReferences: []metav1.OwnerReference{{UID: ro.UID}},
+		},
+		Spec: appsv1.ReplicaSetSpec{Replicas: pointer.Int32Ptr(1)},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "guestbook-abc123-xyz",
+			Namespace:       metav1.NamespaceDefault,
+			OwnerReferences: []metav1.OwnerReference{{UID: rs.UID}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	o, out := newTestOptions([]runtime.Object{ro}, []runtime.Object{rs, pod})
+	cmd := NewCmdGetRollout(o)
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	output := out.String()
+	assert.Regexp(t, `Name:\s+guestbook\n`, output)
+	assert.Regexp(t, `Status:\s+॥ Paused\n`, output)
+	assert.Regexp(t, `Step:\s+1/2\n`, output)
+	assert.Regexp(t, `Images:\s+argoproj/rollouts-demo:blue\n`, output)
+	assert.Contains(t, output, "NAME")
+	assert.Contains(t, output, "⟳ guestbook")
+	assert.Contains(t, output, "└──# revision:1")
+	assert.Contains(t, output, "    └──⧉ guestbook-abc123")
+	assert.Contains(t, output, "        └──□ guestbook-abc123-xyz")
+}
+
+func TestGetRolloutCmdNotFound(t *testing.T) {
+	o, _ := newTestOptions(nil, nil)
+	cmd := NewCmdGetRollout(o)
+	cmd.SetArgs([]string{"guestbook"})
+	cmd.SetOutput(o.ErrOut)
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
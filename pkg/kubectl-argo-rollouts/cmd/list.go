@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/info"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+// NewCmdList returns a new instance of a `rollouts list` command
+func NewCmdList(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list RESOURCE",
+		Short:        "List rollouts",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+	}
+	cmd.AddCommand(NewCmdListRollouts(o))
+	return cmd
+}
+
+// NewCmdListRollouts returns a new instance of a `rollouts list rollouts` command
+func NewCmdListRollouts(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var allNamespaces bool
+	cmd := &cobra.Command{
+		Use:          "rollouts",
+		Aliases:      []string{"ro", "rollout"},
+		Short:        "List rollouts",
+		Example:      "  kubectl argo rollouts list rollouts",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			namespace := metav1.NamespaceAll
+			if !allNamespaces {
+				var err error
+				namespace, err = o.Namespace()
+				if err != nil {
+					return err
+				}
+			}
+			client, err := o.RolloutsClientset()
+			if err != nil {
+				return err
+			}
+			roList, err := client.ArgoprojV1alpha1().Rollouts(namespace).List(metav1.ListOptions{})
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(o.Out, 0, 0, 2, ' ', 0)
+			if allNamespaces {
+				fmt.Fprintf(w, "NAMESPACE\t")
+			}
+			fmt.Fprintf(w, "NAME\tSTRATEGY\tSTATUS\tSTEP\tSET-WEIGHT\tREADY\tDESIRED\tUP-TO-DATE\tAVAILABLE\n")
+			for i := range roList.Items {
+				ro := &roList.Items[i]
+				if allNamespaces {
+					fmt.Fprintf(w, "%s\t", ro.Namespace)
+				}
+				status, _ := info.RolloutStatus(ro)
+				desired := int32(1)
+				if ro.Spec.Replicas != nil {
+					desired = *ro.Spec.Replicas
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d/%d\t%d\t%d\t%d\n", ro.Name, info.Strategy(ro), status,
+					info.CanaryStep(ro), info.SetWeight(ro), ro.Status.ReadyReplicas, ro.Status.Replicas, desired,
+					ro.Status.UpdatedReplicas, ro.Status.AvailableReplicas)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().BoolVar(&allNamespaces, "all-namespaces", false, "Include rollouts from all namespaces")
+	return cmd
+}
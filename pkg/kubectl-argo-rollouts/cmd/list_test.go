@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+)
+
+func TestListRolloutsCmd(t *testing.T) {
+	ro := newCanaryRollout("guestbook", pointer.Int32Ptr(1))
+	ro.Spec.Replicas = pointer.Int32Ptr(5)
+	ro.Spec.Paused = true
+	ro.Status.Replicas = 5
+	ro.Status.ReadyReplicas = 4
+	ro.Status.UpdatedReplicas = 1
+	ro.Status.AvailableReplicas = 4
+	o, out := newTestOptions([]runtime.Object{ro}, nil)
+	cmd := NewCmdListRollouts(o)
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	expected := `NAME       STRATEGY  STATUS  STEP  SET-WEIGHT  READY  DESIRED  UP-TO-DATE  AVAILABLE
+guestbook  Canary    Paused  1/2   20          4/5    5        1           4
+`
+	assert.Equal(t, expected, out.String())
+}
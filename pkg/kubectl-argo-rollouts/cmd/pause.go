@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+const pausePatch = `{"spec":{"paused":true}}`
+
+// NewCmdPause returns a new instance of a `rollouts pause` command
+func NewCmdPause(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "pause ROLLOUT_NAME",
+		Short:        "Pause a rollout",
+		Example:      "  kubectl argo rollouts pause guestbook",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return c.Usage()
+			}
+			for _, name := range args {
+				ro, err := patchRollout(o, name, types.MergePatchType, []byte(pausePatch))
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(o.Out, "rollout '%s' paused\n", ro.Name)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
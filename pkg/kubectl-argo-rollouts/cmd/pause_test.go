@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newRollout(name string) *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+}
+
+func TestPauseCmd(t *testing.T) {
+	o, out := newTestOptions([]runtime.Object{newRollout("guestbook")}, nil)
+	cmd := NewCmdPause(o)
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "rollout 'guestbook' paused\n", out.String())
+
+	ro, err := o.RolloutsClient.ArgoprojV1alpha1().Rollouts(metav1.NamespaceDefault).Get("guestbook", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, ro.Spec.Paused)
+}
+
+func TestPauseCmdNotFound(t *testing.T) {
+	o, _ := newTestOptions(nil, nil)
+	cmd := NewCmdPause(o)
+	cmd.SetArgs([]string{"guestbook"})
+	cmd.SetOutput(o.ErrOut)
+	err := cmd.Execute()
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+// skipStepPatch moves the rollout to the next step and releases the AnalysisRun and Experiment of the skipped step
+const skipStepPatch = `{"status":{"currentStepIndex":%d,"pauseStartTime":null,"canary":{"currentStepAnalysisRun":null,"currentExperiment":null}}}`
+
+// pauseConditionsPatch replaces the pause conditions of a rollout which stays paused after it is promoted
+const pauseConditionsPatch = `{"status":{"pauseConditions":%s}}`
+
+// NewCmdPromote returns a new instance of a `rollouts promote` command
+func NewCmdPromote(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "promote ROLLOUT_NAME",
+		Short: "Promote a rollout",
+		Long: "Promote a rollout. A paused rollout is resumed, otherwise the current step of a canary rollout is " +
+			"skipped. If a user also paused the rollout, only the pause conditions added by the controller are " +
+			"cleared and the rollout stays paused until it is resumed.",
+		Example:      "  kubectl argo rollouts promote guestbook",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return c.Usage()
+			}
+			namespace, err := o.Namespace()
+			if err != nil {
+				return err
+			}
+			client, err := o.RolloutsClientset()
+			if err != nil {
+				return err
+			}
+			rolloutIf := client.ArgoprojV1alpha1().Rollouts(namespace)
+			ro, err := rolloutIf.Get(args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			patch, err := getPromotePatch(ro)
+			if err != nil {
+				return err
+			}
+			ro, err = rolloutIf.Patch(ro.Name, types.MergePatchType, patch)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Out, "rollout '%s' promoted\n", ro.Name)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// getPromotePatch returns the patch which resumes a paused rollout or skips the current step of a canary rollout
+func getPromotePatch(ro *v1alpha1.Rollout) ([]byte, error) {
+	if ro.Spec.Paused {
+		var userPauseConditions []v1alpha1.PauseCondition
+		for _, cond := range ro.Status.PauseConditions {
+			if cond.Reason == v1alpha1.PauseReasonUser {
+				userPauseConditions = append(userPauseConditions, cond)
+			}
+		}
+		if len(userPauseConditions) == 0 || len(userPauseConditions) == len(ro.Status.PauseConditions) {
+			return []byte(unpausePatch), nil
+		}
+		pauseConditions, err := json.Marshal(userPauseConditions)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(fmt.Sprintf(pauseConditionsPatch, pauseConditions)), nil
+	}
+	canary := ro.Spec.Strategy.CanaryStrategy
+	if canary != nil && len(canary.Steps) > 0 {
+		index := int32(0)
+		if ro.Status.CurrentStepIndex != nil {
+			index = *ro.Status.CurrentStepIndex
+		}
+		if int(index) < len(canary.Steps) {
+			return []byte(fmt.Sprintf(skipStepPatch, index+1)), nil
+		}
+	}
+	return nil, fmt.Errorf("rollout '%s' is not paused and has no remaining steps to skip", ro.Name)
+}
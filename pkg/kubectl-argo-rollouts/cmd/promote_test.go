@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newCanaryRollout(name string, stepIndex *int32) *v1alpha1.Rollout {
+	ro := newRollout(name)
+	ro.Spec.Strategy.CanaryStrategy = &v1alpha1.CanaryStrategy{
+		Steps: []v1alpha1.CanaryStep{
+			{SetWeight: pointer.Int32Ptr(20)},
+			{Pause: &v1alpha1.RolloutPause{}},
+		},
+	}
+	ro.Status.CurrentStepIndex = stepIndex
+	return ro
+}
+
+func TestGetPromotePatch(t *testing.T) {
+	paused := newCanaryRollout("guestbook", pointer.Int32Ptr(1))
+	paused.Spec.Paused = true
+	patch, err := getPromotePatch(paused)
+	assert.NoError(t, err)
+	assert.Equal(t, unpausePatch, string(patch))
+
+	startTime := metav1.NewTime(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+	paused.Status.PauseConditions = []v1alpha1.PauseCondition{
+		{Reason: v1alpha1.PauseReasonCanaryPauseStep, StartTime: startTime},
+		{Reason: v1alpha1.PauseReasonUser, StartTime: startTime},
+	}
+	patch, err = getPromotePatch(paused)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"status":{"pauseConditions":[{"reason":"UserPause","startTime":"2020-03-01T00:00:00Z"}]}}`, string(patch))
+
+	paused.Status.PauseConditions = paused.Status.PauseConditions[1:]
+	patch, err = getPromotePatch(paused)
+	assert.NoError(t, err)
+	assert.Equal(t, unpausePatch, string(patch))
+
+	patch, err = getPromotePatch(newCanaryRollout("guestbook", pointer.Int32Ptr(0)))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"status":{"currentStepIndex":1,"pauseStartTime":null,"canary":{"currentStepAnalysisRun":null,"currentExperiment":null}}}`, string(patch))
+
+	patch, err = getPromotePatch(newCanaryRollout("guestbook", nil))
+	assert.NoError(t, err)
+	assert.Contains(t, string(patch), `"currentStepIndex":1`)
+
+	_, err = getPromotePatch(newCanaryRollout("guestbook", pointer.Int32Ptr(2)))
+	assert.EqualError(t, err, "rollout 'guestbook' is not paused and has no remaining steps to skip")
+
+	_, err = getPromotePatch(newRollout("guestbook"))
+	assert.Error(t, err)
+}
+
+func TestPromoteCmd(t *testing.T) {
+	o, out := newTestOptions([]runtime.Object{newCanaryRollout("guestbook", pointer.Int32Ptr(0))}, nil)
+	cmd := NewCmdPromote(o)
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "rollout 'guestbook' promoted\n", out.String())
+
+	ro, err := o.RolloutsClient.ArgoprojV1alpha1().Rollouts(metav1.NamespaceDefault).Get("guestbook", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), *ro.Status.CurrentStepIndex)
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+const unpausePatch = `{"spec":{"paused":false}}`
+
+// NewCmdResume returns a new instance of a `rollouts resume` command
+func NewCmdResume(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "resume ROLLOUT_NAME",
+		Short:        "Resume a paused rollout",
+		Example:      "  kubectl argo rollouts resume guestbook",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return c.Usage()
+			}
+			for _, name := range args {
+				ro, err := patchRollout(o, name, types.MergePatchType, []byte(unpausePatch))
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(o.Out, "rollout '%s' resumed\n", ro.Name)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+const retryPatch = `{"status":{"abort":false}}`
+
+// NewCmdRetry returns a new instance of a `rollouts retry` command
+func NewCmdRetry(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "retry ROLLOUT_NAME",
+		Short:        "Retry an aborted rollout",
+		Long:         "Retry an aborted rollout. The rollout restarts the update from the first step.",
+		Example:      "  kubectl argo rollouts retry guestbook",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return c.Usage()
+			}
+			for _, name := range args {
+				ro, err := patchRollout(o, name, types.MergePatchType, []byte(retryPatch))
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(o.Out, "rollout '%s' retried\n", ro.Name)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
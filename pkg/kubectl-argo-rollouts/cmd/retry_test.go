@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestRetryCmd(t *testing.T) {
+	ro := newRollout("guestbook")
+	ro.Status.Abort = true
+	o, out := newTestOptions([]runtime.Object{ro}, nil)
+	cmd := NewCmdRetry(o)
+	cmd.SetArgs([]string{"guestbook"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "rollout 'guestbook' retried\n", out.String())
+
+	ro, err = o.RolloutsClient.ArgoprojV1alpha1().Rollouts(metav1.NamespaceDefault).Get("guestbook", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, ro.Status.Abort)
+}
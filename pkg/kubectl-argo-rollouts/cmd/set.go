@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+// NewCmdSet returns a new instance of a `rollouts set` command
+func NewCmdSet(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "set COMMAND",
+		Short:        "Update various values on resources",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return c.Help()
+		},
+	}
+	cmd.AddCommand(NewCmdSetImage(o))
+	return cmd
+}
+
+// NewCmdSetImage returns a new instance of a `rollouts set image` command
+func NewCmdSetImage(o *options.ArgoRolloutsOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "image ROLLOUT_NAME CONTAINER=IMAGE",
+		Short: "Update the image of a rollout",
+		Long:  "Update the image of a container of the rollout. Use '*' as the container name to update all containers.",
+		Example: `
+  # Set the image of the guestbook container
+  kubectl argo rollouts set image guestbook guestbook=argoproj/rollouts-demo:yellow
+
+  # Set the image of all containers
+  kubectl argo rollouts set image guestbook *=argoproj/rollouts-demo:yellow`,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return c.Usage()
+			}
+			parts := strings.SplitN(args[1], "=", 2)
+			if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+				return fmt.Errorf("invalid image update '%s': expected CONTAINER=IMAGE", args[1])
+			}
+			namespace, err := o.Namespace()
+			if err != nil {
+				return err
+			}
+			client, err := o.RolloutsClientset()
+			if err != nil {
+				return err
+			}
+			rolloutIf := client.ArgoprojV1alpha1().Rollouts(namespace)
+			ro, err := rolloutIf.Get(args[0], metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			patch, err := getSetImagePatch(ro, parts[0], parts[1])
+			if err != nil {
+				return err
+			}
+			ro, err = rolloutIf.Patch(ro.Name, types.JSONPatchType, patch)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(o.Out, "rollout '%s' image updated\n", ro.Name)
+			return nil
+		},
+	}
+	return cmd
+}
+
+type jsonPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value string `json:"value"`
+}
+
+// getSetImagePatch returns a JSON patch replacing the image of the named container (or all containers if the name
+// is '*') in the pod template of the rollout
+func getSetImagePatch(ro *v1alpha1.Rollout, container, image string) ([]byte, error) {
+	var ops []jsonPatchOperation
+	addOps := func(field string, containers []corev1.Container) {
+		for i, c := range containers {
+			if container == "*" || c.Name == container {
+				ops = append(ops, jsonPatchOperation{
+					Op:    "replace",
+					Path:  fmt.Sprintf("/spec/template/spec/%s/%d/image", field, i),
+					Value: image,
+				})
+			}
+		}
+	}
+	addOps("initContainers", ro.Spec.Template.Spec.InitContainers)
+	addOps("containers", ro.Spec.Template.Spec.Containers)
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("unable to find container named '%s'", container)
+	}
+	return json.Marshal(ops)
+}
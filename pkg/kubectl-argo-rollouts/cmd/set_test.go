@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func newRolloutWithContainers(name string) *v1alpha1.Rollout {
+	ro := newRollout(name)
+	ro.Spec.Template.Spec.InitContainers = []corev1.Container{{Name: "init", Image: "busybox"}}
+	ro.Spec.Template.Spec.Containers = []corev1.Container{
+		{Name: "guestbook", Image: "argoproj/rollouts-demo:blue"},
+		{Name: "sidecar", Image: "envoy"},
+	}
+	return ro
+}
+
+func TestGetSetImagePatch(t *testing.T) {
+	ro := newRolloutWithContainers("guestbook")
+	patch, err := getSetImagePatch(ro, "sidecar", "envoy:v2")
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"op":"replace","path":"/spec/template/spec/containers/1/image","value":"envoy:v2"}]`, string(patch))
+
+	patch, err = getSetImagePatch(ro, "init", "busybox:latest")
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"op":"replace","path":"/spec/template/spec/initContainers/0/image","value":"busybox:latest"}]`, string(patch))
+
+	_, err = getSetImagePatch(ro, "missing", "busybox")
+	assert.EqualError(t, err, "unable to find container named 'missing'")
+}
+
+func TestSetImageCmd(t *testing.T) {
+	o, out := newTestOptions([]runtime.Object{newRolloutWithContainers("guestbook")}, nil)
+	cmd := NewCmdSetImage(o)
+	cmd.SetArgs([]string{"guestbook", "*=argoproj/rollouts-demo:yellow"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "rollout 'guestbook' image updated\n", out.String())
+
+	ro, err := o.RolloutsClient.ArgoprojV1alpha1().Rollouts(metav1.NamespaceDefault).Get("guestbook", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "argoproj/rollouts-demo:yellow", ro.Spec.Template.Spec.InitContainers[0].Image)
+	assert.Equal(t, "argoproj/rollouts-demo:yellow", ro.Spec.Template.Spec.Containers[0].Image)
+	assert.Equal(t, "argoproj/rollouts-demo:yellow", ro.Spec.Template.Spec.Containers[1].Image)
+}
+
+func TestSetImageCmdInvalidArg(t *testing.T) {
+	o, _ := newTestOptions([]runtime.Object{newRolloutWithContainers("guestbook")}, nil)
+	cmd := NewCmdSetImage(o)
+	cmd.SetArgs([]string{"guestbook", "argoproj/rollouts-demo:yellow"})
+	cmd.SetOutput(o.ErrOut)
+	err := cmd.Execute()
+	assert.EqualError(t, err, "invalid image update 'argoproj/rollouts-demo:yellow': expected CONTAINER=IMAGE")
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/pkg/kubectl-argo-rollouts/options"
+)
+
+const undoExample = `
+  # Roll back to the previous revision
+  kubectl argo rollouts undo guestbook
+
+  # Roll back to revision 3 without running the canary steps
+  kubectl argo rollouts undo guestbook --to-revision=3 --skip-steps`
+
+// NewCmdUndo returns a new instance of a `rollouts undo` command
+func NewCmdUndo(o *options.ArgoRolloutsOptions) *cobra.Command {
+	var rollbackTo v1alpha1.RollbackConfig
+	cmd := &cobra.Command{
+		Use:   "undo ROLLOUT_NAME",
+		Short: "Roll back a rollout to a previous revision",
+		Long: "Roll back a rollout to the pod template of a previous revision. The rollout is rolled back to the " +
+			"previous revision unless a revision is given.",
+		Example:      undoExample,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return c.Usage()
+			}
+			patch, err := getUndoPatch(rollbackTo)
+			if err != nil {
+				return err
+			}
+			for _, name := range args {
+				ro, err := patchRollout(o, name, types.MergePatchType, patch)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(o.Out, "rollout '%s' rolled back\n", ro.Name)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Int64Var(&rollbackTo.Revision, "to-revision", 0, "The revision to roll back to. Defaults to the previous revision")
+	cmd.Flags().BoolVar(&rollbackTo.SkipSteps, "skip-steps", false, "Skip the canary steps and scale the revision up right away")
+	return cmd
+}
+
+// getUndoPatch returns the merge patch setting spec.rollbackTo of a rollout
+func getUndoPatch(rollbackTo v1alpha1.RollbackConfig) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"rollbackTo": rollbackTo,
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func TestGetUndoPatch(t *testing.T) {
+	patch, err := getUndoPatch(v1alpha1.RollbackConfig{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"spec":{"rollbackTo":{}}}`, string(patch))
+
+	patch, err = getUndoPatch(v1alpha1.RollbackConfig{Revision: 3, SkipSteps: true})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"spec":{"rollbackTo":{"revision":3,"skipSteps":true}}}`, string(patch))
+}
+
+func TestUndoCmd(t *testing.T) {
+	o, out := newTestOptions([]runtime.Object{newRollout("guestbook")}, nil)
+	cmd := NewCmdUndo(o)
+	cmd.SetArgs([]string{"guestbook", "--to-revision=2"})
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, "rollout 'guestbook' rolled back\n", out.String())
+
+	ro, err := o.RolloutsClient.ArgoprojV1alpha1().Rollouts(metav1.NamespaceDefault).Get("guestbook", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, &v1alpha1.RollbackConfig{Revision: 2}, ro.Spec.RollbackTo)
+}
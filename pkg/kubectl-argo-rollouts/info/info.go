@@ -0,0 +1,393 @@
+package info
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+// Icons used by the CLI to display the statuses and kinds of the objects
+const (
+	IconWaiting     = "◷"
+	IconProgressing = "◌"
+	IconWarning     = "⚠"
+	IconUnknown     = "?"
+	IconOK          = "✔"
+	IconBad         = "✖"
+	IconPaused      = "॥"
+	IconNeutral     = "•"
+
+	IconRollout     = "⟳"
+	IconRevision    = "#"
+	IconReplicaSet  = "⧉"
+	IconPod         = "□"
+	IconExperiment  = "Σ"
+	IconAnalysisRun = "α"
+)
+
+// Statuses of a rollout as reported by the CLI
+const (
+	RolloutStatusHealthy     = "Healthy"
+	RolloutStatusProgressing = "Progressing"
+	RolloutStatusPaused      = "Paused"
+	RolloutStatusDegraded    = "Degraded"
+)
+
+// Node is a resource in the tree of objects owned by a rollout
+type Node struct {
+	Kind              string
+	Name              string
+	Icon              string
+	Status            string
+	StatusIcon        string
+	Info              []string
+	CreationTimestamp metav1.Time
+	Children          []*Node
+}
+
+// RolloutStatus returns the status of the rollout (Healthy, Progressing, Paused or Degraded) and a message explaining
+// why the rollout is degraded or paused
+func RolloutStatus(ro *v1alpha1.Rollout) (string, string) {
+	if ro.Status.Abort {
+		return RolloutStatusDegraded, conditions.RolloutAbortedMessage
+	}
+	if cond := conditions.GetRolloutCondition(ro.Status, v1alpha1.InvalidSpec); cond != nil {
+		return RolloutStatusDegraded, cond.Message
+	}
+	if cond := conditions.GetRolloutCondition(ro.Status, v1alpha1.RolloutProgressing); cond != nil && cond.Status == corev1.ConditionFalse {
+		return RolloutStatusDegraded, cond.Message
+	}
+	if ro.Spec.Paused {
+		var reasons []string
+		for _, cond := range ro.Status.PauseConditions {
+			reasons = append(reasons, string(cond.Reason))
+		}
+		return RolloutStatusPaused, strings.Join(reasons, ", ")
+	}
+	if conditions.RolloutComplete(ro, &ro.Status) {
+		return RolloutStatusHealthy, ""
+	}
+	return RolloutStatusProgressing, ""
+}
+
+// RolloutStatusIcon returns the icon for a rollout status
+func RolloutStatusIcon(status string) string {
+	switch status {
+	case RolloutStatusHealthy:
+		return IconOK
+	case RolloutStatusProgressing:
+		return IconProgressing
+	case RolloutStatusPaused:
+		return IconPaused
+	case RolloutStatusDegraded:
+		return IconBad
+	}
+	return IconUnknown
+}
+
+// Strategy returns the name of the rollout strategy
+func Strategy(ro *v1alpha1.Rollout) string {
+	if ro.Spec.Strategy.BlueGreenStrategy != nil {
+		return "BlueGreen"
+	}
+	if ro.Spec.Strategy.CanaryStrategy != nil {
+		return "Canary"
+	}
+	return "Unknown"
+}
+
+// CanaryStep returns the current step of a canary rollout formatted as "<index>/<count>"
+func CanaryStep(ro *v1alpha1.Rollout) string {
+	if ro.Spec.Strategy.CanaryStrategy == nil || len(ro.Spec.Strategy.CanaryStrategy.Steps) == 0 {
+		return "-"
+	}
+	stepCount := len(ro.Spec.Strategy.CanaryStrategy.Steps)
+	index := stepCount
+	if ro.Status.CurrentStepIndex != nil {
+		index = int(*ro.Status.CurrentStepIndex)
+	}
+	return fmt.Sprintf("%d/%d", index, stepCount)
+}
+
+// SetWeight returns the setWeight of the current step of a canary rollout
+func SetWeight(ro *v1alpha1.Rollout) string {
+	if ro.Spec.Strategy.CanaryStrategy == nil {
+		return "-"
+	}
+	return strconv.Itoa(int(replicasetutil.GetCurrentSetWeight(ro)))
+}
+
+// Images returns the images of the containers in the rollout pod template
+func Images(ro *v1alpha1.Rollout) []string {
+	var images []string
+	for _, c := range ro.Spec.Template.Spec.Containers {
+		images = append(images, c.Image)
+	}
+	return images
+}
+
+// Age returns the time since the timestamp in the format used by kubectl (e.g. 30s, 5m, 3h, 2d)
+func Age(t metav1.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := time.Since(t.Time)
+	switch {
+	case d < 2*time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < 2*time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// NewRolloutTree builds the tree of the rollout, its ReplicaSets grouped by revision, their pods, and the Experiments
+// and AnalysisRuns owned by the rollout. Objects which are not owned by the rollout are ignored.
+func NewRolloutTree(ro *v1alpha1.Rollout, allReplicaSets []*appsv1.ReplicaSet, allPods []*corev1.Pod, allExperiments []*v1alpha1.Experiment, allAnalysisRuns []*v1alpha1.AnalysisRun) *Node {
+	status, _ := RolloutStatus(ro)
+	root := &Node{
+		Kind:              "Rollout",
+		Name:              ro.Name,
+		Icon:              IconRollout,
+		Status:            status,
+		StatusIcon:        RolloutStatusIcon(status),
+		CreationTimestamp: ro.CreationTimestamp,
+	}
+
+	revisions := map[int]*Node{}
+	revisionByPodHash := map[string]int{}
+	for _, rs := range allReplicaSets {
+		if !isOwnedBy(rs.OwnerReferences, ro.UID) {
+			continue
+		}
+		revision, _ := strconv.Atoi(rs.Annotations[annotations.RevisionAnnotation])
+		revisionByPodHash[replicasetutil.GetPodTemplateHash(rs)] = revision
+		revisionNode, ok := revisions[revision]
+		if !ok {
+			revisionNode = &Node{
+				Kind: "Revision",
+				Name: fmt.Sprintf("revision:%d", revision),
+				Icon: IconRevision,
+			}
+			revisions[revision] = revisionNode
+		}
+		rsNode := newReplicaSetNode(rs, replicaSetInfo(ro, rs), allPods)
+		revisionNode.Children = append(revisionNode.Children, rsNode)
+	}
+
+	for _, ar := range allAnalysisRuns {
+		if !isOwnedBy(ar.OwnerReferences, ro.UID) {
+			continue
+		}
+		arNode := newAnalysisRunNode(ar)
+		if revision, ok := revisionByPodHash[ar.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]]; ok {
+			revisions[revision].Children = append(revisions[revision].Children, arNode)
+		} else {
+			root.Children = append(root.Children, arNode)
+		}
+	}
+
+	for _, ex := range allExperiments {
+		if !isOwnedBy(ex.OwnerReferences, ro.UID) {
+			continue
+		}
+		root.Children = append(root.Children, newExperimentNode(ex, allReplicaSets, allPods, allAnalysisRuns))
+	}
+	sortNodes(root.Children)
+
+	var revisionNumbers []int
+	for revision := range revisions {
+		revisionNumbers = append(revisionNumbers, revision)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(revisionNumbers)))
+	for _, revision := range revisionNumbers {
+		sortNodes(revisions[revision].Children)
+		root.Children = append(root.Children, revisions[revision])
+	}
+	return root
+}
+
+func newExperimentNode(ex *v1alpha1.Experiment, allReplicaSets []*appsv1.ReplicaSet, allPods []*corev1.Pod, allAnalysisRuns []*v1alpha1.AnalysisRun) *Node {
+	exNode := &Node{
+		Kind:              "Experiment",
+		Name:              ex.Name,
+		Icon:              IconExperiment,
+		CreationTimestamp: ex.CreationTimestamp,
+	}
+	switch {
+	case ex.Status.Running == nil:
+		exNode.Status, exNode.StatusIcon = "Pending", IconWaiting
+	case *ex.Status.Running:
+		exNode.Status, exNode.StatusIcon = "Running", IconProgressing
+	default:
+		exNode.Status, exNode.StatusIcon = "Completed", IconOK
+	}
+	for _, rs := range allReplicaSets {
+		if isOwnedBy(rs.OwnerReferences, ex.UID) {
+			exNode.Children = append(exNode.Children, newReplicaSetNode(rs, nil, allPods))
+		}
+	}
+	for _, ar := range allAnalysisRuns {
+		if isOwnedBy(ar.OwnerReferences, ex.UID) {
+			exNode.Children = append(exNode.Children, newAnalysisRunNode(ar))
+		}
+	}
+	sortNodes(exNode.Children)
+	return exNode
+}
+
+func newReplicaSetNode(rs *appsv1.ReplicaSet, info []string, allPods []*corev1.Pod) *Node {
+	rsNode := &Node{
+		Kind:              "ReplicaSet",
+		Name:              rs.Name,
+		Icon:              IconReplicaSet,
+		Info:              info,
+		CreationTimestamp: rs.CreationTimestamp,
+	}
+	desired := int32(1)
+	if rs.Spec.Replicas != nil {
+		desired = *rs.Spec.Replicas
+	}
+	switch {
+	case desired == 0:
+		rsNode.Status, rsNode.StatusIcon = "ScaledDown", IconNeutral
+	case rs.Status.AvailableReplicas >= desired:
+		rsNode.Status, rsNode.StatusIcon = "Healthy", IconOK
+	default:
+		rsNode.Status, rsNode.StatusIcon = "Progressing", IconProgressing
+	}
+	for _, pod := range allPods {
+		if isOwnedBy(pod.OwnerReferences, rs.UID) {
+			rsNode.Children = append(rsNode.Children, newPodNode(pod))
+		}
+	}
+	sortNodes(rsNode.Children)
+	return rsNode
+}
+
+func replicaSetInfo(ro *v1alpha1.Rollout, rs *appsv1.ReplicaSet) []string {
+	podHash := replicasetutil.GetPodTemplateHash(rs)
+	var info []string
+	if ro.Spec.Strategy.CanaryStrategy != nil {
+		if podHash == ro.Status.Canary.StableRS {
+			info = append(info, "stable")
+		} else if podHash == ro.Status.CurrentPodHash {
+			info = append(info, "canary")
+		}
+	}
+	if ro.Spec.Strategy.BlueGreenStrategy != nil {
+		if podHash == ro.Status.BlueGreen.ActiveSelector {
+			info = append(info, "active")
+		}
+		if podHash == ro.Status.BlueGreen.PreviewSelector {
+			info = append(info, "preview")
+		}
+	}
+	return info
+}
+
+func newPodNode(pod *corev1.Pod) *Node {
+	podNode := &Node{
+		Kind:              "Pod",
+		Name:              pod.Name,
+		Icon:              IconPod,
+		Status:            string(pod.Status.Phase),
+		CreationTimestamp: pod.CreationTimestamp,
+	}
+	ready := 0
+	restarts := 0
+	var waitingReason string
+	for _, c := range pod.Status.ContainerStatuses {
+		if c.Ready {
+			ready++
+		}
+		restarts += int(c.RestartCount)
+		if c.State.Waiting != nil && c.State.Waiting.Reason != "" {
+			waitingReason = c.State.Waiting.Reason
+		}
+	}
+	podNode.Info = append(podNode.Info, fmt.Sprintf("ready:%d/%d", ready, len(pod.Spec.Containers)))
+	if restarts > 0 {
+		podNode.Info = append(podNode.Info, fmt.Sprintf("restarts:%d", restarts))
+	}
+
+	switch {
+	case pod.DeletionTimestamp != nil:
+		podNode.Status, podNode.StatusIcon = "Terminating", IconNeutral
+	case waitingReason != "" && waitingReason != "ContainerCreating":
+		podNode.Status, podNode.StatusIcon = waitingReason, IconBad
+	case pod.Status.Phase == corev1.PodRunning && ready == len(pod.Spec.Containers):
+		podNode.StatusIcon = IconOK
+	case pod.Status.Phase == corev1.PodRunning, pod.Status.Phase == corev1.PodPending:
+		podNode.StatusIcon = IconProgressing
+	case pod.Status.Phase == corev1.PodSucceeded:
+		podNode.StatusIcon = IconOK
+	case pod.Status.Phase == corev1.PodFailed:
+		podNode.StatusIcon = IconBad
+	default:
+		podNode.StatusIcon = IconUnknown
+	}
+	return podNode
+}
+
+func newAnalysisRunNode(ar *v1alpha1.AnalysisRun) *Node {
+	arNode := &Node{
+		Kind:              "AnalysisRun",
+		Name:              ar.Name,
+		Icon:              IconAnalysisRun,
+		Status:            string(v1alpha1.AnalysisStatusPending),
+		StatusIcon:        IconWaiting,
+		CreationTimestamp: ar.CreationTimestamp,
+	}
+	if ar.Status == nil || ar.Status.Status == "" {
+		return arNode
+	}
+	arNode.Status = string(ar.Status.Status)
+	switch ar.Status.Status {
+	case v1alpha1.AnalysisStatusSuccessful:
+		arNode.StatusIcon = IconOK
+	case v1alpha1.AnalysisStatusFailed, v1alpha1.AnalysisStatusError:
+		arNode.StatusIcon = IconBad
+	case v1alpha1.AnalysisStatusInconclusive:
+		arNode.StatusIcon = IconWarning
+	case v1alpha1.AnalysisStatusRunning:
+		arNode.StatusIcon = IconProgressing
+	}
+	if ar.Spec.Terminate {
+		arNode.Info = append(arNode.Info, "terminated")
+	}
+	return arNode
+}
+
+func isOwnedBy(ownerRefs []metav1.OwnerReference, uid types.UID) bool {
+	for _, ref := range ownerRefs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+// sortNodes sorts the nodes from the newest to the oldest, using the name as a tie breaker
+func sortNodes(nodes []*Node) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].CreationTimestamp.Equal(&nodes[j].CreationTimestamp) {
+			return nodes[i].Name < nodes[j].Name
+		}
+		return nodes[j].CreationTimestamp.Before(&nodes[i].CreationTimestamp)
+	})
+}
@@ -0,0 +1,157 @@
+package info
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+)
+
+func newCanaryRollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "guestbook",
+			UID:  "ro-uid",
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				CanaryStrategy: &v1alpha1.CanaryStrategy{
+					Steps: []v1alpha1.CanaryStep{
+						{SetWeight: pointer.Int32Ptr(20)},
+						{Pause: &v1alpha1.RolloutPause{}},
+					},
+				},
+			},
+		},
+		Status: v1alpha1.RolloutStatus{
+			CurrentStepIndex: pointer.Int32Ptr(1),
+			CurrentPodHash:   "new",
+			Canary: v1alpha1.CanaryStatus{
+				StableRS: "old",
+			},
+		},
+	}
+}
+
+func newReplicaSet(name, podHash, revision string, ownerUID types.UID, replicas, available int32, created time.Time) *appsv1.ReplicaSet {
+	return &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			UID:               types.UID(name + "-uid"),
+			CreationTimestamp: metav1.NewTime(created),
+			Labels:            map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: podHash},
+			Annotations:       map[string]string{annotations.RevisionAnnotation: revision},
+			OwnerReferences:   []metav1.OwnerReference{{UID: ownerUID}},
+		},
+		Spec:   appsv1.ReplicaSetSpec{Replicas: pointer.Int32Ptr(replicas)},
+		Status: appsv1.ReplicaSetStatus{AvailableReplicas: available},
+	}
+}
+
+func TestRolloutStatus(t *testing.T) {
+	ro := newCanaryRollout()
+	status, message := RolloutStatus(ro)
+	assert.Equal(t, RolloutStatusProgressing, status)
+	assert.Equal(t, "", message)
+
+	ro.Spec.Paused = true
+	status, _ = RolloutStatus(ro)
+	assert.Equal(t, RolloutStatusPaused, status)
+
+	ro.Status.PauseConditions = []v1alpha1.PauseCondition{
+		{Reason: v1alpha1.PauseReasonCanaryPauseStep},
+		{Reason: v1alpha1.PauseReasonUser},
+	}
+	status, message = RolloutStatus(ro)
+	assert.Equal(t, RolloutStatusPaused, status)
+	assert.Equal(t, "CanaryPauseStep, UserPause", message)
+
+	ro.Status.Abort = true
+	status, message = RolloutStatus(ro)
+	assert.Equal(t, RolloutStatusDegraded, status)
+	assert.Equal(t, conditions.RolloutAbortedMessage, message)
+	assert.Equal(t, IconBad, RolloutStatusIcon(status))
+}
+
+func TestCanaryStepAndSetWeight(t *testing.T) {
+	ro := newCanaryRollout()
+	assert.Equal(t, "Canary", Strategy(ro))
+	assert.Equal(t, "1/2", CanaryStep(ro))
+	assert.Equal(t, "20", SetWeight(ro))
+
+	ro.Status.CurrentStepIndex = nil
+	assert.Equal(t, "2/2", CanaryStep(ro))
+
+	ro.Spec.Strategy.CanaryStrategy = nil
+	ro.Spec.Strategy.BlueGreenStrategy = &v1alpha1.BlueGreenStrategy{}
+	assert.Equal(t, "BlueGreen", Strategy(ro))
+	assert.Equal(t, "-", CanaryStep(ro))
+	assert.Equal(t, "-", SetWeight(ro))
+}
+
+func TestAge(t *testing.T) {
+	assert.Equal(t, "-", Age(metav1.Time{}))
+	assert.Equal(t, "5m", Age(metav1.NewTime(time.Now().Add(-5*time.Minute))))
+	assert.Equal(t, "3d", Age(metav1.NewTime(time.Now().Add(-72*time.Hour))))
+}
+
+func TestNewRolloutTree(t *testing.T) {
+	now := time.Now()
+	ro := newCanaryRollout()
+	oldRS := newReplicaSet("guestbook-old", "old", "1", "ro-uid", 4, 4, now.Add(-time.Hour))
+	newRS := newReplicaSet("guestbook-new", "new", "2", "ro-uid", 1, 0, now)
+	otherRS := newReplicaSet("other", "other", "1", "other-uid", 1, 1, now)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "guestbook-new-abc",
+			OwnerReferences: []metav1.OwnerReference{{UID: newRS.UID}},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "guestbook"}}},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{Ready: true, RestartCount: 2}},
+		},
+	}
+	ar := &v1alpha1.AnalysisRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "guestbook-new-analysis",
+			Labels:          map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "new"},
+			OwnerReferences: []metav1.OwnerReference{{UID: ro.UID}},
+		},
+		Status: &v1alpha1.AnalysisRunStatus{Status: v1alpha1.AnalysisStatusFailed},
+	}
+
+	tree := NewRolloutTree(ro, []*appsv1.ReplicaSet{oldRS, newRS, otherRS}, []*corev1.Pod{pod}, nil, []*v1alpha1.AnalysisRun{ar})
+	assert.Equal(t, "guestbook", tree.Name)
+	assert.Equal(t, RolloutStatusProgressing, tree.Status)
+	assert.Len(t, tree.Children, 2)
+
+	revision2 := tree.Children[0]
+	assert.Equal(t, "revision:2", revision2.Name)
+	assert.Len(t, revision2.Children, 2)
+	rsNode := revision2.Children[0]
+	assert.Equal(t, "guestbook-new", rsNode.Name)
+	assert.Equal(t, []string{"canary"}, rsNode.Info)
+	assert.Equal(t, "Progressing", rsNode.Status)
+	assert.Len(t, rsNode.Children, 1)
+	assert.Equal(t, IconOK, rsNode.Children[0].StatusIcon)
+	assert.Equal(t, []string{"ready:1/1", "restarts:2"}, rsNode.Children[0].Info)
+	arNode := revision2.Children[1]
+	assert.Equal(t, "guestbook-new-analysis", arNode.Name)
+	assert.Equal(t, IconBad, arNode.StatusIcon)
+
+	revision1 := tree.Children[1]
+	assert.Equal(t, "revision:1", revision1.Name)
+	assert.Len(t, revision1.Children, 1)
+	assert.Equal(t, []string{"stable"}, revision1.Children[0].Info)
+	assert.Equal(t, "Healthy", revision1.Children[0].Status)
+}
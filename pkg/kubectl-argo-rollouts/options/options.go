@@ -0,0 +1,87 @@
+package options
+
+import (
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	clientset "github.com/argoproj/argo-rollouts/pkg/client/clientset/versioned"
+)
+
+// ArgoRolloutsOptions holds the clients and streams shared by all the kubectl-argo-rollouts commands
+type ArgoRolloutsOptions struct {
+	ClientConfig   clientcmd.ClientConfig
+	RolloutsClient clientset.Interface
+	KubeClient     kubernetes.Interface
+
+	In     io.Reader
+	Out    io.Writer
+	ErrOut io.Writer
+}
+
+// NewArgoRolloutsOptions returns options which read from stdin and write to stdout and stderr
+func NewArgoRolloutsOptions() *ArgoRolloutsOptions {
+	return &ArgoRolloutsOptions{
+		In:     os.Stdin,
+		Out:    os.Stdout,
+		ErrOut: os.Stderr,
+	}
+}
+
+// AddKubectlFlags adds the standard kubectl flags (e.g. --kubeconfig, --namespace, --context) to the command
+func (o *ArgoRolloutsOptions) AddKubectlFlags(cmd *cobra.Command) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.DefaultClientConfig = &clientcmd.DefaultClientConfig
+	overrides := clientcmd.ConfigOverrides{}
+	kflags := clientcmd.RecommendedConfigOverrideFlags("")
+	cmd.PersistentFlags().StringVar(&loadingRules.ExplicitPath, "kubeconfig", "", "Path to a kube config. Only required if out-of-cluster")
+	clientcmd.BindOverrideFlags(&overrides, cmd.PersistentFlags(), kflags)
+	o.ClientConfig = clientcmd.NewInteractiveDeferredLoadingClientConfig(loadingRules, &overrides, o.In)
+}
+
+// Namespace returns the namespace from the --namespace flag or the current kubectl context
+func (o *ArgoRolloutsOptions) Namespace() (string, error) {
+	if o.ClientConfig == nil {
+		return metav1.NamespaceDefault, nil
+	}
+	namespace, _, err := o.ClientConfig.Namespace()
+	return namespace, err
+}
+
+// RolloutsClientset returns a clientset for the argoproj.io resources
+func (o *ArgoRolloutsOptions) RolloutsClientset() (clientset.Interface, error) {
+	if o.RolloutsClient != nil {
+		return o.RolloutsClient, nil
+	}
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := clientset.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	o.RolloutsClient = client
+	return client, nil
+}
+
+// KubeClientset returns a clientset for the core Kubernetes resources
+func (o *ArgoRolloutsOptions) KubeClientset() (kubernetes.Interface, error) {
+	if o.KubeClient != nil {
+		return o.KubeClient, nil
+	}
+	config, err := o.ClientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	o.KubeClient = client
+	return client, nil
+}
@@ -20,7 +20,7 @@ import (
 const (
 	cancelAnalysisRun = `{
 		"spec": {
-			"terminated": true
+			"terminate": true
 		}
 	}`
 )
@@ -43,6 +43,10 @@ func (c *RolloutController) getAnalysisRunsForRollout(rollout *v1alpha1.Rollout)
 }
 
 func (c *RolloutController) reconcileAnalysisRuns(rollout *v1alpha1.Rollout, currentArs, otherArs []*v1alpha1.AnalysisRun, stableRS, newRS *appsv1.ReplicaSet) ([]*v1alpha1.AnalysisRun, error) {
+	if rollout.Status.Abort {
+		allArs := append(currentArs, otherArs...)
+		return nil, c.cancelAnalysisRuns(rollout, allArs)
+	}
 	if rollout.Spec.Paused {
 		return currentArs, nil
 	}
@@ -56,7 +60,13 @@ func (c *RolloutController) reconcileAnalysisRuns(rollout *v1alpha1.Rollout, cur
 		newCurrentAnalysisRuns = append(newCurrentAnalysisRuns, stepAnalysisRun)
 	}
 
-	//TODO(dthomson) implement reconcileBackgroundBasedAnalysisRun
+	backgroundAnalysisRun, err := c.reconcileBackgroundAnalysisRun(rollout, currentArs, stableRS, newRS)
+	if err != nil {
+		return currentArs, err
+	}
+	if backgroundAnalysisRun != nil {
+		newCurrentAnalysisRuns = append(newCurrentAnalysisRuns, backgroundAnalysisRun)
+	}
 
 	err = c.cancelAnalysisRuns(rollout, otherArs)
 	if err != nil {
@@ -66,6 +76,64 @@ func (c *RolloutController) reconcileAnalysisRuns(rollout *v1alpha1.Rollout, cur
 	return newCurrentAnalysisRuns, nil
 }
 
+// reconcileBackgroundAnalysisRun creates the background AnalysisRun once the rollout starts executing its canary
+// steps and keeps it running until the rollout has finished all its steps, at which point the run is terminated.
+func (c *RolloutController) reconcileBackgroundAnalysisRun(rollout *v1alpha1.Rollout, currentArs []*v1alpha1.AnalysisRun, stableRS, newRS *appsv1.ReplicaSet) (*v1alpha1.AnalysisRun, error) {
+	currentAr := analysisutil.FilterAnalysisRunsByName(currentArs, rollout.Status.Canary.CurrentBackgroundAnalysisRun)
+	if rollout.Spec.Strategy.CanaryStrategy.Analysis == nil || !needsBackgroundAnalysisRun(rollout, newRS) {
+		err := c.cancelAnalysisRuns(rollout, []*v1alpha1.AnalysisRun{currentAr})
+		return nil, err
+	}
+	if currentAr != nil && currentAr.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] != replicasetutil.GetPodTemplateHash(newRS) {
+		// The background AnalysisRun was created for a previous ReplicaSet
+		err := c.cancelAnalysisRuns(rollout, []*v1alpha1.AnalysisRun{currentAr})
+		if err != nil {
+			return nil, err
+		}
+		currentAr = nil
+	}
+	if currentAr == nil {
+		return c.createBackgroundAnalysisRun(rollout, rollout.Spec.Strategy.CanaryStrategy.Analysis, stableRS, newRS)
+	}
+	return currentAr, nil
+}
+
+// needsBackgroundAnalysisRun returns whether the rollout is in the middle of promoting a new ReplicaSet, which is
+// the only time a background AnalysisRun should be running.
+func needsBackgroundAnalysisRun(rollout *v1alpha1.Rollout, newRS *appsv1.ReplicaSet) bool {
+	if newRS == nil || rollout.Status.Canary.StableRS == "" {
+		return false
+	}
+	if rollout.Status.Canary.StableRS == replicasetutil.GetPodTemplateHash(newRS) {
+		return false
+	}
+	_, index := replicasetutil.GetCurrentCanaryStep(rollout)
+	stepCount := int32(len(rollout.Spec.Strategy.CanaryStrategy.Steps))
+	if index != nil && *index >= stepCount {
+		return false
+	}
+	return true
+}
+
+func (c *RolloutController) createBackgroundAnalysisRun(rollout *v1alpha1.Rollout, rolloutAnalysis *v1alpha1.RolloutAnalysisStep, stableRS, newRS *appsv1.ReplicaSet) (*v1alpha1.AnalysisRun, error) {
+	podHash := replicasetutil.GetPodTemplateHash(newRS)
+	analysisRunLabels := analysisutil.BackgroundLabels(podHash)
+	args, err := analysisutil.BuildArgumentsForRolloutAnalysisRun(rolloutAnalysis, stableRS, newRS, rollout)
+	if err != nil {
+		return nil, err
+	}
+	ar, err := c.getAnalysisRunFromRollout(rollout, rolloutAnalysis, args, podHash, analysisRunLabels)
+	if err != nil {
+		return nil, err
+	}
+	ar, err = c.argoprojclientset.ArgoprojV1alpha1().AnalysisRuns(ar.Namespace).Create(ar)
+	if err != nil {
+		return nil, err
+	}
+	logutil.WithRollout(rollout).WithField(logutil.AnalysisRunKey, ar.Name).Info("Created background AnalysisRun")
+	return ar, nil
+}
+
 func (c *RolloutController) reconcileStepBasedAnalysisRun(rollout *v1alpha1.Rollout, currentArs []*v1alpha1.AnalysisRun, stableRS, newRS *appsv1.ReplicaSet) (*v1alpha1.AnalysisRun, error) {
 	step, index := replicasetutil.GetCurrentCanaryStep(rollout)
 	currentAr := analysisutil.FilterAnalysisRunsByName(currentArs, rollout.Status.Canary.CurrentStepAnalysisRun)
@@ -92,7 +160,10 @@ func (c *RolloutController) createStepBasedAnalysisRun(rollout *v1alpha1.Rollout
 		}
 	}
 	analysisRunLabels := analysisutil.StepLabels(rollout, index, podHash)
-	args := analysisutil.BuildArgumentsForRolloutAnalysisRun(rolloutAnalysisStep, stableRS, newRS)
+	args, err := analysisutil.BuildArgumentsForRolloutAnalysisRun(rolloutAnalysisStep, stableRS, newRS, rollout)
+	if err != nil {
+		return nil, err
+	}
 	ar, err := c.getAnalysisRunFromRollout(rollout, rolloutAnalysisStep, args, podHash, analysisRunLabels)
 	if err != nil {
 		return nil, err
@@ -105,6 +176,106 @@ func (c *RolloutController) createStepBasedAnalysisRun(rollout *v1alpha1.Rollout
 	return ar, nil
 }
 
+// reconcileBlueGreenAnalysisRuns creates the pre-promotion AnalysisRun while the new ReplicaSet waits behind the
+// preview service, and the post-promotion AnalysisRun once the active service switches to the new ReplicaSet.
+func (c *RolloutController) reconcileBlueGreenAnalysisRuns(rollout *v1alpha1.Rollout, currentArs, otherArs []*v1alpha1.AnalysisRun, activeSelector string, newRS *appsv1.ReplicaSet, oldRSs []*appsv1.ReplicaSet) ([]*v1alpha1.AnalysisRun, error) {
+	newCurrentAnalysisRuns := []*v1alpha1.AnalysisRun{}
+
+	activeRS, _ := replicasetutil.GetReplicaSetByTemplateHash(oldRSs, activeSelector)
+	prePromotionAnalysisRun, err := c.reconcilePrePromotionAnalysisRun(rollout, currentArs, activeRS, newRS)
+	if err != nil {
+		return currentArs, err
+	}
+	if prePromotionAnalysisRun != nil {
+		newCurrentAnalysisRuns = append(newCurrentAnalysisRuns, prePromotionAnalysisRun)
+	}
+
+	previousActiveRS, _ := replicasetutil.GetReplicaSetByTemplateHash(oldRSs, rollout.Status.BlueGreen.PreviousActiveSelector)
+	postPromotionAnalysisRun, err := c.reconcilePostPromotionAnalysisRun(rollout, currentArs, activeSelector, previousActiveRS, newRS)
+	if err != nil {
+		return currentArs, err
+	}
+	if postPromotionAnalysisRun != nil {
+		newCurrentAnalysisRuns = append(newCurrentAnalysisRuns, postPromotionAnalysisRun)
+	}
+
+	err = c.cancelAnalysisRuns(rollout, otherArs)
+	if err != nil {
+		return currentArs, err
+	}
+
+	return newCurrentAnalysisRuns, nil
+}
+
+func (c *RolloutController) reconcilePrePromotionAnalysisRun(rollout *v1alpha1.Rollout, currentArs []*v1alpha1.AnalysisRun, activeRS, newRS *appsv1.ReplicaSet) (*v1alpha1.AnalysisRun, error) {
+	currentAr := analysisutil.FilterAnalysisRunsByName(currentArs, rollout.Status.BlueGreen.PrePromotionAnalysisRun)
+	if !needsPrePromotionAnalysisRun(rollout, activeRS, newRS) {
+		err := c.cancelAnalysisRuns(rollout, []*v1alpha1.AnalysisRun{currentAr})
+		return nil, err
+	}
+	if currentAr == nil {
+		podHash := replicasetutil.GetPodTemplateHash(newRS)
+		return c.createBlueGreenAnalysisRun(rollout, rollout.Spec.Strategy.BlueGreenStrategy.PrePromotionAnalysis, activeRS, newRS, analysisutil.PrePromotionLabels(podHash))
+	}
+	return currentAr, nil
+}
+
+// needsPrePromotionAnalysisRun returns whether the new ReplicaSet is waiting to replace an existing ReplicaSet
+// behind the active service, which is the only time a pre-promotion AnalysisRun should be running.
+func needsPrePromotionAnalysisRun(rollout *v1alpha1.Rollout, activeRS, newRS *appsv1.ReplicaSet) bool {
+	if rollout.Spec.Strategy.BlueGreenStrategy.PrePromotionAnalysis == nil {
+		return false
+	}
+	if newRS == nil || activeRS == nil {
+		return false
+	}
+	return replicasetutil.GetPodTemplateHash(activeRS) != replicasetutil.GetPodTemplateHash(newRS)
+}
+
+func (c *RolloutController) reconcilePostPromotionAnalysisRun(rollout *v1alpha1.Rollout, currentArs []*v1alpha1.AnalysisRun, activeSelector string, previousActiveRS, newRS *appsv1.ReplicaSet) (*v1alpha1.AnalysisRun, error) {
+	currentAr := analysisutil.FilterAnalysisRunsByName(currentArs, rollout.Status.BlueGreen.PostPromotionAnalysisRun)
+	if !needsPostPromotionAnalysisRun(rollout, activeSelector, previousActiveRS, newRS) {
+		err := c.cancelAnalysisRuns(rollout, []*v1alpha1.AnalysisRun{currentAr})
+		return nil, err
+	}
+	if currentAr == nil {
+		podHash := replicasetutil.GetPodTemplateHash(newRS)
+		return c.createBlueGreenAnalysisRun(rollout, rollout.Spec.Strategy.BlueGreenStrategy.PostPromotionAnalysis, previousActiveRS, newRS, analysisutil.PostPromotionLabels(podHash))
+	}
+	return currentAr, nil
+}
+
+// needsPostPromotionAnalysisRun returns whether the active service has switched from a previous ReplicaSet to the
+// new ReplicaSet. The post-promotion AnalysisRun is kept after it completes so it is not created again.
+func needsPostPromotionAnalysisRun(rollout *v1alpha1.Rollout, activeSelector string, previousActiveRS, newRS *appsv1.ReplicaSet) bool {
+	if rollout.Spec.Strategy.BlueGreenStrategy.PostPromotionAnalysis == nil {
+		return false
+	}
+	if newRS == nil || previousActiveRS == nil {
+		return false
+	}
+	newRSPodHash := replicasetutil.GetPodTemplateHash(newRS)
+	return activeSelector == newRSPodHash && replicasetutil.GetPodTemplateHash(previousActiveRS) != newRSPodHash
+}
+
+func (c *RolloutController) createBlueGreenAnalysisRun(rollout *v1alpha1.Rollout, rolloutAnalysis *v1alpha1.RolloutAnalysisStep, stableRS, newRS *appsv1.ReplicaSet, analysisRunLabels map[string]string) (*v1alpha1.AnalysisRun, error) {
+	podHash := replicasetutil.GetPodTemplateHash(newRS)
+	args, err := analysisutil.BuildArgumentsForRolloutAnalysisRun(rolloutAnalysis, stableRS, newRS, rollout)
+	if err != nil {
+		return nil, err
+	}
+	ar, err := c.getAnalysisRunFromRollout(rollout, rolloutAnalysis, args, podHash, analysisRunLabels)
+	if err != nil {
+		return nil, err
+	}
+	ar, err = c.argoprojclientset.ArgoprojV1alpha1().AnalysisRuns(ar.Namespace).Create(ar)
+	if err != nil {
+		return nil, err
+	}
+	logutil.WithRollout(rollout).WithField(logutil.AnalysisRunKey, ar.Name).Infof("Created %s AnalysisRun", analysisRunLabels[v1alpha1.RolloutTypeLabel])
+	return ar, nil
+}
+
 func (c *RolloutController) cancelAnalysisRuns(r *v1alpha1.Rollout, analysisRuns []*v1alpha1.AnalysisRun) error {
 	logctx := logutil.WithRollout(r)
 	for i := range analysisRuns {
@@ -127,18 +298,26 @@ func (c *RolloutController) cancelAnalysisRuns(r *v1alpha1.Rollout, analysisRuns
 
 // getAnalysisRunFromRollout generates an AnalysisRun from the rollouts, the AnalysisRun Step, the new/stable ReplicaSet, and any extra objects.
 func (c *RolloutController) getAnalysisRunFromRollout(r *v1alpha1.Rollout, rolloutAnalysisStep *v1alpha1.RolloutAnalysisStep, args []v1alpha1.Argument, podHash string, labels map[string]string) (*v1alpha1.AnalysisRun, error) {
-	logctx := logutil.WithRollout(r)
-	template, err := c.analysisTemplateLister.AnalysisTemplates(r.Namespace).Get(rolloutAnalysisStep.TemplateName)
+	specs, err := c.getAnalysisTemplateSpecs(r, rolloutAnalysisStep)
+	if err != nil {
+		return nil, err
+	}
+	templateSpec, err := analysisutil.MergeAnalysisTemplateSpecs(specs)
+	if err != nil {
+		return nil, err
+	}
+	templateSpec.Args, args, err = analysisutil.ResolveTemplateArgs(specs, args)
 	if err != nil {
-		if k8serrors.IsNotFound(err) {
-			logctx.Warnf("AnalysisTemplate '%s' not found", rolloutAnalysisStep.TemplateName)
-		}
 		return nil, err
 	}
 
+	templateName := rolloutAnalysisStep.TemplateName
+	if templateName == "" && len(rolloutAnalysisStep.Templates) > 0 {
+		templateName = rolloutAnalysisStep.Templates[0].TemplateName
+	}
 	ar := v1alpha1.AnalysisRun{
 		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: fmt.Sprintf("%s-%s-%s", r.Name, rolloutAnalysisStep.TemplateName, podHash),
+			GenerateName: fmt.Sprintf("%s-%s-%s", r.Name, templateName, podHash),
 			Namespace:    r.Namespace,
 			Labels:       labels,
 			Annotations: map[string]string{
@@ -147,9 +326,49 @@ func (c *RolloutController) getAnalysisRunFromRollout(r *v1alpha1.Rollout, rollo
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(r, controllerKind)},
 		},
 		Spec: v1alpha1.AnalysisRunSpec{
-			AnalysisSpec: template.Spec,
+			AnalysisSpec: *templateSpec,
 			Arguments:    args,
 		},
 	}
 	return &ar, nil
 }
+
+// getAnalysisTemplateSpecs returns the specs of all the templates referenced by the analysis step
+func (c *RolloutController) getAnalysisTemplateSpecs(r *v1alpha1.Rollout, rolloutAnalysisStep *v1alpha1.RolloutAnalysisStep) ([]*v1alpha1.AnalysisTemplateSpec, error) {
+	var specs []*v1alpha1.AnalysisTemplateSpec
+	for _, ref := range analysisutil.GetTemplateRefs(rolloutAnalysisStep) {
+		spec, err := c.getAnalysisTemplateSpecFromRef(r, ref)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// getAnalysisTemplateSpecFromRef returns the spec of the referenced AnalysisTemplate, or the spec of the
+// ClusterAnalysisTemplate if the reference is cluster scoped
+func (c *RolloutController) getAnalysisTemplateSpecFromRef(r *v1alpha1.Rollout, ref v1alpha1.RolloutAnalysisTemplate) (*v1alpha1.AnalysisTemplateSpec, error) {
+	logctx := logutil.WithRollout(r)
+	if ref.ClusterScope {
+		if c.clusterAnalysisTemplateLister == nil {
+			return nil, fmt.Errorf("ClusterAnalysisTemplate '%s' cannot be used by a controller limited to a namespace", ref.TemplateName)
+		}
+		clusterTemplate, err := c.clusterAnalysisTemplateLister.Get(ref.TemplateName)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				logctx.Warnf("ClusterAnalysisTemplate '%s' not found", ref.TemplateName)
+			}
+			return nil, err
+		}
+		return clusterTemplate.Spec.DeepCopy(), nil
+	}
+	template, err := c.analysisTemplateLister.AnalysisTemplates(r.Namespace).Get(ref.TemplateName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			logctx.Warnf("AnalysisTemplate '%s' not found", ref.TemplateName)
+		}
+		return nil, err
+	}
+	return template.Spec.DeepCopy(), nil
+}
@@ -1,6 +1,7 @@
 package rollout
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -31,11 +32,30 @@ func analysisTemplate(name string) *v1alpha1.AnalysisTemplate {
 	}
 }
 
+func clusterAnalysisTemplate(name string) *v1alpha1.ClusterAnalysisTemplate {
+	return &v1alpha1.ClusterAnalysisTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: v1alpha1.AnalysisTemplateSpec{
+			Metrics: []v1alpha1.Metric{{
+				Name: "cluster-example",
+			}},
+		},
+	}
+}
+
 func analysisRun(at *v1alpha1.AnalysisTemplate, analysisRunType string, r *v1alpha1.Rollout) *v1alpha1.AnalysisRun {
 	labels := map[string]string{}
 	podHash := controller.ComputeHash(&r.Spec.Template, r.Status.CollisionCount)
 	if analysisRunType == v1alpha1.RolloutTypeStepLabel {
 		labels = analysisutil.StepLabels(r, *r.Status.CurrentStepIndex, podHash)
+	} else if analysisRunType == v1alpha1.RolloutTypeBackgroundRunLabel {
+		labels = analysisutil.BackgroundLabels(podHash)
+	} else if analysisRunType == v1alpha1.RolloutTypePrePromotionLabel {
+		labels = analysisutil.PrePromotionLabels(podHash)
+	} else if analysisRunType == v1alpha1.RolloutTypePostPromotionLabel {
+		labels = analysisutil.PostPromotionLabels(podHash)
 	}
 	return &v1alpha1.AnalysisRun{
 		ObjectMeta: metav1.ObjectMeta{
@@ -102,6 +122,67 @@ func TestCreateAnalysisRunOnAnalysisStep(t *testing.T) {
 	assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, expectedArName)), patch)
 }
 
+func TestCreateAnalysisRunWithFieldRefAndSecretArguments(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("bar")
+	steps := []v1alpha1.CanaryStep{{
+		Analysis: &v1alpha1.RolloutAnalysisStep{
+			TemplateName: at.Name,
+			Arguments: []v1alpha1.AnalysisRunArgument{
+				{
+					Name: "namespace",
+					ValueFrom: &v1alpha1.ArgumentValueFrom{
+						FieldRef: &v1alpha1.FieldRef{FieldPath: "metadata.namespace"},
+					},
+				},
+				{
+					Name: "api-token",
+					ValueFrom: &v1alpha1.ArgumentValueFrom{
+						SecretKeyRef: &v1alpha1.SecretKeyRef{Name: "metrics", Key: "token"},
+					},
+				},
+			},
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypeStepLabel, r2)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+	progressingCondition, _ := newProgressingCondition(conditions.ReplicaSetUpdatedReason, rs2)
+	conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+	availableCondition, _ := newAvailableCondition(true)
+	conditions.SetRolloutCondition(&r2.Status, availableCondition)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.objects = append(f.objects, r2, at)
+
+	createdIndex := f.expectCreateAnalysisRunAction(ar)
+	f.expectPatchRolloutAction(r1)
+
+	f.run(getKey(r2, t))
+	createdAr := f.getCreatedAnalysisRun(createdIndex)
+	assert.Equal(t, []v1alpha1.Argument{
+		{Name: "namespace", Value: metav1.NamespaceDefault},
+		{
+			Name: "api-token",
+			ValueFrom: &v1alpha1.ValueFrom{
+				SecretKeyRef: &v1alpha1.SecretKeyRef{Name: "metrics", Key: "token"},
+			},
+		},
+	}, createdAr.Spec.Arguments)
+}
+
 func TestFailCreateAnalysisRunIfInvalidTemplateRef(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -133,6 +214,243 @@ func TestFailCreateAnalysisRunIfInvalidTemplateRef(t *testing.T) {
 	f.runExpectError(getKey(r2, t), true)
 }
 
+func TestCreateAnalysisRunOnClusterAnalysisStep(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	// a namespaced template with the same name must not be used for a cluster scoped step
+	at := analysisTemplate("bar")
+	cat := clusterAnalysisTemplate("bar")
+	steps := []v1alpha1.CanaryStep{{
+		Analysis: &v1alpha1.RolloutAnalysisStep{
+			TemplateName: cat.Name,
+			ClusterScope: true,
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	ar := analysisRun(&v1alpha1.AnalysisTemplate{ObjectMeta: cat.ObjectMeta, Spec: cat.Spec}, v1alpha1.RolloutTypeStepLabel, r2)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+	progressingCondition, _ := newProgressingCondition(conditions.ReplicaSetUpdatedReason, rs2)
+	conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+	availableCondition, _ := newAvailableCondition(true)
+	conditions.SetRolloutCondition(&r2.Status, availableCondition)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.clusterAnalysisTemplateLister = append(f.clusterAnalysisTemplateLister, cat)
+	f.objects = append(f.objects, r2, at, cat)
+
+	createdIndex := f.expectCreateAnalysisRunAction(ar)
+	f.expectPatchRolloutAction(r1)
+
+	f.run(getKey(r2, t))
+	createdAr := f.getCreatedAnalysisRun(createdIndex)
+	assert.Equal(t, cat.Spec, createdAr.Spec.AnalysisSpec)
+	assert.Equal(t, metav1.NamespaceDefault, createdAr.Namespace)
+}
+
+func TestFailCreateAnalysisRunIfInvalidClusterTemplateRef(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	// only a namespaced template exists with the referenced name
+	at := analysisTemplate("bar")
+	steps := []v1alpha1.CanaryStep{{
+		Analysis: &v1alpha1.RolloutAnalysisStep{
+			TemplateName: at.Name,
+			ClusterScope: true,
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+	progressingCondition, _ := newProgressingCondition(conditions.ReplicaSetUpdatedReason, rs2)
+	conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+	availableCondition, _ := newAvailableCondition(true)
+	conditions.SetRolloutCondition(&r2.Status, availableCondition)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.objects = append(f.objects, r2, at)
+
+	f.runExpectError(getKey(r2, t), true)
+}
+
+func TestCreateAnalysisRunWithMultipleTemplates(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("bar")
+	cat := clusterAnalysisTemplate("baz")
+	steps := []v1alpha1.CanaryStep{{
+		Analysis: &v1alpha1.RolloutAnalysisStep{
+			Templates: []v1alpha1.RolloutAnalysisTemplate{
+				{TemplateName: at.Name},
+				{TemplateName: cat.Name, ClusterScope: true},
+			},
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypeStepLabel, r2)
+	ar.Spec.AnalysisSpec.Metrics = append(ar.Spec.AnalysisSpec.Metrics, cat.Spec.Metrics...)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+	progressingCondition, _ := newProgressingCondition(conditions.ReplicaSetUpdatedReason, rs2)
+	conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+	availableCondition, _ := newAvailableCondition(true)
+	conditions.SetRolloutCondition(&r2.Status, availableCondition)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.clusterAnalysisTemplateLister = append(f.clusterAnalysisTemplateLister, cat)
+	f.objects = append(f.objects, r2, at, cat)
+
+	createdIndex := f.expectCreateAnalysisRunAction(ar)
+	f.expectPatchRolloutAction(r1)
+
+	f.run(getKey(r2, t))
+	createdAr := f.getCreatedAnalysisRun(createdIndex)
+	assert.Len(t, createdAr.Spec.AnalysisSpec.Metrics, 2)
+	assert.Equal(t, "example", createdAr.Spec.AnalysisSpec.Metrics[0].Name)
+	assert.Equal(t, "cluster-example", createdAr.Spec.AnalysisSpec.Metrics[1].Name)
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	assert.Equal(t, fmt.Sprintf("%s-%s-%s", r2.Name, at.Name, rs2PodHash), createdAr.GenerateName)
+}
+
+func TestInvalidSpecWithDuplicateMetricsAcrossTemplates(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at1 := analysisTemplate("bar")
+	at2 := analysisTemplate("baz")
+	steps := []v1alpha1.CanaryStep{{
+		Analysis: &v1alpha1.RolloutAnalysisStep{
+			Templates: []v1alpha1.RolloutAnalysisTemplate{
+				{TemplateName: at1.Name},
+				{TemplateName: at2.Name},
+			},
+		},
+	}}
+
+	r := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at1, at2)
+	f.objects = append(f.objects, r, at1, at2)
+
+	patchIndex := f.expectPatchRolloutAction(r)
+	f.run(getKey(r, t))
+
+	patchedRollout := &v1alpha1.Rollout{}
+	assert.NoError(t, json.Unmarshal([]byte(f.getPatchedRollout(patchIndex)), patchedRollout))
+	invalidSpecCond := conditions.GetRolloutCondition(patchedRollout.Status, v1alpha1.InvalidSpec)
+	assert.NotNil(t, invalidSpecCond)
+	assert.Equal(t, conditions.InvalidSpecReason, invalidSpecCond.Reason)
+	assert.Equal(t, fmt.Sprintf(conditions.DuplicatedAnalysisMetricMessage, ".Spec.Strategy.CanaryStrategy.Steps[0].Analysis", "example"), invalidSpecCond.Message)
+}
+
+func TestInvalidSpecWithMissingTemplateArgument(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("bar")
+	at.Spec.Args = []v1alpha1.AnalysisTemplateArgument{{Name: "service-name"}}
+	steps := []v1alpha1.CanaryStep{{
+		Analysis: &v1alpha1.RolloutAnalysisStep{
+			TemplateName: at.Name,
+		},
+	}}
+
+	r := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.objects = append(f.objects, r, at)
+
+	patchIndex := f.expectPatchRolloutAction(r)
+	f.run(getKey(r, t))
+
+	patchedRollout := &v1alpha1.Rollout{}
+	assert.NoError(t, json.Unmarshal([]byte(f.getPatchedRollout(patchIndex)), patchedRollout))
+	invalidSpecCond := conditions.GetRolloutCondition(patchedRollout.Status, v1alpha1.InvalidSpec)
+	assert.NotNil(t, invalidSpecCond)
+	assert.Equal(t, conditions.InvalidSpecReason, invalidSpecCond.Reason)
+	assert.Equal(t, fmt.Sprintf(conditions.MissingAnalysisArgumentMessage, ".Spec.Strategy.CanaryStrategy.Steps[0].Analysis", "service-name"), invalidSpecCond.Message)
+}
+
+func TestCreateAnalysisRunWithTemplateArgumentDefaults(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	defaultValue := "guestbook-svc"
+	at := analysisTemplate("bar")
+	at.Spec.Args = []v1alpha1.AnalysisTemplateArgument{
+		{Name: "service-name", Default: &defaultValue},
+		{Name: "namespace"},
+	}
+	steps := []v1alpha1.CanaryStep{{
+		Analysis: &v1alpha1.RolloutAnalysisStep{
+			TemplateName: at.Name,
+			Arguments: []v1alpha1.AnalysisRunArgument{
+				{Name: "namespace", Value: metav1.NamespaceDefault},
+			},
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypeStepLabel, r2)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+	progressingCondition, _ := newProgressingCondition(conditions.ReplicaSetUpdatedReason, rs2)
+	conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+	availableCondition, _ := newAvailableCondition(true)
+	conditions.SetRolloutCondition(&r2.Status, availableCondition)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.objects = append(f.objects, r2, at)
+
+	createdIndex := f.expectCreateAnalysisRunAction(ar)
+	f.expectPatchRolloutAction(r1)
+
+	f.run(getKey(r2, t))
+	createdAr := f.getCreatedAnalysisRun(createdIndex)
+	assert.Equal(t, []v1alpha1.Argument{
+		{Name: "namespace", Value: metav1.NamespaceDefault},
+		{Name: "service-name", Value: "guestbook-svc"},
+	}, createdAr.Spec.Arguments)
+}
+
 func TestDoNothingWhileAnalysisRunRunning(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -310,15 +628,17 @@ func TestPausedStepAfterInconclusiveAnalysisRun(t *testing.T) {
 			"canary": {
 				"currentStepAnalysisRun": null
 			},
-			"pauseStartTime": "%s"
+			"pauseStartTime": "%s",
+			"pauseConditions": %s
 		}
 	}`
 	condition := generateConditionsPatch(true, conditions.ReplicaSetUpdatedReason, r2, false)
+	pauseConditions := generatePauseConditionsPatch(now, v1alpha1.PauseReasonInconclusiveAnalysis)
 
-	assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, condition, now)), patch)
+	assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, condition, now, pauseConditions)), patch)
 }
 
-func TestErrorConditionAfterErrorAnalysisRun(t *testing.T) {
+func TestAbortRolloutAfterErrorAnalysisRun(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
 
@@ -358,10 +678,378 @@ func TestErrorConditionAfterErrorAnalysisRun(t *testing.T) {
 	patch := f.getPatchedRollout(patchIndex)
 	expectedPatch := `{
 		"status": {
-			"conditions": %s
+			"conditions": %s,
+			"canary": {
+				"currentStepAnalysisRun": null
+			},
+			"abort": true
+		}
+	}`
+	condition := generateConditionsPatch(true, conditions.RolloutAbortedReason, r2, false)
+
+	assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, condition)), patch)
+}
+
+func TestCreateBackgroundAnalysisRun(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("bar")
+	steps := []v1alpha1.CanaryStep{{
+		Pause: &v1alpha1.RolloutPause{},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r1.Spec.Strategy.CanaryStrategy.Analysis = &v1alpha1.RolloutAnalysisStep{
+		TemplateName: at.Name,
+	}
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypeBackgroundRunLabel, r2)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.objects = append(f.objects, r2, at)
+
+	createdIndex := f.expectCreateAnalysisRunAction(ar)
+	index := f.expectPatchRolloutAction(r1)
+
+	f.run(getKey(r2, t))
+	createdAr := f.getCreatedAnalysisRun(createdIndex)
+	expectedArGeneratedName := fmt.Sprintf("%s-%s-%s", r2.Name, at.Name, rs2PodHash)
+	expectedArName := fmt.Sprintf("%s-%s", expectedArGeneratedName, MockGeneratedNameSuffix)
+	assert.Equal(t, expectedArGeneratedName, createdAr.GenerateName)
+	assert.Equal(t, v1alpha1.RolloutTypeBackgroundRunLabel, createdAr.Labels[v1alpha1.RolloutTypeLabel])
+
+	patch := f.getPatchedRollout(index)
+	now := metav1.Now().UTC().Format(time.RFC3339)
+	expectedPatch := `{
+		"spec":{
+			"paused": true
+		},
+		"status": {
+			"conditions": %s,
+			"canary": {
+				"currentBackgroundAnalysisRun": "%s"
+			},
+			"pauseStartTime": "%s",
+			"pauseConditions": %s
 		}
 	}`
-	condition := generateConditionsPatch(true, conditions.RolloutAnalysisRunFailedReason, r2, false)
+	condition := generateConditionsPatch(true, conditions.ReplicaSetUpdatedReason, r2, false)
+	pauseConditions := generatePauseConditionsPatch(now, v1alpha1.PauseReasonCanaryPauseStep)
+	assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, condition, expectedArName, now, pauseConditions)), patch)
+}
+
+func TestAbortRolloutAfterFailedBackgroundAnalysisRun(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("bar")
+	steps := []v1alpha1.CanaryStep{{
+		SetWeight: pointer.Int32Ptr(0),
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r1.Spec.Strategy.CanaryStrategy.Analysis = &v1alpha1.RolloutAnalysisStep{
+		TemplateName: at.Name,
+	}
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypeBackgroundRunLabel, r2)
+	ar.Status = &v1alpha1.AnalysisRunStatus{
+		Status: v1alpha1.AnalysisStatusFailed,
+		MetricResults: []v1alpha1.MetricResult{{
+			Status: v1alpha1.AnalysisStatusFailed,
+		}},
+	}
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+	r2.Status.Canary.CurrentBackgroundAnalysisRun = ar.Name
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.analysisRunLister = append(f.analysisRunLister, ar)
+	f.objects = append(f.objects, r2, at, ar)
+
+	patchIndex := f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+	patch := f.getPatchedRollout(patchIndex)
+	expectedPatch := `{
+		"status": {
+			"conditions": %s,
+			"canary": {
+				"currentBackgroundAnalysisRun": null
+			},
+			"abort": true
+		}
+	}`
+	condition := generateConditionsPatch(true, conditions.RolloutAbortedReason, r2, false)
+
+	assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, condition)), patch)
+}
+
+func TestCancelBackgroundAnalysisRunWhenRolloutCompletes(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("bar")
+	steps := []v1alpha1.CanaryStep{{
+		SetWeight: pointer.Int32Ptr(10),
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(1), intstr.FromInt(0), intstr.FromInt(1))
+	r1.Spec.Strategy.CanaryStrategy.Analysis = &v1alpha1.RolloutAnalysisStep{
+		TemplateName: at.Name,
+	}
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypeBackgroundRunLabel, r2)
+
+	rs1 := newReplicaSetWithStatus(r1, 0, 0)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 1, 1, false)
+	r2.Status.Canary.CurrentBackgroundAnalysisRun = ar.Name
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.analysisRunLister = append(f.analysisRunLister, ar)
+	f.objects = append(f.objects, r2, at, ar)
+
+	cancelBackgroundAr := f.expectPatchAnalysisRunAction(ar)
+	patchIndex := f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+
+	assert.True(t, f.verifyPatchedAnalysisRun(cancelBackgroundAr, ar))
+	patch := f.getPatchedRollout(patchIndex)
+	assert.Contains(t, patch, `"currentBackgroundAnalysisRun":null`)
+}
+
+func TestCancelAnalysisRunsWhenAborted(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("bar")
+	// The background run uses another template so its name differs from the step run
+	backgroundAt := analysisTemplate("baz")
+	steps := []v1alpha1.CanaryStep{{
+		Analysis: &v1alpha1.RolloutAnalysisStep{
+			TemplateName: at.Name,
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r1.Spec.Strategy.CanaryStrategy.Analysis = &v1alpha1.RolloutAnalysisStep{
+		TemplateName: backgroundAt.Name,
+	}
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypeStepLabel, r2)
+	backgroundAr := analysisRun(backgroundAt, v1alpha1.RolloutTypeBackgroundRunLabel, r2)
+	backgroundAr.Status = &v1alpha1.AnalysisRunStatus{
+		Status: v1alpha1.AnalysisStatusFailed,
+	}
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+	r2.Status.Canary.CurrentStepAnalysisRun = ar.Name
+	r2.Status.Canary.CurrentBackgroundAnalysisRun = backgroundAr.Name
+	r2.Status.Abort = true
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at, backgroundAt)
+	f.analysisRunLister = append(f.analysisRunLister, ar, backgroundAr)
+	f.objects = append(f.objects, r2, at, backgroundAt, ar, backgroundAr)
 
+	cancelStepAr := f.expectPatchAnalysisRunAction(ar)
+	patchIndex := f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+
+	assert.True(t, f.verifyPatchedAnalysisRun(cancelStepAr, ar))
+	patch := f.getPatchedRollout(patchIndex)
+	expectedPatch := `{
+		"status": {
+			"conditions": %s,
+			"canary": {
+				"currentStepAnalysisRun": null,
+				"currentBackgroundAnalysisRun": null
+			}
+		}
+	}`
+	condition := generateConditionsPatch(true, conditions.RolloutAbortedReason, r2, false)
 	assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, condition)), patch)
 }
+
+func TestCreatePrePromotionAnalysisRun(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("test")
+	r1 := newBlueGreenRollout("foo", 1, nil, "active", "")
+	r1.Spec.Strategy.BlueGreenStrategy.PrePromotionAnalysis = &v1alpha1.RolloutAnalysisStep{
+		TemplateName: at.Name,
+	}
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypePrePromotionLabel, r2)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	r2 = updateBlueGreenRolloutStatus(r2, "", rs1PodHash, 1, 1, 2, 1, false, true)
+	activeSelector := map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs1PodHash}
+	activeSvc := newService("active", 80, activeSelector)
+
+	f.objects = append(f.objects, r2, at)
+	f.kubeobjects = append(f.kubeobjects, activeSvc, rs1, rs2)
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.serviceLister = append(f.serviceLister, activeSvc)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+
+	createdIndex := f.expectCreateAnalysisRunAction(ar)
+	patchIndex := f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+
+	createdAr := f.getCreatedAnalysisRun(createdIndex)
+	assert.Equal(t, v1alpha1.RolloutTypePrePromotionLabel, createdAr.Labels[v1alpha1.RolloutTypeLabel])
+	patch := f.getPatchedRollout(patchIndex)
+	assert.Contains(t, patch, fmt.Sprintf(`"prePromotionAnalysisRun":"%s"`, ar.Name))
+}
+
+func TestAbortRolloutAfterFailedPrePromotionAnalysisRun(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("test")
+	r1 := newBlueGreenRollout("foo", 1, nil, "active", "")
+	r1.Spec.Strategy.BlueGreenStrategy.PrePromotionAnalysis = &v1alpha1.RolloutAnalysisStep{
+		TemplateName: at.Name,
+	}
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypePrePromotionLabel, r2)
+	ar.Status = &v1alpha1.AnalysisRunStatus{
+		Status: v1alpha1.AnalysisStatusFailed,
+	}
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	r2 = updateBlueGreenRolloutStatus(r2, "", rs1PodHash, 1, 1, 2, 1, false, true)
+	r2.Status.BlueGreen.PrePromotionAnalysisRun = ar.Name
+	activeSelector := map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs1PodHash}
+	activeSvc := newService("active", 80, activeSelector)
+
+	f.objects = append(f.objects, r2, at, ar)
+	f.kubeobjects = append(f.kubeobjects, activeSvc, rs1, rs2)
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.serviceLister = append(f.serviceLister, activeSvc)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+	f.analysisRunLister = append(f.analysisRunLister, ar)
+
+	patchIndex := f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+
+	patch := f.getPatchedRollout(patchIndex)
+	assert.Contains(t, patch, `"abort":true`)
+	assert.Contains(t, patch, `"prePromotionAnalysisRun":null`)
+}
+
+func TestCreatePostPromotionAnalysisRun(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("test")
+	r1 := newBlueGreenRollout("foo", 1, nil, "active", "")
+	r1.Spec.Strategy.BlueGreenStrategy.PostPromotionAnalysis = &v1alpha1.RolloutAnalysisStep{
+		TemplateName: at.Name,
+	}
+	r2 := bumpVersion(r1)
+	ar := analysisRun(at, v1alpha1.RolloutTypePostPromotionLabel, r2)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	inTheFuture := metav1.Now().Add(10 * time.Second).UTC().Format(time.RFC3339)
+	rs1.Annotations[v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey] = inTheFuture
+
+	r2 = updateBlueGreenRolloutStatus(r2, "", rs2PodHash, 1, 1, 2, 1, false, true)
+	r2.Status.BlueGreen.PreviousActiveSelector = rs1PodHash
+	activeSelector := map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs2PodHash}
+	activeSvc := newService("active", 80, activeSelector)
+
+	f.objects = append(f.objects, r2, at)
+	f.kubeobjects = append(f.kubeobjects, activeSvc, rs1, rs2)
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.serviceLister = append(f.serviceLister, activeSvc)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+
+	createdIndex := f.expectCreateAnalysisRunAction(ar)
+	patchIndex := f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+
+	createdAr := f.getCreatedAnalysisRun(createdIndex)
+	assert.Equal(t, v1alpha1.RolloutTypePostPromotionLabel, createdAr.Labels[v1alpha1.RolloutTypeLabel])
+	patch := f.getPatchedRollout(patchIndex)
+	assert.Contains(t, patch, fmt.Sprintf(`"postPromotionAnalysisRun":"%s"`, ar.Name))
+}
+
+func TestAbortedRolloutSwitchesActiveServiceBack(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	at := analysisTemplate("test")
+	r1 := newBlueGreenRollout("foo", 1, nil, "active", "")
+	r1.Spec.Strategy.BlueGreenStrategy.PostPromotionAnalysis = &v1alpha1.RolloutAnalysisStep{
+		TemplateName: at.Name,
+	}
+	r2 := bumpVersion(r1)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	rs2PodHash := rs2.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateBlueGreenRolloutStatus(r2, "", rs2PodHash, 1, 1, 2, 1, false, true)
+	r2.Status.BlueGreen.PreviousActiveSelector = rs1PodHash
+	r2.Status.Abort = true
+	activeSelector := map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: rs2PodHash}
+	activeSvc := newService("active", 80, activeSelector)
+
+	f.objects = append(f.objects, r2, at)
+	f.kubeobjects = append(f.kubeobjects, activeSvc, rs1, rs2)
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.serviceLister = append(f.serviceLister, activeSvc)
+	f.analysisTemplateLister = append(f.analysisTemplateLister, at)
+
+	servicePatchIndex := f.expectPatchServiceAction(activeSvc, rs1PodHash)
+	patchIndex := f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+
+	assert.True(t, f.verifyPatchedService(servicePatchIndex, rs1PodHash))
+	patch := f.getPatchedRollout(patchIndex)
+	assert.Contains(t, patch, fmt.Sprintf(`"activeSelector":"%s"`, rs1PodHash))
+}
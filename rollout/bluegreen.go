@@ -1,6 +1,7 @@
 package rollout
 
 import (
+	"fmt"
 	"sort"
 	"time"
 
@@ -10,7 +11,9 @@ import (
 	"k8s.io/kubernetes/pkg/controller"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
 	"github.com/argoproj/argo-rollouts/utils/annotations"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
@@ -28,9 +31,27 @@ func (c *RolloutController) rolloutBlueGreen(r *v1alpha1.Rollout, rsList []*apps
 	if err != nil {
 		return err
 	}
+	arList, err := c.getAnalysisRunsForRollout(r)
+	if err != nil {
+		return err
+	}
+	currentArs, otherArs := analysisutil.FilterCurrentRolloutAnalysisRuns(arList, r)
+
 	if reconcileBlueGreenTemplateChange(r, newRS) {
 		logCtx.Infof("New pod template or template change detected")
-		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, r, false)
+		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, nil, r, false)
+	}
+
+	if r.Status.Abort {
+		// An aborted rollout stops progressing until the pod template changes or the rollout is retried
+		logCtx.Info("Reconciling aborted rollout")
+		if err := c.cancelAnalysisRuns(r, arList); err != nil {
+			return err
+		}
+		if err := c.reconcileAbortedActiveService(r, newRS, oldRSs, activeSvc); err != nil {
+			return err
+		}
+		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, nil, r, false)
 	}
 	allRSs := append(oldRSs, newRS)
 
@@ -59,21 +80,28 @@ func (c *RolloutController) rolloutBlueGreen(r *v1alpha1.Rollout, rsList []*apps
 
 	if scaledUp {
 		logCtx.Infof("Not finished reconciling new ReplicaSet '%s'", newRS.Name)
-		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, r, false)
+		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, currentArs, r, false)
 	}
 
 	if scaledDown {
 		logCtx.Info("Not finished reconciling old replica sets")
-		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, r, false)
+		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, currentArs, r, false)
 	}
 	if switchPreviewSvc {
 		logCtx.Infof("Not finished reconciling preview service' %s'", previewSvc.Name)
-		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, r, false)
+		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, currentArs, r, false)
 	}
 
 	if !replicasetutil.ReadyForPause(r, newRS, allRSs) {
 		logutil.WithRollout(r).Infof("New RS '%s' is not fully saturated", newRS.Name)
-		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, r, false)
+		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, currentArs, r, false)
+	}
+
+	logCtx.Info("Reconciling AnalysisRuns")
+	activeSelector, _ := serviceutil.GetRolloutSelectorLabel(activeSvc)
+	currentArs, err = c.reconcileBlueGreenAnalysisRuns(r, currentArs, otherArs, activeSelector, newRS, oldRSs)
+	if err != nil {
+		return err
 	}
 
 	noFastRollback := true
@@ -89,14 +117,19 @@ func (c *RolloutController) rolloutBlueGreen(r *v1alpha1.Rollout, rsList []*apps
 		pauseBeforeSwitchActive := c.reconcileBlueGreenPause(activeSvc, previewSvc, r, newRS)
 		if pauseBeforeSwitchActive {
 			logCtx.Info("Not finished reconciling pause")
-			return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, r, true)
+			return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, currentArs, r, true)
 		}
 	}
 
 	logCtx.Infof("Reconciling active service '%s'", activeSvc.Name)
 	if !annotations.IsSaturated(r, newRS) {
 		logutil.WithRollout(r).Infof("New RS '%s' is not fully saturated", newRS.Name)
-		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, r, false)
+		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, currentArs, r, false)
+	}
+	prePromotionAr := analysisutil.GetCurrentPrePromotionAnalysisRun(currentArs)
+	if prePromotionAr != nil && (prePromotionAr.Status == nil || prePromotionAr.Status.Status != v1alpha1.AnalysisStatusSuccessful) {
+		logCtx.WithField(logutil.AnalysisRunKey, prePromotionAr.Name).Info("Waiting for pre-promotion AnalysisRun to succeed")
+		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, currentArs, r, false)
 	}
 	switchActiveSvc, err := c.reconcileActiveService(r, newRS, previewSvc, activeSvc)
 	if err != nil {
@@ -104,7 +137,7 @@ func (c *RolloutController) rolloutBlueGreen(r *v1alpha1.Rollout, rsList []*apps
 	}
 	if switchActiveSvc {
 		logCtx.Infof("Not finished reconciling active service '%s'", activeSvc.Name)
-		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, r, false)
+		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, currentArs, r, false)
 	}
 
 	if _, ok := newRS.Annotations[v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey]; ok {
@@ -116,7 +149,7 @@ func (c *RolloutController) rolloutBlueGreen(r *v1alpha1.Rollout, rsList []*apps
 		}
 	}
 
-	return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, r, false)
+	return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, currentArs, r, false)
 }
 
 // reconcileBlueGreenTemplateChange returns true if we detect there was a change in the pod template
@@ -194,7 +227,8 @@ func (c *RolloutController) scaleDownOldReplicaSetsForBlueGreen(oldRSs []*appsv1
 	return totalScaledDown, nil
 }
 
-func (c *RolloutController) syncRolloutStatusBlueGreen(oldRSs []*appsv1.ReplicaSet, newRS *appsv1.ReplicaSet, previewSvc *corev1.Service, activeSvc *corev1.Service, r *v1alpha1.Rollout, addPause bool) error {
+func (c *RolloutController) syncRolloutStatusBlueGreen(oldRSs []*appsv1.ReplicaSet, newRS *appsv1.ReplicaSet, previewSvc *corev1.Service, activeSvc *corev1.Service, currArs []*v1alpha1.AnalysisRun, r *v1alpha1.Rollout, addPause bool) error {
+	logCtx := logutil.WithRollout(r)
 	allRSs := append(oldRSs, newRS)
 	newStatus := c.calculateBaseStatus(allRSs, newRS, r)
 	newStatus.AvailableReplicas = replicasetutil.GetAvailableReplicaCountForReplicaSets([]*appsv1.ReplicaSet{newRS})
@@ -208,6 +242,7 @@ func (c *RolloutController) syncRolloutStatusBlueGreen(oldRSs []*appsv1.ReplicaS
 		activeSelector = ""
 	}
 	newStatus.BlueGreen.ActiveSelector = activeSelector
+	newStatus.BlueGreen.PreviousActiveSelector = r.Status.BlueGreen.PreviousActiveSelector
 	if newStatus.BlueGreen.ActiveSelector != r.Status.BlueGreen.ActiveSelector {
 		previousActiveRS, _ := replicasetutil.GetReplicaSetByTemplateHash(oldRSs, r.Status.BlueGreen.ActiveSelector)
 		if replicasetutil.GetReplicaCountForReplicaSets([]*appsv1.ReplicaSet{previousActiveRS}) > 0 {
@@ -216,6 +251,29 @@ func (c *RolloutController) syncRolloutStatusBlueGreen(oldRSs []*appsv1.ReplicaS
 				return err
 			}
 		}
+		// The previous active selector is only needed to switch back if the post-promotion analysis fails
+		promotedNewRS := newRS != nil && activeSelector == replicasetutil.GetPodTemplateHash(newRS)
+		if r.Spec.Strategy.BlueGreenStrategy.PostPromotionAnalysis != nil && promotedNewRS && r.Status.BlueGreen.ActiveSelector != "" {
+			newStatus.BlueGreen.PreviousActiveSelector = r.Status.BlueGreen.ActiveSelector
+		}
+	}
+
+	if !reconcileBlueGreenTemplateChange(r, newRS) {
+		prePromotionAr := analysisutil.GetCurrentPrePromotionAnalysisRun(currArs)
+		if prePromotionAr != nil {
+			newStatus.BlueGreen.PrePromotionAnalysisRun = prePromotionAr.Name
+		}
+		postPromotionAr := analysisutil.GetCurrentPostPromotionAnalysisRun(currArs)
+		if postPromotionAr != nil {
+			newStatus.BlueGreen.PostPromotionAnalysisRun = postPromotionAr.Name
+		}
+		abortingAr := getAbortingBlueGreenAnalysisRun(prePromotionAr, postPromotionAr)
+		if abortingAr != nil && !r.Status.Abort {
+			msg := fmt.Sprintf("Rollout aborted due to AnalysisRun '%s' completing %s", abortingAr.Name, abortingAr.Status.Status)
+			logCtx.Info(msg)
+			c.recorder.Event(r, corev1.EventTypeWarning, conditions.RolloutAbortedReason, msg)
+		}
+		newStatus.Abort = r.Status.Abort || abortingAr != nil
 	}
 
 	activeRS, _ := replicasetutil.GetReplicaSetByTemplateHash(allRSs, newStatus.BlueGreen.ActiveSelector)
@@ -227,13 +285,34 @@ func (c *RolloutController) syncRolloutStatusBlueGreen(oldRSs []*appsv1.ReplicaS
 		newStatus.Selector = metav1.FormatLabelSelector(r.Spec.Selector)
 	}
 
-	pauseStartTime, paused := calculatePauseStatus(r, newRS, addPause, nil)
+	pauseStartTime, pauseConditions, paused := calculatePauseStatus(r, newRS, addPause, nil)
 	newStatus.PauseStartTime = pauseStartTime
+	newStatus.PauseConditions = pauseConditions
+	if newStatus.Abort {
+		// The AnalysisRuns are terminated by an aborted rollout and a paused rollout is resumed so the active
+		// service can switch back to the previous ReplicaSet
+		newStatus.BlueGreen.PrePromotionAnalysisRun = ""
+		newStatus.BlueGreen.PostPromotionAnalysisRun = ""
+		newStatus.PauseStartTime = nil
+		newStatus.PauseConditions = nil
+		paused = false
+	}
 	newStatus.BlueGreen.ScaleUpPreviewCheckPoint = calculateScaleUpPreviewCheckPoint(r, newRS, activeRS)
 	newStatus = c.calculateRolloutConditions(r, newStatus, allRSs, newRS, nil, nil)
 	return c.persistRolloutStatus(r, &newStatus, &paused)
 }
 
+// getAbortingBlueGreenAnalysisRun returns the pre-promotion or post-promotion AnalysisRun that completed without
+// succeeding, which requires the rollout to abort.
+func getAbortingBlueGreenAnalysisRun(prePromotionAr, postPromotionAr *v1alpha1.AnalysisRun) *v1alpha1.AnalysisRun {
+	for _, ar := range []*v1alpha1.AnalysisRun{prePromotionAr, postPromotionAr} {
+		if ar != nil && ar.Status != nil && ar.Status.Status.Completed() && ar.Status.Status != v1alpha1.AnalysisStatusSuccessful {
+			return ar
+		}
+	}
+	return nil
+}
+
 func calculateScaleUpPreviewCheckPoint(r *v1alpha1.Rollout, newRS *appsv1.ReplicaSet, activeRS *appsv1.ReplicaSet) bool {
 	newRSAvailableCount := replicasetutil.GetAvailableReplicaCountForReplicaSets([]*appsv1.ReplicaSet{newRS})
 	if r.Spec.Strategy.BlueGreenStrategy.PreviewReplicaCount != nil && newRSAvailableCount == *r.Spec.Strategy.BlueGreenStrategy.PreviewReplicaCount {
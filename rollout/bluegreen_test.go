@@ -162,10 +162,13 @@ func TestBlueGreenHandlePause(t *testing.T) {
 				"paused": true
 			},
 			"status": {
-				"pauseStartTime": "%s"
+				"pauseStartTime": "%s",
+				"pauseConditions": %s
 			}
 		}`
-		assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, metav1.Now().UTC().Format(time.RFC3339))), patch)
+		now := metav1.Now().UTC().Format(time.RFC3339)
+		pauseConditions := generatePauseConditionsPatch(now, v1alpha1.PauseReasonBlueGreenPause)
+		assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, now, pauseConditions)), patch)
 
 	})
 
@@ -316,7 +319,8 @@ func TestBlueGreenHandlePause(t *testing.T) {
 				"paused": null
 			},
 			"status": {
-				"pauseStartTime": null
+				"pauseStartTime": null,
+				"pauseConditions": null
 			}
 		}`
 		expectedPatch := calculatePatch(r2, expectedPatchWithoutSubs)
@@ -408,10 +412,12 @@ func TestBlueGreenHandlePause(t *testing.T) {
 				"paused": true
 			},
 			"status": {
-				"pauseStartTime": "%s"
+				"pauseStartTime": "%s",
+				"pauseConditions": %s
 			}
 		}`
-		expectedPatch := calculatePatch(r2, fmt.Sprintf(expectedPatchWithoutSubs, now))
+		pauseConditions := generatePauseConditionsPatch(now, v1alpha1.PauseReasonBlueGreenPause)
+		expectedPatch := calculatePatch(r2, fmt.Sprintf(expectedPatchWithoutSubs, now, pauseConditions))
 		patchIndex := f.expectPatchRolloutActionWithPatch(r2, expectedPatch)
 		f.run(getKey(r2, t))
 
@@ -661,7 +667,7 @@ func TestBlueGreenRolloutStatusHPAStatusFieldsNoActiveSelector(t *testing.T) {
 
 	c, _, _ := f.newController(noResyncPeriodFunc)
 
-	err := c.syncRolloutStatusBlueGreen([]*appsv1.ReplicaSet{}, rs, nil, activeSvc, ro, false)
+	err := c.syncRolloutStatusBlueGreen([]*appsv1.ReplicaSet{}, rs, nil, activeSvc, nil, ro, false)
 	assert.Nil(t, err)
 	assert.Len(t, f.client.Actions(), 1)
 	result := f.client.Actions()[0].(core.PatchAction).GetPatch()
@@ -1,6 +1,7 @@
 package rollout
 
 import (
+	"fmt"
 	"sort"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -54,7 +55,11 @@ func (c *RolloutController) rolloutCanary(rollout *v1alpha1.Rollout, rsList []*a
 		return err
 	}
 
-	if err := c.reconcileCanaryService(rollout, newRS); err != nil {
+	if err := c.reconcileStableAndCanaryService(rollout, newRS, stableRS); err != nil {
+		return err
+	}
+
+	if err := c.reconcileTrafficRouting(rollout, newRS, stableRS); err != nil {
 		return err
 	}
 
@@ -113,7 +118,7 @@ func (c *RolloutController) reconcileCanaryPause(rollout *v1alpha1.Rollout) bool
 		return false
 	}
 
-	if currentStep.Pause == nil {
+	if rollout.Status.Abort || currentStep.Pause == nil {
 		return false
 	}
 
@@ -206,7 +211,7 @@ func completedCurrentCanaryStep(olderRSs []*appsv1.ReplicaSet, newRS *appsv1.Rep
 		logCtx.Info("Rollout has reached the desired state for the correct weight")
 		return true
 	}
-	if currentStep.Experiment != nil && experiment != nil && conditions.ExperimentCompleted(experiment.Status) && !conditions.ExperimentTimeOut(experiment, experiment.Status) {
+	if currentStep.Experiment != nil && experiment != nil && conditions.ExperimentCompleted(experiment.Status) && !conditions.ExperimentTimeOut(experiment, experiment.Status) && experimentutil.AnalysesSuccessful(experiment) {
 		return true
 	}
 	analysisExistsAndCompleted := currentStepAr != nil && currentStepAr.Status != nil && currentStepAr.Status.Status.Completed()
@@ -269,6 +274,36 @@ func (c *RolloutController) syncRolloutStatusCanary(olderRSs []*appsv1.ReplicaSe
 		}
 
 	}
+	// Unlike the step AnalysisRun, the background AnalysisRun is kept in the status after it completes so that the
+	// controller does not create another one for the same ReplicaSet.
+	currBackgroundAr := analysisutil.GetCurrentBackgroundAnalysisRun(currArs)
+	if currBackgroundAr != nil {
+		newStatus.Canary.CurrentBackgroundAnalysisRun = currBackgroundAr.Name
+	}
+
+	abortingAr := getAbortingAnalysisRun(currStepAr, currBackgroundAr)
+	abortingExp := currExp != nil && experimentutil.HasFailed(currExp)
+	if r.Status.Abort || abortingAr != nil || abortingExp {
+		if !r.Status.Abort {
+			var msg string
+			if abortingAr != nil {
+				msg = fmt.Sprintf("Rollout aborted due to AnalysisRun '%s' completing %s", abortingAr.Name, abortingAr.Status.Status)
+			} else {
+				msg = fmt.Sprintf("Rollout aborted due to Experiment '%s' completing %s", currExp.Name, currExp.Status.Status)
+			}
+			logCtx.Info(msg)
+			c.recorder.Event(r, corev1.EventTypeWarning, conditions.RolloutAbortedReason, msg)
+		}
+		newStatus.Abort = true
+		// The AnalysisRuns are terminated by an aborted rollout and the steps start over if the rollout is retried
+		newStatus.Canary.CurrentStepAnalysisRun = ""
+		newStatus.Canary.CurrentBackgroundAnalysisRun = ""
+		if stepCount > 0 {
+			newStatus.CurrentStepIndex = pointer.Int32Ptr(0)
+		}
+		newStatus = c.calculateRolloutConditions(r, newStatus, allRSs, newRS, currExp, currArs)
+		return c.persistRolloutStatus(r, &newStatus, pointer.BoolPtr(false))
+	}
 
 	if !r.Spec.Paused {
 		if stepCount == 0 {
@@ -292,7 +327,6 @@ func (c *RolloutController) syncRolloutStatusCanary(olderRSs []*appsv1.ReplicaSe
 			return c.persistRolloutStatus(r, &newStatus, pointer.BoolPtr(false))
 		}
 
-		//TODO(dthomson): Add steps to store CurrentBackgroundAnalysisRun
 		if completedCurrentCanaryStep(olderRSs, newRS, stableRS, currExp, currStepAr, r) {
 			*currentStepIndex++
 			newStatus.CurrentStepIndex = currentStepIndex
@@ -313,8 +347,9 @@ func (c *RolloutController) syncRolloutStatusCanary(olderRSs []*appsv1.ReplicaSe
 	}
 
 	addPause := currentStep.Pause != nil
-	pauseStartTime, paused := calculatePauseStatus(r, newRS, addPause, currArs)
+	pauseStartTime, pauseConditions, paused := calculatePauseStatus(r, newRS, addPause, currArs)
 	newStatus.PauseStartTime = pauseStartTime
+	newStatus.PauseConditions = pauseConditions
 
 	newStatus.CurrentStepIndex = currentStepIndex
 	newStatus = c.calculateRolloutConditions(r, newStatus, allRSs, newRS, currExp, currArs)
@@ -359,3 +394,21 @@ func (c *RolloutController) reconcileCanaryReplicaSets(rollout *v1alpha1.Rollout
 	}
 	return false, nil
 }
+
+// getAbortingAnalysisRun returns the AnalysisRun that requires the rollout to abort. A step AnalysisRun aborts the
+// rollout when it fails or errors, while a background AnalysisRun also aborts the rollout when it is inconclusive.
+func getAbortingAnalysisRun(currentStepAr, currentBackgroundAr *v1alpha1.AnalysisRun) *v1alpha1.AnalysisRun {
+	if currentStepAr != nil && currentStepAr.Status != nil {
+		switch currentStepAr.Status.Status {
+		case v1alpha1.AnalysisStatusFailed, v1alpha1.AnalysisStatusError:
+			return currentStepAr
+		}
+	}
+	if currentBackgroundAr != nil && currentBackgroundAr.Status != nil {
+		switch currentBackgroundAr.Status.Status {
+		case v1alpha1.AnalysisStatusFailed, v1alpha1.AnalysisStatusError, v1alpha1.AnalysisStatusInconclusive:
+			return currentBackgroundAr
+		}
+	}
+	return nil
+}
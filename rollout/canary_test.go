@@ -107,12 +107,15 @@ func TestCanaryRolloutEnterPauseState(t *testing.T) {
 		},
 		"status":{
 			"pauseStartTime":"%s",
+			"pauseConditions": %s,
 			"conditions": %s
 		}
 	}`
 
+	now := metav1.Now().UTC().Format(time.RFC3339)
 	conditions := generateConditionsPatch(true, conditions.ReplicaSetUpdatedReason, r2, false)
-	expectedPatchWithoutObservedGen := fmt.Sprintf(expectedPatchTemplate, metav1.Now().UTC().Format(time.RFC3339), conditions)
+	pauseConditions := generatePauseConditionsPatch(now, v1alpha1.PauseReasonCanaryPauseStep)
+	expectedPatchWithoutObservedGen := fmt.Sprintf(expectedPatchTemplate, now, pauseConditions, conditions)
 	expectedPatch := calculatePatch(r2, expectedPatchWithoutObservedGen)
 	assert.Equal(t, expectedPatch, patch)
 }
@@ -472,6 +475,47 @@ func TestCanaryRolloutCreateNewReplicaWithCorrectWeight(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf(conditions.NewReplicaSetMessage, createdRS.Name), progessingCondition.Message)
 }
 
+func TestCanaryRolloutCreateNewReplicaWithAntiAffinity(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	steps := []v1alpha1.CanaryStep{{
+		SetWeight: int32Ptr(10),
+	}}
+	r1 := newCanaryRollout("foo", 10, nil, steps, int32Ptr(0), intstr.FromInt(1), intstr.FromInt(0))
+	r1.Spec.Strategy.CanaryStrategy.AntiAffinity = &v1alpha1.AntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1alpha1.RequiredDuringSchedulingIgnoredDuringExecution{},
+	}
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	r1.Status.Canary.StableRS = rs1PodHash
+	r2 := bumpVersion(r1)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.objects = append(f.objects, r2)
+
+	rs2 := newReplicaSetWithStatus(r2, 1, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1)
+	f.replicaSetLister = append(f.replicaSetLister, rs1)
+
+	createdRSIndex := f.expectCreateReplicaSetAction(rs2)
+	f.expectUpdateRolloutAction(r2)
+	f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+
+	createdRS := f.getCreatedReplicaSet(createdRSIndex)
+	assert.Equal(t, rs2.Name, createdRS.Name)
+	assert.Nil(t, r2.Spec.Template.Spec.Affinity)
+	terms := createdRS.Spec.Template.Spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, terms, 1)
+	assert.Equal(t, corev1.LabelHostname, terms[0].TopologyKey)
+	assert.Equal(t, []metav1.LabelSelectorRequirement{{
+		Key:      v1alpha1.DefaultRolloutUniqueLabelKey,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{rs1PodHash},
+	}}, terms[0].LabelSelector.MatchExpressions)
+}
+
 func TestCanaryRolloutScaleUpNewReplicaWithCorrectWeight(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -572,6 +616,39 @@ func TestCanaryRolloutScaleDownOldRs(t *testing.T) {
 	assert.Equal(t, expectedRS2, updatedRS)
 }
 
+func TestCanaryRolloutScaleDownNewRsWhenAborted(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	canarySvc := newService("canary", 80, nil)
+	steps := []v1alpha1.CanaryStep{{
+		SetWeight: int32Ptr(10),
+	}}
+	r1 := newCanaryRollout("foo", 10, nil, steps, int32Ptr(0), intstr.FromInt(1), intstr.FromInt(0))
+	r1.Spec.Strategy.CanaryStrategy.CanaryService = canarySvc.Name
+	r2 := bumpVersion(r1)
+
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	f.kubeobjects = append(f.kubeobjects, canarySvc, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	f.serviceLister = append(f.serviceLister, canarySvc)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 11, 1, 11, false)
+	r2.Status.Abort = true
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.objects = append(f.objects, r2)
+
+	f.expectPatchServiceAction(canarySvc, rs1PodHash)
+	updatedRSIndex := f.expectUpdateReplicaSetAction(rs2)
+	f.expectPatchRolloutAction(r2)
+	f.run(getKey(r2, t))
+
+	updatedRS := f.getUpdatedReplicaSet(updatedRSIndex)
+	assert.Equal(t, int32(0), *updatedRS.Spec.Replicas)
+}
+
 func TestRollBackToStable(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -778,11 +855,14 @@ func TestSyncRolloutsSetPauseStartTime(t *testing.T) {
 		},
 		"status":{
 			"pauseStartTime": "%s",
+			"pauseConditions": %s,
 			"conditions": %s
 		}
 	}`
+	now := metav1.Now().UTC().Format(time.RFC3339)
 	condtions := generateConditionsPatch(true, conditions.ReplicaSetUpdatedReason, r2, false)
-	expectedPatch := fmt.Sprintf(expectedPatchWithoutTime, metav1.Now().UTC().Format(time.RFC3339), condtions)
+	pauseConditions := generatePauseConditionsPatch(now, v1alpha1.PauseReasonCanaryPauseStep)
+	expectedPatch := fmt.Sprintf(expectedPatchWithoutTime, now, pauseConditions, condtions)
 
 	index := f.expectPatchRolloutActionWithPatch(r2, expectedPatch)
 	f.run(getKey(r2, t))
@@ -978,6 +1058,25 @@ func TestCanaryRolloutWithCanaryService(t *testing.T) {
 	f.run(getKey(rollout, t))
 }
 
+func TestCanaryRolloutWithStableService(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	stableSvc := newService("stable", 80, nil)
+	rollout := newCanaryRollout("foo", 0, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	rs := newReplicaSetWithStatus(rollout, 0, 0)
+	rollout.Spec.Strategy.CanaryStrategy.StableService = stableSvc.Name
+
+	f.rolloutLister = append(f.rolloutLister, rollout)
+	f.objects = append(f.objects, rollout)
+	f.kubeobjects = append(f.kubeobjects, stableSvc, rs)
+	f.serviceLister = append(f.serviceLister, stableSvc)
+
+	_ = f.expectPatchServiceAction(stableSvc, rollout.Status.CurrentPodHash)
+	_ = f.expectPatchRolloutAction(rollout)
+	f.run(getKey(rollout, t))
+}
+
 func TestCanaryRolloutWithInvalidCanaryServiceName(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -1046,3 +1145,85 @@ func TestCanaryRolloutScaleWhilePaused(t *testing.T) {
 	expectedPatch := calculatePatch(r2, OnlyObservedGenerationPatch)
 	assert.Equal(t, expectedPatch, patch)
 }
+
+func TestCalculatePauseStatusPauseConditions(t *testing.T) {
+	steps := []v1alpha1.CanaryStep{{
+		SetWeight: pointer.Int32Ptr(10),
+	}, {
+		Pause: &v1alpha1.RolloutPause{},
+	}}
+	inconclusiveAr := &v1alpha1.AnalysisRun{
+		Status: &v1alpha1.AnalysisRunStatus{
+			Status: v1alpha1.AnalysisStatusInconclusive,
+		},
+	}
+	reasons := func(pauseConditions []v1alpha1.PauseCondition) []v1alpha1.PauseReason {
+		var reasons []v1alpha1.PauseReason
+		for _, cond := range pauseConditions {
+			reasons = append(reasons, cond.Reason)
+		}
+		return reasons
+	}
+
+	t.Run("PauseStep", func(t *testing.T) {
+		r := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(1), intstr.FromInt(1), intstr.FromInt(0))
+		pauseStartTime, pauseConditions, paused := calculatePauseStatus(r, nil, true, nil)
+		assert.True(t, paused)
+		assert.NotNil(t, pauseStartTime)
+		assert.Equal(t, []v1alpha1.PauseReason{v1alpha1.PauseReasonCanaryPauseStep}, reasons(pauseConditions))
+		assert.Equal(t, *pauseStartTime, pauseConditions[0].StartTime)
+	})
+
+	t.Run("PauseStepWithInconclusiveAnalysisRun", func(t *testing.T) {
+		r := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(1), intstr.FromInt(1), intstr.FromInt(0))
+		_, pauseConditions, paused := calculatePauseStatus(r, nil, true, []*v1alpha1.AnalysisRun{inconclusiveAr})
+		assert.True(t, paused)
+		assert.Equal(t, []v1alpha1.PauseReason{v1alpha1.PauseReasonInconclusiveAnalysis, v1alpha1.PauseReasonCanaryPauseStep}, reasons(pauseConditions))
+	})
+
+	t.Run("UserPause", func(t *testing.T) {
+		r := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(1), intstr.FromInt(0))
+		r.Spec.Paused = true
+		pauseStartTime, pauseConditions, paused := calculatePauseStatus(r, nil, false, nil)
+		assert.True(t, paused)
+		assert.Nil(t, pauseStartTime)
+		assert.Equal(t, []v1alpha1.PauseReason{v1alpha1.PauseReasonUser}, reasons(pauseConditions))
+	})
+
+	t.Run("KeepRemainingConditionAfterPromote", func(t *testing.T) {
+		r := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(1), intstr.FromInt(1), intstr.FromInt(0))
+		earlier := metav1.NewTime(metav1.Now().Add(-1 * time.Minute))
+		r.Spec.Paused = true
+		r.Status.PauseStartTime = &earlier
+		r.Status.PauseConditions = []v1alpha1.PauseCondition{{
+			Reason:    v1alpha1.PauseReasonUser,
+			StartTime: earlier,
+		}}
+		_, pauseConditions, paused := calculatePauseStatus(r, nil, true, nil)
+		assert.True(t, paused)
+		assert.Equal(t, r.Status.PauseConditions, pauseConditions)
+	})
+
+	t.Run("AddConditionsToPausedRolloutWithoutConditions", func(t *testing.T) {
+		r := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(1), intstr.FromInt(1), intstr.FromInt(0))
+		earlier := metav1.NewTime(metav1.Now().Add(-1 * time.Minute))
+		r.Spec.Paused = true
+		r.Status.PauseStartTime = &earlier
+		_, pauseConditions, _ := calculatePauseStatus(r, nil, true, nil)
+		assert.Equal(t, []v1alpha1.PauseCondition{{
+			Reason:    v1alpha1.PauseReasonCanaryPauseStep,
+			StartTime: earlier,
+		}}, pauseConditions)
+	})
+
+	t.Run("ResumedRolloutHasNoConditions", func(t *testing.T) {
+		r := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(1), intstr.FromInt(0))
+		r.Status.PauseConditions = []v1alpha1.PauseCondition{{
+			Reason:    v1alpha1.PauseReasonUser,
+			StartTime: metav1.Now(),
+		}}
+		_, pauseConditions, paused := calculatePauseStatus(r, nil, false, nil)
+		assert.False(t, paused)
+		assert.Nil(t, pauseConditions)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	appsinformers "k8s.io/client-go/informers/apps/v1"
 	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -18,6 +19,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/kubernetes/cmd/kubeadm/app/util"
 	"k8s.io/kubernetes/pkg/controller"
 	"k8s.io/utils/pointer"
 
@@ -43,22 +45,29 @@ type RolloutController struct {
 	kubeclientset kubernetes.Interface
 	// argoprojclientset is a clientset for our own API group
 	argoprojclientset clientset.Interface
+	// dynamicclientset is a dynamic clientset for the service mesh resources (e.g. Istio VirtualServices)
+	dynamicclientset dynamic.Interface
 
-	replicaSetLister       appslisters.ReplicaSetLister
-	replicaSetSynced       cache.InformerSynced
-	rolloutsLister         listers.RolloutLister
-	rolloutsSynced         cache.InformerSynced
-	rolloutsIndexer        cache.Indexer
-	servicesLister         v1.ServiceLister
-	experimentsLister      listers.ExperimentLister
-	analysisRunLister      listers.AnalysisRunLister
-	analysisTemplateLister listers.AnalysisTemplateLister
-	metricsServer          *metrics.MetricsServer
+	replicaSetLister              appslisters.ReplicaSetLister
+	replicaSetSynced              cache.InformerSynced
+	deploymentsLister             appslisters.DeploymentLister
+	deploymentsSynced             cache.InformerSynced
+	rolloutsLister                listers.RolloutLister
+	rolloutsSynced                cache.InformerSynced
+	rolloutsIndexer               cache.Indexer
+	servicesLister                v1.ServiceLister
+	experimentsLister             listers.ExperimentLister
+	analysisRunLister             listers.AnalysisRunLister
+	analysisTemplateLister        listers.AnalysisTemplateLister
+	clusterAnalysisTemplateLister listers.ClusterAnalysisTemplateLister
+	metricsServer                 *metrics.MetricsServer
 
 	// used for unit testing
 	enqueueRollout      func(obj interface{})
 	enqueueRolloutAfter func(obj interface{}, duration time.Duration)
 
+	newTrafficRoutingReconciler func(r *v1alpha1.Rollout) TrafficRoutingReconciler
+
 	// workqueue is a rate limited work queue. This is used to queue work to be
 	// processed instead of performing it as soon as a change happens. This
 	// means we can ensure we only process a fixed amount of resources at a
@@ -76,10 +85,13 @@ type RolloutController struct {
 func NewRolloutController(
 	kubeclientset kubernetes.Interface,
 	argoprojclientset clientset.Interface,
+	dynamicclientset dynamic.Interface,
 	experimentInformer informers.ExperimentInformer,
 	analysisRunInformer informers.AnalysisRunInformer,
 	analysisTemplateInformer informers.AnalysisTemplateInformer,
+	clusterAnalysisTemplateInformer informers.ClusterAnalysisTemplateInformer,
 	replicaSetInformer appsinformers.ReplicaSetInformer,
+	deploymentsInformer appsinformers.DeploymentInformer,
 	servicesInformer coreinformers.ServiceInformer,
 	rolloutsInformer informers.RolloutInformer,
 	resyncPeriod time.Duration,
@@ -96,9 +108,12 @@ func NewRolloutController(
 	controller := &RolloutController{
 		kubeclientset:          kubeclientset,
 		argoprojclientset:      argoprojclientset,
+		dynamicclientset:       dynamicclientset,
 		replicaSetControl:      replicaSetControl,
 		replicaSetLister:       replicaSetInformer.Lister(),
 		replicaSetSynced:       replicaSetInformer.Informer().HasSynced,
+		deploymentsLister:      deploymentsInformer.Lister(),
+		deploymentsSynced:      deploymentsInformer.Informer().HasSynced,
 		rolloutsIndexer:        rolloutsInformer.Informer().GetIndexer(),
 		rolloutsLister:         rolloutsInformer.Lister(),
 		rolloutsSynced:         rolloutsInformer.Informer().HasSynced,
@@ -112,12 +127,17 @@ func NewRolloutController(
 		resyncPeriod:           resyncPeriod,
 		metricsServer:          metricsServer,
 	}
+	// The informer is nil when the controller is limited to a namespace and cannot watch the ClusterAnalysisTemplates
+	if clusterAnalysisTemplateInformer != nil {
+		controller.clusterAnalysisTemplateLister = clusterAnalysisTemplateInformer.Lister()
+	}
 	controller.enqueueRollout = func(obj interface{}) {
 		controllerutil.EnqueueRateLimited(obj, rolloutWorkQueue)
 	}
 	controller.enqueueRolloutAfter = func(obj interface{}, duration time.Duration) {
 		controllerutil.EnqueueAfter(obj, duration, rolloutWorkQueue)
 	}
+	controller.newTrafficRoutingReconciler = controller.NewTrafficRoutingReconciler
 	log.Info("Setting up event handlers")
 	// Set up an event handler for when rollout resources change
 	rolloutsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -134,6 +154,28 @@ func NewRolloutController(
 		},
 	})
 
+	util.CheckErr(rolloutsInformer.Informer().AddIndexers(cache.Indexers{
+		workloadRefIndexName: func(obj interface{}) ([]string, error) {
+			if rollout, ok := obj.(*v1alpha1.Rollout); ok {
+				return getWorkloadRefKeys(rollout), nil
+			}
+			return []string{}, nil
+		},
+	}))
+
+	deploymentsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueWorkloadRefRollouts,
+		UpdateFunc: func(old, new interface{}) {
+			newDeployment := new.(*appsv1.Deployment)
+			oldDeployment := old.(*appsv1.Deployment)
+			if newDeployment.ResourceVersion == oldDeployment.ResourceVersion {
+				return
+			}
+			controller.enqueueWorkloadRefRollouts(new)
+		},
+		DeleteFunc: controller.enqueueWorkloadRefRollouts,
+	})
+
 	replicaSetInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			controllerutil.EnqueueParentObject(obj, register.RolloutKind, controller.enqueueRollout)
@@ -238,6 +280,16 @@ func (c *RolloutController) syncHandler(key string) error {
 
 	prevCond := conditions.GetRolloutCondition(rollout.Status, v1alpha1.InvalidSpec)
 	invalidSpecCond := conditions.VerifyRolloutSpec(r, prevCond)
+	if invalidSpecCond == nil {
+		invalidSpecCond = conditions.VerifyRolloutWorkloadRef(r, prevCond, func(name string) (*appsv1.Deployment, error) {
+			return c.deploymentsLister.Deployments(r.Namespace).Get(name)
+		})
+	}
+	if invalidSpecCond == nil {
+		invalidSpecCond = conditions.VerifyRolloutAnalysisTemplates(r, prevCond, func(ref v1alpha1.RolloutAnalysisTemplate) (*v1alpha1.AnalysisTemplateSpec, error) {
+			return c.getAnalysisTemplateSpecFromRef(r, ref)
+		})
+	}
 	if invalidSpecCond != nil {
 		logutil.WithRollout(r).Error("Spec submitted is invalid")
 		generation := conditions.ComputeGenerationHash(r.Spec)
@@ -258,6 +310,17 @@ func (c *RolloutController) syncHandler(key string) error {
 		return nil
 	}
 
+	// Use the pod template of the Deployment referenced by the workloadRef as the template of the Rollout
+	err = c.resolveWorkloadRef(r)
+	if err != nil {
+		return err
+	}
+
+	err = c.scaleDownWorkloadRef(r)
+	if err != nil {
+		return err
+	}
+
 	// List ReplicaSets owned by this Rollout, while reconciling ControllerRef
 	// through adoption/orphaning.
 	rsList, err := c.getReplicaSetsForRollouts(r)
@@ -265,6 +328,11 @@ func (c *RolloutController) syncHandler(key string) error {
 		return err
 	}
 
+	// Restore the template of a previous revision before the rollout progresses, including when it is paused
+	if r.Spec.RollbackTo != nil {
+		return c.rollback(r, rsList)
+	}
+
 	err = c.checkPausedConditions(r)
 	if err != nil {
 		return err
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -22,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	kubeinformers "k8s.io/client-go/informers"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 	core "k8s.io/client-go/testing"
@@ -61,12 +63,14 @@ type fixture struct {
 	client     *fake.Clientset
 	kubeclient *k8sfake.Clientset
 	// Objects to put in the store.
-	rolloutLister          []*v1alpha1.Rollout
-	experimentLister       []*v1alpha1.Experiment
-	analysisRunLister      []*v1alpha1.AnalysisRun
-	analysisTemplateLister []*v1alpha1.AnalysisTemplate
-	replicaSetLister       []*appsv1.ReplicaSet
-	serviceLister          []*corev1.Service
+	rolloutLister                 []*v1alpha1.Rollout
+	experimentLister              []*v1alpha1.Experiment
+	analysisRunLister             []*v1alpha1.AnalysisRun
+	analysisTemplateLister        []*v1alpha1.AnalysisTemplate
+	clusterAnalysisTemplateLister []*v1alpha1.ClusterAnalysisTemplate
+	replicaSetLister              []*appsv1.ReplicaSet
+	serviceLister                 []*corev1.Service
+	deploymentLister              []*appsv1.Deployment
 	// Actions expected to happen on the client.
 	kubeactions []core.Action
 	actions     []core.Action
@@ -190,6 +194,10 @@ func newProgressingCondition(reason string, resourceObj runtime.Object) (v1alpha
 		msg = conditions.ResumeRolloutMessage
 		status = corev1.ConditionUnknown
 	}
+	if reason == conditions.RolloutAbortedReason {
+		msg = conditions.RolloutAbortedMessage
+		status = corev1.ConditionFalse
+	}
 
 	condition := v1alpha1.RolloutCondition{
 		LastTransitionTime: metav1.Now(),
@@ -236,6 +244,14 @@ func generateConditionsPatch(available bool, progressingReason string, progressi
 	return fmt.Sprintf("[%s, %s]", progressingConditon, availableCondition)
 }
 
+func generatePauseConditionsPatch(startTime string, reasons ...v1alpha1.PauseReason) string {
+	var pauseConditions []string
+	for _, reason := range reasons {
+		pauseConditions = append(pauseConditions, fmt.Sprintf(`{"reason": "%s", "startTime": "%s"}`, reason, startTime))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(pauseConditions, ", "))
+}
+
 // func updateBlueGreenRolloutStatus(r *v1alpha1.Rollout, preview, active string, availableReplicas, updatedReplicas, hpaReplicas int32, pause bool, available bool, progressingStatus string) *v1alpha1.Rollout {
 func updateBlueGreenRolloutStatus(r *v1alpha1.Rollout, preview, active string, availableReplicas, updatedReplicas, totalReplicas, hpaReplicas int32, pause bool, available bool) *v1alpha1.Rollout {
 	newRollout := updateBaseRolloutStatus(r, availableReplicas, updatedReplicas, totalReplicas, hpaReplicas, pause)
@@ -266,6 +282,11 @@ func updateBaseRolloutStatus(r *v1alpha1.Rollout, availableReplicas, updatedRepl
 		newRollout.Spec.Paused = pause
 		now := metav1.Now()
 		newRollout.Status.PauseStartTime = &now
+		reason := v1alpha1.PauseReasonCanaryPauseStep
+		if r.Spec.Strategy.BlueGreenStrategy != nil {
+			reason = v1alpha1.PauseReasonBlueGreenPause
+		}
+		newRollout.Status.PauseConditions = []v1alpha1.PauseCondition{{Reason: reason, StartTime: now}}
 	}
 	return newRollout
 }
@@ -351,11 +372,13 @@ func (f *fixture) newController(resync resyncFunc) (*RolloutController, informer
 	rolloutWorkqueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Rollouts")
 	serviceWorkqueue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "Services")
 
-	c := NewRolloutController(f.kubeclient, f.client,
+	c := NewRolloutController(f.kubeclient, f.client, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
 		i.Argoproj().V1alpha1().Experiments(),
 		i.Argoproj().V1alpha1().AnalysisRuns(),
 		i.Argoproj().V1alpha1().AnalysisTemplates(),
+		i.Argoproj().V1alpha1().ClusterAnalysisTemplates(),
 		k8sI.Apps().V1().ReplicaSets(),
+		k8sI.Apps().V1().Deployments(),
 		k8sI.Core().V1().Services(),
 		i.Argoproj().V1alpha1().Rollouts(),
 		resync(),
@@ -399,9 +422,15 @@ func (f *fixture) newController(resync resyncFunc) (*RolloutController, informer
 	for _, s := range f.serviceLister {
 		k8sI.Core().V1().Services().Informer().GetIndexer().Add(s)
 	}
+	for _, d := range f.deploymentLister {
+		k8sI.Apps().V1().Deployments().Informer().GetIndexer().Add(d)
+	}
 	for _, at := range f.analysisTemplateLister {
 		i.Argoproj().V1alpha1().AnalysisTemplates().Informer().GetIndexer().Add(at)
 	}
+	for _, at := range f.clusterAnalysisTemplateLister {
+		i.Argoproj().V1alpha1().ClusterAnalysisTemplates().Informer().GetIndexer().Add(at)
+	}
 	for _, ar := range f.analysisRunLister {
 		i.Argoproj().V1alpha1().AnalysisRuns().Informer().GetIndexer().Add(ar)
 	}
@@ -527,12 +556,16 @@ func filterInformerActions(actions []core.Action) []core.Action {
 			action.Matches("watch", "analysisruns") ||
 			action.Matches("list", "analysistemplates") ||
 			action.Matches("watch", "analysistemplates") ||
+			action.Matches("list", "clusteranalysistemplates") ||
+			action.Matches("watch", "clusteranalysistemplates") ||
 			action.Matches("list", "rollouts") ||
 			action.Matches("watch", "rollouts") ||
 			action.Matches("list", "replicaSets") ||
 			action.Matches("watch", "replicaSets") ||
 			action.Matches("list", "services") ||
-			action.Matches("watch", "services") {
+			action.Matches("watch", "services") ||
+			action.Matches("list", "deployments") ||
+			action.Matches("watch", "deployments") {
 			continue
 		}
 		ret = append(ret, action)
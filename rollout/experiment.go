@@ -80,6 +80,9 @@ func GetExperimentFromTemplate(r *v1alpha1.Rollout, stableRS, newRS *appsv1.Repl
 		}
 		experiment.Spec.Templates = append(experiment.Spec.Templates, template)
 	}
+	for i := range step.Analyses {
+		experiment.Spec.Analyses = append(experiment.Spec.Analyses, *step.Analyses[i].DeepCopy())
+	}
 
 	return experiment, nil
 }
@@ -116,7 +119,12 @@ func (c *RolloutController) reconcileExperiments(rollout *v1alpha1.Rollout, stab
 	}
 
 	step, _ := replicasetutil.GetCurrentCanaryStep(rollout)
-	if step == nil || step.Experiment == nil {
+	if rollout.Status.Abort && currentEx != nil && currentEx.Status.Running != nil && *currentEx.Status.Running {
+		logCtx.Infof("Canceling experiment '%s' owned by aborted rollout", currentEx.Name)
+		_, err := c.argoprojclientset.ArgoprojV1alpha1().Experiments(currentEx.Namespace).Patch(currentEx.Name, patchtypes.MergePatchType, []byte(cancelExperimentPatch))
+		return nil, err
+	}
+	if rollout.Status.Abort || step == nil || step.Experiment == nil {
 		return nil, nil
 	}
 	if currentEx == nil {
@@ -245,6 +245,113 @@ func TestRolloutExperimentFinishedIncrementStep(t *testing.T) {
 	assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, generatedConditions)), patch)
 }
 
+func TestRolloutExperimentWaitForAnalysesBeforeIncrementStep(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	steps := []v1alpha1.CanaryStep{{
+		Experiment: &v1alpha1.RolloutExperimentStep{
+			Templates: []v1alpha1.RolloutExperimentTemplate{{
+				Name:     "stable-template",
+				SpecRef:  v1alpha1.StableSpecRef,
+				Replicas: int32(1),
+			}},
+			Analyses: []v1alpha1.ExperimentAnalysisTemplateRef{{
+				Name:         "test",
+				TemplateName: "test",
+			}},
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+	ex, _ := GetExperimentFromTemplate(r2, rs1, rs2)
+	ex.Status.Running = pointer.BoolPtr(false)
+	now := metav1.Now()
+	ex.Status.AvailableAt = &now
+	ex.Status.Status = v1alpha1.AnalysisStatusRunning
+	ex.Name = fmt.Sprintf("%s-%s", ex.GenerateName, MockGeneratedNameSuffix)
+	r2.Status.Canary.CurrentExperiment = ex.Name
+	progressingCondition, _ := newProgressingCondition(conditions.ReplicaSetUpdatedReason, rs2)
+	conditions.SetRolloutCondition(&r2.Status, progressingCondition)
+	availableCondition, _ := newAvailableCondition(true)
+	conditions.SetRolloutCondition(&r2.Status, availableCondition)
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.experimentLister = append(f.experimentLister, ex)
+	f.objects = append(f.objects, r2, ex)
+
+	patchIndex := f.expectPatchRolloutAction(r1)
+	f.run(getKey(r2, t))
+
+	patch := f.getPatchedRollout(patchIndex)
+	assert.Equal(t, calculatePatch(r2, OnlyObservedGenerationPatch), patch)
+}
+
+func TestRolloutAbortedDueToFailedExperimentAnalysis(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	steps := []v1alpha1.CanaryStep{{
+		Experiment: &v1alpha1.RolloutExperimentStep{
+			Templates: []v1alpha1.RolloutExperimentTemplate{{
+				Name:     "stable-template",
+				SpecRef:  v1alpha1.StableSpecRef,
+				Replicas: int32(1),
+			}},
+			Analyses: []v1alpha1.ExperimentAnalysisTemplateRef{{
+				Name:         "test",
+				TemplateName: "test",
+			}},
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	f.kubeobjects = append(f.kubeobjects, rs1, rs2)
+	f.replicaSetLister = append(f.replicaSetLister, rs1, rs2)
+	rs1PodHash := rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	r2 = updateCanaryRolloutStatus(r2, rs1PodHash, 1, 0, 1, false)
+	ex, _ := GetExperimentFromTemplate(r2, rs1, rs2)
+	ex.Status.Running = pointer.BoolPtr(false)
+	now := metav1.Now()
+	ex.Status.AvailableAt = &now
+	ex.Status.Status = v1alpha1.AnalysisStatusFailed
+	ex.Name = fmt.Sprintf("%s-%s", ex.GenerateName, MockGeneratedNameSuffix)
+	r2.Status.Canary.CurrentExperiment = ex.Name
+
+	f.rolloutLister = append(f.rolloutLister, r2)
+	f.experimentLister = append(f.experimentLister, ex)
+	f.objects = append(f.objects, r2, ex)
+
+	patchIndex := f.expectPatchRolloutAction(r1)
+	f.run(getKey(r2, t))
+	patch := f.getPatchedRollout(patchIndex)
+	expectedPatch := `{
+		"status": {
+			"canary": {
+				"currentExperiment": null
+			},
+			"conditions": %s,
+			"abort": true
+		}
+	}`
+	condition := generateConditionsPatch(true, conditions.RolloutAbortedReason, r2, false)
+	assert.Equal(t, calculatePatch(r2, fmt.Sprintf(expectedPatch, condition)), patch)
+}
+
 func TestRolloutDoNotCreateExperimentWithoutNewRS(t *testing.T) {
 	f := newFixture(t)
 	defer f.Close()
@@ -364,3 +471,36 @@ func TestGetExperimentFromTemplate(t *testing.T) {
 	assert.Nil(t, err)
 
 }
+
+func TestGetExperimentFromTemplateWithAnalyses(t *testing.T) {
+	analyses := []v1alpha1.ExperimentAnalysisTemplateRef{{
+		Name:         "mann-whitney",
+		TemplateName: "mann-whitney-template",
+		Arguments: []v1alpha1.Argument{{
+			Name:  "stable-hash",
+			Value: "{{templates.stable-template.podTemplateHash}}",
+		}},
+		RequiredForCompletion: true,
+	}}
+	steps := []v1alpha1.CanaryStep{{
+		Experiment: &v1alpha1.RolloutExperimentStep{
+			Templates: []v1alpha1.RolloutExperimentTemplate{{
+				Name:     "stable-template",
+				SpecRef:  v1alpha1.StableSpecRef,
+				Replicas: int32(1),
+			}},
+			Analyses: analyses,
+		},
+	}}
+
+	r1 := newCanaryRollout("foo", 1, nil, steps, pointer.Int32Ptr(0), intstr.FromInt(0), intstr.FromInt(1))
+	r2 := bumpVersion(r1)
+	rs1 := newReplicaSetWithStatus(r1, 1, 1)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+
+	ex, err := GetExperimentFromTemplate(r2, rs1, rs2)
+	assert.Nil(t, err)
+	assert.Equal(t, analyses, ex.Spec.Analyses)
+	ex.Spec.Analyses[0].Arguments[0].Value = "modified"
+	assert.Equal(t, "{{templates.stable-template.podTemplateHash}}", analyses[0].Arguments[0].Value)
+}
@@ -0,0 +1,134 @@
+package rollout
+
+import (
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	patchtypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/controller"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+const (
+	pauseDeploymentPatch = `{"spec":{"paused":true}}`
+	addPodHashLabelPatch = `{"metadata":{"labels":{"%s":"%s"}}}`
+	adoptReplicaSetPatch = `{
+	"metadata": {
+		"labels": {
+			"%[1]s": "%[2]s"
+		},
+		"ownerReferences": %[3]s
+	},
+	"spec": {
+		"template": {
+			"metadata": {
+				"labels": {
+					"%[1]s": "%[2]s"
+				}
+			}
+		}
+	}
+}`
+)
+
+// migrateDeploymentReplicaSet adopts the ReplicaSet of the Deployment referenced by migrateFromDeployment when its
+// pod template matches the template of the rollout. The Deployment is paused so it does not create a replacement for
+// the ReplicaSet, and the ReplicaSet, then its pods, are labelled with the pod template hash of the rollout. Nothing is
+// migrated once the rollout owns a ReplicaSet. It returns the list of ReplicaSets with the adopted ReplicaSet updated.
+func (c *RolloutController) migrateDeploymentReplicaSet(r *v1alpha1.Rollout, rsList []*appsv1.ReplicaSet) ([]*appsv1.ReplicaSet, error) {
+	if r.Spec.MigrateFromDeployment == "" {
+		return rsList, nil
+	}
+	for _, rs := range rsList {
+		if metav1.IsControlledBy(rs, r) {
+			return rsList, nil
+		}
+	}
+	logCtx := logutil.WithRollout(r)
+	deployment, err := c.deploymentsLister.Deployments(r.Namespace).Get(r.Spec.MigrateFromDeployment)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			logCtx.Warnf("Deployment '%s' to migrate from not found", r.Spec.MigrateFromDeployment)
+			return rsList, nil
+		}
+		return nil, err
+	}
+
+	index := -1
+	for i, rs := range rsList {
+		if metav1.IsControlledBy(rs, deployment) && rs.DeletionTimestamp == nil && replicasetutil.PodTemplateEqualIgnoreHash(&rs.Spec.Template, &r.Spec.Template) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		logCtx.Infof("Deployment '%s' has no ReplicaSet matching the rollout's template", deployment.Name)
+		return rsList, nil
+	}
+	rs := rsList[index]
+
+	if !deployment.Spec.Paused {
+		logCtx.Infof("Pausing Deployment '%s' to migrate its ReplicaSet '%s'", deployment.Name, rs.Name)
+		_, err = c.kubeclientset.AppsV1().Deployments(deployment.Namespace).Patch(deployment.Name, patchtypes.MergePatchType, []byte(pauseDeploymentPatch))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	podHash := controller.ComputeHash(&r.Spec.Template, r.Status.CollisionCount)
+	ownerReferences, err := json.Marshal([]metav1.OwnerReference{*metav1.NewControllerRef(r, controllerKind)})
+	if err != nil {
+		return nil, err
+	}
+	patch := fmt.Sprintf(adoptReplicaSetPatch, v1alpha1.DefaultRolloutUniqueLabelKey, podHash, string(ownerReferences))
+	adoptedRS, err := c.kubeclientset.AppsV1().ReplicaSets(rs.Namespace).Patch(rs.Name, patchtypes.MergePatchType, []byte(patch))
+	if err != nil {
+		return nil, err
+	}
+	// The existing pods are labelled once the template of the ReplicaSet carries the label, so the pods it creates
+	// in the meantime are labelled as well
+	err = c.addPodHashLabel(adoptedRS, podHash)
+	if err != nil {
+		return nil, err
+	}
+	msg := fmt.Sprintf("Adopted replica set %s from deployment %s", rs.Name, deployment.Name)
+	logCtx.Info(msg)
+	c.recorder.Event(r, corev1.EventTypeNormal, "MigratedReplicaSet", msg)
+
+	migratedRSList := make([]*appsv1.ReplicaSet, len(rsList))
+	copy(migratedRSList, rsList)
+	migratedRSList[index] = adoptedRS
+	return migratedRSList, nil
+}
+
+// addPodHashLabel labels the pods of the ReplicaSet with the pod template hash of the rollout so the services managed
+// by the rollout select them
+func (c *RolloutController) addPodHashLabel(rs *appsv1.ReplicaSet, podHash string) error {
+	selector, err := metav1.LabelSelectorAsSelector(rs.Spec.Selector)
+	if err != nil {
+		return err
+	}
+	pods, err := c.kubeclientset.CoreV1().Pods(rs.Namespace).List(metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return err
+	}
+	patch := fmt.Sprintf(addPodHashLabelPatch, v1alpha1.DefaultRolloutUniqueLabelKey, podHash)
+	for i := range pods.Items {
+		pod := pods.Items[i]
+		if !metav1.IsControlledBy(&pod, rs) || pod.Labels[v1alpha1.DefaultRolloutUniqueLabelKey] == podHash {
+			continue
+		}
+		_, err = c.kubeclientset.CoreV1().Pods(pod.Namespace).Patch(pod.Name, patchtypes.MergePatchType, []byte(patch))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package rollout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	core "k8s.io/client-go/testing"
+	corev1defaults "k8s.io/kubernetes/pkg/apis/core/v1"
+	"k8s.io/kubernetes/pkg/controller"
+	"k8s.io/utils/pointer"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+// newMigrationFixture returns a rollout migrated from a Deployment along with the Deployment, its ReplicaSet
+// running the rollout's template and the pod of the ReplicaSet
+func newMigrationFixture() (*v1alpha1.Rollout, *appsv1.Deployment, *appsv1.ReplicaSet, *corev1.Pod) {
+	r := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	r.Spec.MigrateFromDeployment = "foo-deploy"
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Spec.MigrateFromDeployment,
+			Namespace: r.Namespace,
+			UID:       uuid.NewUUID(),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(1),
+			Selector: r.Spec.Selector.DeepCopy(),
+			Template: *r.Spec.Template.DeepCopy(),
+		},
+	}
+	deploymentHash := controller.ComputeHash(&deployment.Spec.Template, nil)
+	labels := map[string]string{appsv1.DefaultDeploymentUniqueLabelKey: deploymentHash}
+	for k, v := range r.Spec.Selector.MatchLabels {
+		labels[k] = v
+	}
+	rsKind := appsv1.SchemeGroupVersion.WithKind("ReplicaSet")
+	deploymentKind := appsv1.SchemeGroupVersion.WithKind("Deployment")
+	// The ReplicaSet returned by the API server has the defaults of the pod template set
+	podTemplate := corev1.PodTemplate{
+		Template: *deployment.Spec.Template.DeepCopy(),
+	}
+	corev1defaults.SetObjectDefaults_PodTemplate(&podTemplate)
+	template := podTemplate.Template
+	template.Labels = labels
+	rs := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            deployment.Name + "-" + deploymentHash,
+			Namespace:       r.Namespace,
+			UID:             uuid.NewUUID(),
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(deployment, deploymentKind)},
+		},
+		Spec: appsv1.ReplicaSetSpec{
+			Replicas: pointer.Int32Ptr(1),
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: template,
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            rs.Name + "-abcde",
+			Namespace:       r.Namespace,
+			Labels:          labels,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(rs, rsKind)},
+		},
+	}
+	return r, deployment, rs, pod
+}
+
+func TestMigrateDeploymentReplicaSet(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r, deployment, rs, pod := newMigrationFixture()
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.replicaSetLister = append(f.replicaSetLister, rs)
+	f.kubeobjects = append(f.kubeobjects, deployment, rs, pod)
+	c, _, _ := f.newController(noResyncPeriodFunc)
+
+	rsList, err := c.migrateDeploymentReplicaSet(r, []*appsv1.ReplicaSet{rs})
+	assert.NoError(t, err)
+	podHash := controller.ComputeHash(&r.Spec.Template, r.Status.CollisionCount)
+
+	actions := filterInformerActions(f.kubeclient.Actions())
+	assert.Len(t, actions, 4)
+	assert.True(t, actions[0].Matches("patch", "deployments"))
+	assert.Equal(t, pauseDeploymentPatch, string(actions[0].(core.PatchAction).GetPatch()))
+	// The ReplicaSet is adopted before its pods are labelled
+	assert.True(t, actions[1].Matches("patch", "replicasets"))
+	assert.True(t, actions[2].Matches("list", "pods"))
+	assert.True(t, actions[3].Matches("patch", "pods"))
+	assert.Equal(t, pod.Name, actions[3].(core.PatchAction).GetName())
+
+	assert.Len(t, rsList, 1)
+	adoptedRS := rsList[0]
+	assert.True(t, metav1.IsControlledBy(adoptedRS, r))
+	assert.Len(t, adoptedRS.OwnerReferences, 1)
+	assert.Equal(t, podHash, adoptedRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey])
+	assert.Equal(t, podHash, adoptedRS.Spec.Template.Labels[v1alpha1.DefaultRolloutUniqueLabelKey])
+	assert.Equal(t, rs.Spec.Selector, adoptedRS.Spec.Selector)
+	// The ReplicaSet in the informer cache is not modified
+	assert.True(t, metav1.IsControlledBy(rs, deployment))
+
+	patchedPod, err := f.kubeclient.CoreV1().Pods(pod.Namespace).Get(pod.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, podHash, patchedPod.Labels[v1alpha1.DefaultRolloutUniqueLabelKey])
+}
+
+func TestMigrateDeploymentReplicaSetClaimedByRollout(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r, deployment, rs, pod := newMigrationFixture()
+	deployment.Spec.Paused = true
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.replicaSetLister = append(f.replicaSetLister, rs)
+	f.kubeobjects = append(f.kubeobjects, deployment, rs, pod)
+	c, _, _ := f.newController(noResyncPeriodFunc)
+
+	rsList, err := c.getReplicaSetsForRollouts(r)
+	assert.NoError(t, err)
+	assert.Len(t, rsList, 1)
+	assert.True(t, metav1.IsControlledBy(rsList[0], r))
+
+	// The paused Deployment is not patched
+	for _, action := range filterInformerActions(f.kubeclient.Actions()) {
+		assert.False(t, action.Matches("patch", "deployments"))
+	}
+}
+
+func TestMigrateDeploymentReplicaSetSkipped(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *v1alpha1.Rollout, deployment *appsv1.Deployment, rs *appsv1.ReplicaSet) []*appsv1.ReplicaSet
+	}{
+		{
+			name: "NoMigration",
+			modify: func(r *v1alpha1.Rollout, deployment *appsv1.Deployment, rs *appsv1.ReplicaSet) []*appsv1.ReplicaSet {
+				r.Spec.MigrateFromDeployment = ""
+				return []*appsv1.ReplicaSet{rs}
+			},
+		},
+		{
+			name: "RolloutOwnsReplicaSet",
+			modify: func(r *v1alpha1.Rollout, deployment *appsv1.Deployment, rs *appsv1.ReplicaSet) []*appsv1.ReplicaSet {
+				return []*appsv1.ReplicaSet{rs, newReplicaSet(r, 1)}
+			},
+		},
+		{
+			name: "DeploymentNotFound",
+			modify: func(r *v1alpha1.Rollout, deployment *appsv1.Deployment, rs *appsv1.ReplicaSet) []*appsv1.ReplicaSet {
+				r.Spec.MigrateFromDeployment = "not-found"
+				return []*appsv1.ReplicaSet{rs}
+			},
+		},
+		{
+			name: "TemplateMismatch",
+			modify: func(r *v1alpha1.Rollout, deployment *appsv1.Deployment, rs *appsv1.ReplicaSet) []*appsv1.ReplicaSet {
+				r.Spec.Template.Spec.Containers[0].Image = "foo/bar2"
+				return []*appsv1.ReplicaSet{rs}
+			},
+		},
+		{
+			name: "ReplicaSetNotOwnedByDeployment",
+			modify: func(r *v1alpha1.Rollout, deployment *appsv1.Deployment, rs *appsv1.ReplicaSet) []*appsv1.ReplicaSet {
+				rs.OwnerReferences = nil
+				return []*appsv1.ReplicaSet{rs}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.Close()
+
+			r, deployment, rs, _ := newMigrationFixture()
+			rsList := test.modify(r, deployment, rs)
+			f.deploymentLister = append(f.deploymentLister, deployment)
+			c, _, _ := f.newController(noResyncPeriodFunc)
+
+			migratedRSList, err := c.migrateDeploymentReplicaSet(r, rsList)
+			assert.NoError(t, err)
+			assert.Equal(t, rsList, migratedRSList)
+			assert.Len(t, filterInformerActions(f.kubeclient.Actions()), 0)
+		})
+	}
+}
@@ -45,7 +45,7 @@ func (c *RolloutController) checkEnqueueRolloutDuringWait(rollout *v1alpha1.Roll
 
 // calculatePauseStatus finds the fields related to a pause step for a rollout. If the pause is nil,
 // the rollout will use the previous values
-func calculatePauseStatus(rollout *v1alpha1.Rollout, newRS *appsv1.ReplicaSet, addPause bool, currArs []*v1alpha1.AnalysisRun) (*metav1.Time, bool) {
+func calculatePauseStatus(rollout *v1alpha1.Rollout, newRS *appsv1.ReplicaSet, addPause bool, currArs []*v1alpha1.AnalysisRun) (*metav1.Time, []v1alpha1.PauseCondition, bool) {
 	logCtx := logutil.WithRollout(rollout)
 	pauseStartTime := rollout.Status.PauseStartTime
 	paused := rollout.Spec.Paused
@@ -53,7 +53,7 @@ func calculatePauseStatus(rollout *v1alpha1.Rollout, newRS *appsv1.ReplicaSet, a
 		pauseStartTime = nil
 	}
 	if rollout.Spec.Strategy.BlueGreenStrategy != nil && defaults.GetAutoPromotionEnabledOrDefault(rollout) {
-		return nil, false
+		return nil, nil, false
 	}
 
 	pauseForInconclusiveAnalysisRun := false
@@ -64,18 +64,32 @@ func calculatePauseStatus(rollout *v1alpha1.Rollout, newRS *appsv1.ReplicaSet, a
 		}
 	}
 
+	var reasons []v1alpha1.PauseReason
+	if pauseForInconclusiveAnalysisRun {
+		reasons = append(reasons, v1alpha1.PauseReasonInconclusiveAnalysis)
+	}
+	if addPause {
+		if rollout.Spec.Strategy.BlueGreenStrategy != nil {
+			reasons = append(reasons, v1alpha1.PauseReasonBlueGreenPause)
+		} else {
+			reasons = append(reasons, v1alpha1.PauseReasonCanaryPauseStep)
+		}
+	}
+
+	startedPause := false
 	if addPause || pauseForInconclusiveAnalysisRun {
 		if pauseStartTime == nil {
 			now := metav1.Now()
 			logCtx.Infof("Setting PauseStartTime to %s", now.UTC().Format(time.RFC3339))
 			pauseStartTime = &now
 			paused = true
+			startedPause = true
 		}
 	}
 
 	if rollout.Spec.Strategy.BlueGreenStrategy != nil {
 		if reconcileBlueGreenTemplateChange(rollout, newRS) {
-			return nil, false
+			return nil, nil, false
 		}
 	}
 
@@ -85,10 +99,53 @@ func calculatePauseStatus(rollout *v1alpha1.Rollout, newRS *appsv1.ReplicaSet, a
 			autoPromoteActiveServiceDelaySeconds := *rollout.Spec.Strategy.BlueGreenStrategy.AutoPromotionSeconds
 			switchDeadline := pauseStartTime.Add(time.Duration(autoPromoteActiveServiceDelaySeconds) * time.Second)
 			if now.After(switchDeadline) {
-				return nil, false
+				return nil, nil, false
 			}
-			return pauseStartTime, true
 		}
 	}
-	return pauseStartTime, paused
+	return pauseStartTime, calculatePauseConditions(rollout, reasons, pauseStartTime, paused, startedPause), paused
+}
+
+// calculatePauseConditions returns the reasons why the rollout is paused. The controller only adds conditions when it
+// starts a pause (or the rollout has none yet), so a condition cleared by a promote is not added back while the
+// rollout stays paused for another reason. A rollout paused without a reason from the controller was paused by a user.
+// New conditions start at the pause start time if the rollout has one.
+func calculatePauseConditions(rollout *v1alpha1.Rollout, reasons []v1alpha1.PauseReason, pauseStartTime *metav1.Time, paused, startedPause bool) []v1alpha1.PauseCondition {
+	if !paused {
+		return nil
+	}
+	var pauseConditions []v1alpha1.PauseCondition
+	for _, cond := range rollout.Status.PauseConditions {
+		pauseConditions = append(pauseConditions, *cond.DeepCopy())
+	}
+	if !startedPause && len(pauseConditions) > 0 {
+		return pauseConditions
+	}
+	if len(reasons) == 0 {
+		reasons = []v1alpha1.PauseReason{v1alpha1.PauseReasonUser}
+	}
+	startTime := metav1.Now()
+	if pauseStartTime != nil {
+		startTime = *pauseStartTime
+	}
+	for _, reason := range reasons {
+		if hasPauseCondition(pauseConditions, reason) {
+			continue
+		}
+		logutil.WithRollout(rollout).Infof("Adding pause condition '%s'", reason)
+		pauseConditions = append(pauseConditions, v1alpha1.PauseCondition{
+			Reason:    reason,
+			StartTime: startTime,
+		})
+	}
+	return pauseConditions
+}
+
+func hasPauseCondition(pauseConditions []v1alpha1.PauseCondition, reason v1alpha1.PauseReason) bool {
+	for _, cond := range pauseConditions {
+		if cond.Reason == reason {
+			return true
+		}
+	}
+	return false
 }
@@ -49,6 +49,11 @@ func (c *RolloutController) getReplicaSetsForRollouts(r *v1alpha1.Rollout) ([]*a
 	if err != nil {
 		return nil, err
 	}
+	// Adopt the ReplicaSet of the Deployment the rollout is migrated from before claiming the ReplicaSets
+	rsList, err = c.migrateDeploymentReplicaSet(r, rsList)
+	if err != nil {
+		return nil, err
+	}
 	replicaSetSelector, err := metav1.LabelSelectorAsSelector(r.Spec.Selector)
 	if err != nil {
 		return nil, fmt.Errorf("rollout %s/%s has invalid label selector: %v", r.Namespace, r.Name, err)
@@ -0,0 +1,96 @@
+package rollout
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+// rollback restores the pod template of the ReplicaSet with the revision of spec.rollbackTo and clears the field. The
+// revision annotations of the ReplicaSet are updated by the next sync, like after an edit of the template back to an
+// older revision.
+func (c *RolloutController) rollback(r *v1alpha1.Rollout, rsList []*appsv1.ReplicaSet) error {
+	logCtx := logutil.WithRollout(r)
+	if r.Spec.WorkloadRef != nil {
+		c.emitRollbackWarningEvent(r, conditions.RollbackWorkloadRefReason, conditions.RollbackWorkloadRefMessage)
+		return c.updateRolloutAndClearRollbackTo(r)
+	}
+	toRevision := r.Spec.RollbackTo.Revision
+	if toRevision == 0 {
+		if toRevision = replicasetutil.LastRevision(rsList); toRevision == 0 {
+			c.emitRollbackWarningEvent(r, conditions.RollbackRevisionNotFoundReason, conditions.RollbackLastRevisionNotFoundMessage)
+			return c.updateRolloutAndClearRollbackTo(r)
+		}
+	}
+	for _, rs := range rsList {
+		v, err := replicasetutil.Revision(rs)
+		if err != nil {
+			logCtx.WithError(err).Infof("Unable to extract revision from replica set '%s'", rs.Name)
+			continue
+		}
+		if v == toRevision {
+			logCtx.Infof("Rolling back to revision %d of replica set '%s'", toRevision, rs.Name)
+			if replicasetutil.PodTemplateEqualIgnoreHash(&rs.Spec.Template, &r.Spec.Template) {
+				c.emitRollbackWarningEvent(r, conditions.RollbackTemplateUnchangedReason, conditions.RollbackTemplateUnchangedMessage)
+				return c.updateRolloutAndClearRollbackTo(r)
+			}
+			r.Spec.Template = getRollbackTemplate(rs)
+			if r.Spec.RollbackTo.SkipSteps && r.Spec.Strategy.CanaryStrategy != nil {
+				skipCanarySteps(r, replicasetutil.GetPodTemplateHash(rs))
+			}
+			err = c.updateRolloutAndClearRollbackTo(r)
+			if err != nil {
+				return err
+			}
+			msg := fmt.Sprintf(conditions.RollbackDoneMessage, toRevision)
+			logCtx.Info(msg)
+			c.recorder.Event(r, corev1.EventTypeNormal, conditions.RollbackDoneReason, msg)
+			return nil
+		}
+	}
+	c.emitRollbackWarningEvent(r, conditions.RollbackRevisionNotFoundReason, fmt.Sprintf(conditions.RollbackRevisionNotFoundMessage, toRevision))
+	return c.updateRolloutAndClearRollbackTo(r)
+}
+
+// skipCanarySteps marks the steps as executed for the ReplicaSet with the pod hash, as if the rollout had been promoted
+// through them. The ReplicaSet is scaled up right away, but the stable ReplicaSet is unchanged until the ReplicaSet is
+// fully available, so the stable service and the traffic are not moved to a ReplicaSet which may be scaled down.
+func skipCanarySteps(r *v1alpha1.Rollout, podHash string) {
+	r.Status.CurrentPodHash = podHash
+	r.Status.CurrentStepHash = conditions.ComputeStepHash(r)
+	if stepCount := len(r.Spec.Strategy.CanaryStrategy.Steps); stepCount > 0 {
+		r.Status.CurrentStepIndex = pointer.Int32Ptr(int32(stepCount))
+	}
+	// Like a change of the template, the rollback starts a new rollout which is not aborted
+	r.Status.Abort = false
+	r.Status.Canary = v1alpha1.CanaryStatus{StableRS: r.Status.Canary.StableRS}
+}
+
+// getRollbackTemplate returns the pod template of the ReplicaSet without the label and anti-affinity term the
+// controller adds to the templates of the ReplicaSets
+func getRollbackTemplate(rs *appsv1.ReplicaSet) corev1.PodTemplateSpec {
+	template := rs.Spec.Template.DeepCopy()
+	delete(template.Labels, v1alpha1.DefaultRolloutUniqueLabelKey)
+	delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	template.Spec.Affinity = replicasetutil.RemoveInjectedAntiAffinityRule(template.Spec.Affinity)
+	return *template
+}
+
+func (c *RolloutController) emitRollbackWarningEvent(r *v1alpha1.Rollout, reason, message string) {
+	logutil.WithRollout(r).Warn(message)
+	c.recorder.Event(r, corev1.EventTypeWarning, reason, message)
+}
+
+// updateRolloutAndClearRollbackTo clears spec.rollbackTo and updates the rollout
+func (c *RolloutController) updateRolloutAndClearRollbackTo(r *v1alpha1.Rollout) error {
+	r.Spec.RollbackTo = nil
+	_, err := c.updateRollout(r)
+	return err
+}
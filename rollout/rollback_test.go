@@ -0,0 +1,191 @@
+package rollout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/annotations"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+// newRollbackRollouts returns three revisions of a canary rollout with their ReplicaSets. The second revision is
+// the stable revision and the third revision is the current revision.
+func newRollbackRollouts() ([]*v1alpha1.Rollout, []*appsv1.ReplicaSet) {
+	steps := []v1alpha1.CanaryStep{{SetWeight: pointer.Int32Ptr(10)}, {Pause: &v1alpha1.RolloutPause{}}}
+	r1 := newCanaryRollout("foo", 10, nil, steps, pointer.Int32Ptr(1), intstr.FromInt(1), intstr.FromInt(0))
+	annotations.SetRolloutRevision(r1, "1")
+	r2 := bumpVersion(r1)
+	r3 := bumpVersion(r2)
+	rs1 := newReplicaSet(r1, 0)
+	rs2 := newReplicaSet(r2, 9)
+	rs3 := newReplicaSet(r3, 1)
+	r3.Status.Canary.StableRS = replicasetutil.GetPodTemplateHash(rs2)
+	return []*v1alpha1.Rollout{r1, r2, r3}, []*appsv1.ReplicaSet{rs1, rs2, rs3}
+}
+
+func TestRollbackToPreviousRevision(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	rollouts, rsList := newRollbackRollouts()
+	r := rollouts[2]
+	r.Spec.RollbackTo = &v1alpha1.RollbackConfig{}
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.objects = append(f.objects, r)
+	f.replicaSetLister = append(f.replicaSetLister, rsList...)
+	f.kubeobjects = append(f.kubeobjects, rsList[0], rsList[1], rsList[2])
+
+	updatedIndex := f.expectUpdateRolloutAction(r)
+	f.run(getKey(r, t))
+
+	updatedRollout := f.getUpdatedRollout(updatedIndex)
+	assert.Nil(t, updatedRollout.Spec.RollbackTo)
+	assert.Equal(t, rollouts[1].Spec.Template.Spec.Containers, updatedRollout.Spec.Template.Spec.Containers)
+	assert.NotContains(t, updatedRollout.Spec.Template.Labels, v1alpha1.DefaultRolloutUniqueLabelKey)
+	// The restored template selects the ReplicaSet of the revision, whose revision is updated by the next sync
+	assert.Equal(t, rsList[1].Name, replicasetutil.FindNewReplicaSet(updatedRollout, rsList).Name)
+	// The stable ReplicaSet is unchanged, so the steps are skipped because the revision is the stable revision
+	assert.Equal(t, r.Status.Canary.StableRS, updatedRollout.Status.Canary.StableRS)
+	assert.Equal(t, r.Status.Canary.StableRS, replicasetutil.GetPodTemplateHash(rsList[1]))
+}
+
+func TestRollbackToRevisionSkipSteps(t *testing.T) {
+	for _, skipSteps := range []bool{false, true} {
+		f := newFixture(t)
+		rollouts, rsList := newRollbackRollouts()
+		r := rollouts[2]
+		r.Spec.RollbackTo = &v1alpha1.RollbackConfig{Revision: 1, SkipSteps: skipSteps}
+		f.rolloutLister = append(f.rolloutLister, r)
+		f.objects = append(f.objects, r)
+		f.replicaSetLister = append(f.replicaSetLister, rsList...)
+		c, _, _ := f.newController(noResyncPeriodFunc)
+
+		assert.NoError(t, c.rollback(r, rsList))
+		updatedRollout := f.getUpdatedRollout(0)
+		assert.Nil(t, updatedRollout.Spec.RollbackTo)
+		assert.Equal(t, rollouts[0].Spec.Template.Spec.Containers, updatedRollout.Spec.Template.Spec.Containers)
+		// The stable ReplicaSet only changes once the ReplicaSet of the revision is fully available
+		assert.Equal(t, replicasetutil.GetPodTemplateHash(rsList[1]), updatedRollout.Status.Canary.StableRS)
+		if skipSteps {
+			assert.Equal(t, replicasetutil.GetPodTemplateHash(rsList[0]), updatedRollout.Status.CurrentPodHash)
+			assert.Equal(t, pointer.Int32Ptr(2), updatedRollout.Status.CurrentStepIndex)
+			assert.False(t, replicasetutil.PodTemplateOrStepsChanged(updatedRollout, rsList[0]))
+		} else {
+			assert.Equal(t, r.Status.CurrentStepIndex, updatedRollout.Status.CurrentStepIndex)
+			assert.True(t, replicasetutil.PodTemplateOrStepsChanged(updatedRollout, rsList[0]))
+		}
+		f.Close()
+	}
+}
+
+func TestRollbackSkipStepsWithTrafficRouting(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	rollouts, rsList := newRollbackRollouts()
+	r := rollouts[2]
+	r.Spec.Strategy.CanaryStrategy.CanaryService = "canary"
+	r.Spec.Strategy.CanaryStrategy.StableService = "stable"
+	r.Spec.Strategy.CanaryStrategy.TrafficRouting = &v1alpha1.RolloutTrafficRouting{
+		Istio: &v1alpha1.IstioTrafficRouting{
+			VirtualService: v1alpha1.IstioVirtualService{Name: "vsvc", Routes: []string{"primary"}},
+		},
+	}
+	r.Status.Abort = true
+	r.Spec.RollbackTo = &v1alpha1.RollbackConfig{Revision: 1, SkipSteps: true}
+	f.rolloutLister = append(f.rolloutLister, r)
+	f.objects = append(f.objects, r)
+	f.replicaSetLister = append(f.replicaSetLister, rsList...)
+	c, _, _ := f.newController(noResyncPeriodFunc)
+
+	// The ReplicaSet of the first revision is scaled down
+	assert.Equal(t, int32(0), *rsList[0].Spec.Replicas)
+	assert.NoError(t, c.rollback(r, rsList))
+	updatedRollout := f.getUpdatedRollout(0)
+	assert.Equal(t, replicasetutil.GetPodTemplateHash(rsList[1]), updatedRollout.Status.Canary.StableRS)
+	assert.False(t, updatedRollout.Status.Abort)
+
+	// The traffic stays on the stable ReplicaSet until the ReplicaSet of the revision is available
+	reconciler := &fakeTrafficRoutingReconciler{}
+	trafficController := newTrafficRoutingController(reconciler)
+	assert.NoError(t, trafficController.reconcileTrafficRouting(updatedRollout, rsList[0], rsList[1]))
+	assert.Empty(t, reconciler.desiredWeights)
+
+	rsList[0].Spec.Replicas = pointer.Int32Ptr(10)
+	rsList[0].Status.AvailableReplicas = 10
+	assert.NoError(t, trafficController.reconcileTrafficRouting(updatedRollout, rsList[0], rsList[1]))
+	assert.Equal(t, []int32{100}, reconciler.desiredWeights)
+}
+
+func TestRollbackNotPerformed(t *testing.T) {
+	tests := []struct {
+		name       string
+		rollbackTo v1alpha1.RollbackConfig
+		modify     func(r *v1alpha1.Rollout) []*appsv1.ReplicaSet
+		reason     string
+	}{
+		{
+			name:       "RevisionNotFound",
+			rollbackTo: v1alpha1.RollbackConfig{Revision: 5},
+			reason:     "RollbackRevisionNotFound",
+		},
+		{
+			name:       "NoPreviousRevision",
+			rollbackTo: v1alpha1.RollbackConfig{},
+			modify: func(r *v1alpha1.Rollout) []*appsv1.ReplicaSet {
+				return []*appsv1.ReplicaSet{newReplicaSet(r, 1)}
+			},
+			reason: "RollbackRevisionNotFound",
+		},
+		{
+			name:       "TemplateUnchanged",
+			rollbackTo: v1alpha1.RollbackConfig{Revision: 3},
+			reason:     "RollbackTemplateUnchanged",
+		},
+		{
+			name:       "WorkloadRef",
+			rollbackTo: v1alpha1.RollbackConfig{Revision: 1},
+			modify: func(r *v1alpha1.Rollout) []*appsv1.ReplicaSet {
+				r.Spec.WorkloadRef = &v1alpha1.WorkloadRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "foo"}
+				return nil
+			},
+			reason: "RollbackWorkloadRef",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := newFixture(t)
+			defer f.Close()
+
+			rollouts, rsList := newRollbackRollouts()
+			r := rollouts[2]
+			r.Spec.RollbackTo = test.rollbackTo.DeepCopy()
+			if test.modify != nil {
+				if modifiedRSList := test.modify(r); modifiedRSList != nil {
+					rsList = modifiedRSList
+				}
+			}
+			f.objects = append(f.objects, r)
+			c, _, _ := f.newController(noResyncPeriodFunc)
+			recorder := record.NewFakeRecorder(1)
+			c.recorder = recorder
+
+			template := r.Spec.Template.DeepCopy()
+			assert.NoError(t, c.rollback(r, rsList))
+			updatedRollout := f.getUpdatedRollout(0)
+			assert.Nil(t, updatedRollout.Spec.RollbackTo)
+			if r.Spec.WorkloadRef == nil {
+				assert.Equal(t, template.Spec.Containers, updatedRollout.Spec.Template.Spec.Containers)
+			}
+			assert.Equal(t, rollouts[2].Status.Canary.StableRS, updatedRollout.Status.Canary.StableRS)
+			assert.Contains(t, <-recorder.Events, corev1.EventTypeWarning+" "+test.reason)
+		})
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 	"github.com/argoproj/argo-rollouts/utils/conditions"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+	serviceutil "github.com/argoproj/argo-rollouts/utils/service"
 )
 
 const (
@@ -114,6 +116,30 @@ func (c *RolloutController) reconcileActiveService(r *v1alpha1.Rollout, newRS *a
 	return false, nil
 }
 
+// reconcileAbortedActiveService switches the active service back to the previous active ReplicaSet when the rollout
+// was aborted after the active service had already switched to the new ReplicaSet
+func (c *RolloutController) reconcileAbortedActiveService(r *v1alpha1.Rollout, newRS *appsv1.ReplicaSet, oldRSs []*appsv1.ReplicaSet, activeSvc *corev1.Service) error {
+	logCtx := logutil.WithRollout(r)
+	activeSelector, ok := serviceutil.GetRolloutSelectorLabel(activeSvc)
+	if !ok || newRS == nil || activeSelector != replicasetutil.GetPodTemplateHash(newRS) {
+		return nil
+	}
+	previousActiveRS, _ := replicasetutil.GetReplicaSetByTemplateHash(oldRSs, r.Status.BlueGreen.PreviousActiveSelector)
+	if replicasetutil.GetReplicaCountForReplicaSets([]*appsv1.ReplicaSet{previousActiveRS}) == 0 {
+		logCtx.Warnf("Unable to switch active service '%s' back as the previous active ReplicaSet is scaled down", activeSvc.Name)
+		return nil
+	}
+	err := c.switchServiceSelector(activeSvc, r.Status.BlueGreen.PreviousActiveSelector, r)
+	if err != nil {
+		return err
+	}
+	if _, ok := previousActiveRS.Annotations[v1alpha1.DefaultReplicaSetScaleDownDeadlineAnnotationKey]; ok {
+		// The previous active ReplicaSet should not be scaled down now that it is receiving traffic again
+		return c.removeScaleDownDelay(r, previousActiveRS)
+	}
+	return nil
+}
+
 // getReferencedService returns service references in rollout spec and sets warning condition if service does not exist
 func (c *RolloutController) getReferencedService(r *v1alpha1.Rollout, serviceName string) (*corev1.Service, error) {
 	svc, err := c.servicesLister.Services(r.Namespace).Get(serviceName)
@@ -151,8 +177,43 @@ func (c *RolloutController) getPreviewAndActiveServices(r *v1alpha1.Rollout) (*c
 	return previewSvc, activeSvc, nil
 }
 
+func (c *RolloutController) reconcileStableAndCanaryService(r *v1alpha1.Rollout, newRS *appsv1.ReplicaSet, stableRS *appsv1.ReplicaSet) error {
+	if r.Spec.Strategy.CanaryStrategy == nil {
+		return nil
+	}
+	if err := c.reconcileStableService(r, newRS, stableRS); err != nil {
+		return err
+	}
+	return c.reconcileCanaryService(r, newRS)
+}
+
+// reconcileStableService points the stable service at the stable ReplicaSet, or at the new ReplicaSet if there is no
+// stable ReplicaSet yet
+func (c *RolloutController) reconcileStableService(r *v1alpha1.Rollout, newRS *appsv1.ReplicaSet, stableRS *appsv1.ReplicaSet) error {
+	if r.Spec.Strategy.CanaryStrategy.StableService == "" {
+		return nil
+	}
+	svc, err := c.getReferencedService(r, r.Spec.Strategy.CanaryStrategy.StableService)
+	if err != nil {
+		return err
+	}
+
+	rs := stableRS
+	if rs == nil {
+		rs = newRS
+	}
+	if rs == nil {
+		return nil
+	}
+	podHash := rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	if currentSelectorValue, ok := serviceutil.GetRolloutSelectorLabel(svc); ok && currentSelectorValue == podHash {
+		return nil
+	}
+	return c.switchServiceSelector(svc, podHash, r)
+}
+
 func (c *RolloutController) reconcileCanaryService(r *v1alpha1.Rollout, newRS *appsv1.ReplicaSet) error {
-	if r.Spec.Strategy.CanaryStrategy == nil || r.Spec.Strategy.CanaryStrategy.CanaryService == "" {
+	if r.Spec.Strategy.CanaryStrategy.CanaryService == "" {
 		return nil
 	}
 
@@ -161,5 +222,10 @@ func (c *RolloutController) reconcileCanaryService(r *v1alpha1.Rollout, newRS *a
 		return err
 	}
 
+	if r.Status.Abort && r.Status.Canary.StableRS != "" {
+		// An aborted rollout sends the canary traffic back to the stable ReplicaSet
+		return c.switchServiceSelector(svc, r.Status.Canary.StableRS, r)
+	}
+
 	return c.switchServiceSelector(svc, newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey], r)
 }
@@ -8,6 +8,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	patchtypes "k8s.io/apimachinery/pkg/types"
@@ -72,8 +73,12 @@ func (c *RolloutController) getNewReplicaSet(rollout *v1alpha1.Rollout, rsList,
 		// Set existing new replica set's annotation
 		annotationsUpdated := annotations.SetNewReplicaSetAnnotations(rollout, rsCopy, newRevision, true)
 		minReadySecondsNeedsUpdate := rsCopy.Spec.MinReadySeconds != rollout.Spec.MinReadySeconds
-		if annotationsUpdated || minReadySecondsNeedsUpdate {
+		// The injected anti-affinity term follows the stable ReplicaSet, and is removed once this ReplicaSet is stable
+		affinity := replicasetutil.GenerateReplicaSetAffinity(rollout, replicasetutil.GetPodTemplateHash(rsCopy))
+		affinityNeedsUpdate := !apiequality.Semantic.DeepEqual(rsCopy.Spec.Template.Spec.Affinity, affinity)
+		if annotationsUpdated || minReadySecondsNeedsUpdate || affinityNeedsUpdate {
 			rsCopy.Spec.MinReadySeconds = rollout.Spec.MinReadySeconds
+			rsCopy.Spec.Template.Spec.Affinity = affinity
 			return c.kubeclientset.AppsV1().ReplicaSets(rsCopy.ObjectMeta.Namespace).Update(rsCopy)
 		}
 
@@ -93,7 +98,7 @@ func (c *RolloutController) getNewReplicaSet(rollout *v1alpha1.Rollout, rsList,
 		if needsUpdate {
 			var err error
 			logCtx.Info("Setting revision annotation after creating a new replicaset")
-			if rollout, err = c.argoprojclientset.ArgoprojV1alpha1().Rollouts(rollout.Namespace).Update(rollout); err != nil {
+			if rollout, err = c.updateRollout(rollout); err != nil {
 				logCtx.WithError(err).Errorf("Error: Setting rollout revision annotation after creating a new replicaset")
 				return nil, err
 			}
@@ -109,6 +114,7 @@ func (c *RolloutController) getNewReplicaSet(rollout *v1alpha1.Rollout, rsList,
 	newRSTemplate := *rollout.Spec.Template.DeepCopy()
 	podTemplateSpecHash := controller.ComputeHash(&newRSTemplate, rollout.Status.CollisionCount)
 	newRSTemplate.Labels = labelsutil.CloneAndAddLabel(rollout.Spec.Template.Labels, v1alpha1.DefaultRolloutUniqueLabelKey, podTemplateSpecHash)
+	newRSTemplate.Spec.Affinity = replicasetutil.GenerateReplicaSetAffinity(rollout, podTemplateSpecHash)
 	// Add podTemplateHash label to selector.
 	newRSSelector := labelsutil.CloneSelectorAndAddLabel(rollout.Spec.Selector, v1alpha1.DefaultRolloutUniqueLabelKey, podTemplateSpecHash)
 
@@ -172,7 +178,7 @@ func (c *RolloutController) getNewReplicaSet(rollout *v1alpha1.Rollout, rsList,
 		*rollout.Status.CollisionCount++
 		// Update the collisionCount for the Rollout and let it requeue by returning the original
 		// error.
-		_, roErr := c.argoprojclientset.ArgoprojV1alpha1().Rollouts(rollout.Namespace).Update(rollout)
+		_, roErr := c.updateRollout(rollout)
 		if roErr == nil {
 			logCtx.Warnf("Found a hash collision - bumped collisionCount (%d->%d) to resolve it", preCollisionCount, *rollout.Status.CollisionCount)
 		}
@@ -200,7 +206,7 @@ func (c *RolloutController) getNewReplicaSet(rollout *v1alpha1.Rollout, rsList,
 	}
 
 	if needsUpdate {
-		_, err = c.argoprojclientset.ArgoprojV1alpha1().Rollouts(rollout.Namespace).Update(rollout)
+		_, err = c.updateRollout(rollout)
 	}
 	return createdRS, err
 }
@@ -224,7 +230,12 @@ func (c *RolloutController) syncReplicasOnly(r *v1alpha1.Rollout, rsList []*apps
 			// so we can abort this resync
 			return err
 		}
-		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, r, r.Spec.Paused)
+		arList, err := c.getAnalysisRunsForRollout(r)
+		if err != nil {
+			return err
+		}
+		currentArs, _ := analysisutil.FilterCurrentRolloutAnalysisRuns(arList, r)
+		return c.syncRolloutStatusBlueGreen(oldRSs, newRS, previewSvc, activeSvc, currentArs, r, r.Spec.Paused)
 	}
 	// The controller wants to use the rolloutCanary method to reconcile the rolllout if the rollout is not paused.
 	if r.Spec.Strategy.CanaryStrategy != nil && r.Spec.Paused {
@@ -442,9 +453,13 @@ func (c *RolloutController) calculateRolloutConditions(r *v1alpha1.Rollout, newS
 	// In such a case, we should simply not estimate any progress for this rollout.
 	currentCond := conditions.GetRolloutCondition(r.Status, v1alpha1.RolloutProgressing)
 	isCompleteRollout := newStatus.Replicas == newStatus.AvailableReplicas && currentCond != nil && currentCond.Reason == conditions.NewRSAvailableReason
-	// Check for progress only if the latest rollout hasn't completed yet.
-	if !isCompleteRollout {
+	// Check for progress only if the latest rollout hasn't completed yet. A blueGreen rollout can still be aborted
+	// after it completes if its post-promotion analysis fails.
+	if !isCompleteRollout || newStatus.Abort {
 		switch {
+		case newStatus.Abort:
+			condition := conditions.NewRolloutCondition(v1alpha1.RolloutProgressing, corev1.ConditionFalse, conditions.RolloutAbortedReason, conditions.RolloutAbortedMessage)
+			conditions.SetRolloutCondition(&newStatus, *condition)
 		case conditions.RolloutComplete(r, &newStatus):
 			// Update the rollout conditions with a message for the new replica set that
 			// was successfully deployed. If the condition already exists, we ignore this update.
@@ -0,0 +1,59 @@
+package rollout
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/istio"
+	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/nginx"
+	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/smi"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+	replicasetutil "github.com/argoproj/argo-rollouts/utils/replicaset"
+)
+
+// TrafficRoutingReconciler common function across all TrafficRouting implementation
+type TrafficRoutingReconciler interface {
+	// Reconcile sends the desired weight of the traffic to the canary service and the rest to the stable service
+	Reconcile(desiredWeight int32) error
+	// Type returns the type of the traffic routing reconciler
+	Type() string
+}
+
+// NewTrafficRoutingReconciler returns the TrafficRoutingReconciler for the service mesh configured in the rollout
+func (c *RolloutController) NewTrafficRoutingReconciler(r *v1alpha1.Rollout) TrafficRoutingReconciler {
+	if r.Spec.Strategy.CanaryStrategy == nil || r.Spec.Strategy.CanaryStrategy.TrafficRouting == nil {
+		return nil
+	}
+	if r.Spec.Strategy.CanaryStrategy.TrafficRouting.Istio != nil {
+		return istio.NewReconciler(r, c.dynamicclientset, c.recorder)
+	}
+	if r.Spec.Strategy.CanaryStrategy.TrafficRouting.Nginx != nil {
+		return nginx.NewReconciler(r, c.kubeclientset, c.recorder)
+	}
+	if r.Spec.Strategy.CanaryStrategy.TrafficRouting.SMI != nil {
+		return smi.NewReconciler(r, c.dynamicclientset, c.recorder)
+	}
+	return nil
+}
+
+// reconcileTrafficRouting sends the weight of the current step to the canary service once the new ReplicaSet has
+// enough available replicas. Aborted and completed rollouts send all the traffic to the stable service.
+func (c *RolloutController) reconcileTrafficRouting(rollout *v1alpha1.Rollout, newRS *appsv1.ReplicaSet, stableRS *appsv1.ReplicaSet) error {
+	reconciler := c.newTrafficRoutingReconciler(rollout)
+	if reconciler == nil {
+		return nil
+	}
+	logCtx := logutil.WithRollout(rollout)
+	logCtx.Infof("Reconciling TrafficRouting with type '%s'", reconciler.Type())
+
+	desiredWeight := int32(0)
+	if !rollout.Status.Abort && replicasetutil.CheckStableRSExists(newRS, stableRS) {
+		desiredNewRSReplicaCount, _ := replicasetutil.DesiredReplicaCountsForCanary(rollout, newRS, stableRS)
+		if newRS == nil || newRS.Status.AvailableReplicas < desiredNewRSReplicaCount {
+			logCtx.Info("Waiting for the new ReplicaSet to become available before shifting traffic")
+			return nil
+		}
+		desiredWeight = replicasetutil.GetCurrentSetWeight(rollout)
+	}
+	return reconciler.Reconcile(desiredWeight)
+}
@@ -0,0 +1,154 @@
+package istio
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+)
+
+const (
+	// Type holds this controller type
+	Type = "Istio"
+
+	// VirtualServiceResource is the plural name of the Istio VirtualService resource
+	VirtualServiceResource = "virtualservices"
+	// DefaultAPIVersion is the API version of the Istio VirtualService modified by the controller
+	DefaultAPIVersion = "networking.istio.io/v1alpha3"
+)
+
+// GetVirtualServiceGVR returns the GroupVersionResource of the Istio VirtualService
+func GetVirtualServiceGVR() schema.GroupVersionResource {
+	gv, _ := schema.ParseGroupVersion(DefaultAPIVersion)
+	return gv.WithResource(VirtualServiceResource)
+}
+
+// Reconciler holds required fields to reconcile Istio resources
+type Reconciler struct {
+	rollout  *v1alpha1.Rollout
+	log      *log.Entry
+	client   dynamic.Interface
+	recorder record.EventRecorder
+}
+
+// NewReconciler returns a reconciler struct that brings the Istio VirtualService into the desired state
+func NewReconciler(r *v1alpha1.Rollout, client dynamic.Interface, recorder record.EventRecorder) *Reconciler {
+	return &Reconciler{
+		rollout:  r,
+		log:      logutil.WithRollout(r),
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+// Type indicates this reconciler is an Istio reconciler
+func (r *Reconciler) Type() string {
+	return Type
+}
+
+// Reconcile modifies the weights of the Istio VirtualService routes to send the desired weight to the canary service
+// and the remaining traffic to the stable service
+func (r *Reconciler) Reconcile(desiredWeight int32) error {
+	vsvcName := r.rollout.Spec.Strategy.CanaryStrategy.TrafficRouting.Istio.VirtualService.Name
+	client := r.client.Resource(GetVirtualServiceGVR()).Namespace(r.rollout.Namespace)
+	vsvc, err := client.Get(vsvcName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	modifiedVsvc, modified, err := r.reconcileVirtualService(vsvc, desiredWeight)
+	if err != nil {
+		return err
+	}
+	if !modified {
+		return nil
+	}
+	msg := fmt.Sprintf("Updating VirtualService '%s' to desired weight '%d'", vsvcName, desiredWeight)
+	r.log.Info(msg)
+	r.recorder.Event(r.rollout, corev1.EventTypeNormal, "UpdatingVirtualService", msg)
+	_, err = client.Update(modifiedVsvc, metav1.UpdateOptions{})
+	return err
+}
+
+// reconcileVirtualService returns a copy of the VirtualService with the weights of the rollout routes set to the
+// desired weight, and whether the weights were modified
+func (r *Reconciler) reconcileVirtualService(obj *unstructured.Unstructured, desiredWeight int32) (*unstructured.Unstructured, bool, error) {
+	newObj := obj.DeepCopy()
+	httpRoutes, found, err := unstructured.NestedSlice(newObj.Object, "spec", "http")
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		return nil, false, fmt.Errorf(".spec.http is not defined in VirtualService '%s'", obj.GetName())
+	}
+
+	canarySvc := r.rollout.Spec.Strategy.CanaryStrategy.CanaryService
+	stableSvc := r.rollout.Spec.Strategy.CanaryStrategy.StableService
+	modified := false
+	for _, routeName := range r.rollout.Spec.Strategy.CanaryStrategy.TrafficRouting.Istio.VirtualService.Routes {
+		route, err := getHTTPRoute(httpRoutes, routeName)
+		if err != nil {
+			return nil, false, fmt.Errorf("VirtualService '%s': %v", obj.GetName(), err)
+		}
+		destinations, ok := route["route"].([]interface{})
+		if !ok {
+			return nil, false, fmt.Errorf("VirtualService '%s': HTTP route '%s' has no destinations", obj.GetName(), routeName)
+		}
+		foundCanary, foundStable := false, false
+		for _, d := range destinations {
+			destination, ok := d.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			host, _, _ := unstructured.NestedString(destination, "destination", "host")
+			var weight int64
+			switch {
+			case isServiceHost(host, canarySvc):
+				weight = int64(desiredWeight)
+				foundCanary = true
+			case isServiceHost(host, stableSvc):
+				weight = int64(100 - desiredWeight)
+				foundStable = true
+			default:
+				continue
+			}
+			if currentWeight, found, _ := unstructured.NestedInt64(destination, "weight"); !found || currentWeight != weight {
+				destination["weight"] = weight
+				modified = true
+			}
+		}
+		if !foundCanary || !foundStable {
+			return nil, false, fmt.Errorf("VirtualService '%s': HTTP route '%s' must have destinations for the canary service '%s' and the stable service '%s'", obj.GetName(), routeName, canarySvc, stableSvc)
+		}
+	}
+	if err := unstructured.SetNestedSlice(newObj.Object, httpRoutes, "spec", "http"); err != nil {
+		return nil, false, err
+	}
+	return newObj, modified, nil
+}
+
+func getHTTPRoute(httpRoutes []interface{}, name string) (map[string]interface{}, error) {
+	for _, r := range httpRoutes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if routeName, _, _ := unstructured.NestedString(route, "name"); routeName == name {
+			return route, nil
+		}
+	}
+	return nil, fmt.Errorf("HTTP route '%s' is not found", name)
+}
+
+// isServiceHost checks if the destination host refers to the service, either by its short name or its FQDN
+func isServiceHost(host, svc string) bool {
+	return host == svc || strings.HasPrefix(host, svc+".")
+}
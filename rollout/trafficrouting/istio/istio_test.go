@@ -0,0 +1,162 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+const regularVsvc = `apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: vsvc
+  namespace: default
+spec:
+  gateways:
+  - istio-rollout-gateway
+  hosts:
+  - istio-rollout.dev.argoproj.io
+  http:
+  - name: primary
+    route:
+    - destination:
+        host: stable
+      weight: 100
+    - destination:
+        host: canary
+      weight: 0
+  - name: secondary
+    route:
+    - destination:
+        host: stable.default.svc.cluster.local
+      weight: 100
+    - destination:
+        host: canary.default.svc.cluster.local
+      weight: 0`
+
+func rollout(routes ...string) *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rollout",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				CanaryStrategy: &v1alpha1.CanaryStrategy{
+					StableService: "stable",
+					CanaryService: "canary",
+					TrafficRouting: &v1alpha1.RolloutTrafficRouting{
+						Istio: &v1alpha1.IstioTrafficRouting{
+							VirtualService: v1alpha1.IstioVirtualService{
+								Name:   "vsvc",
+								Routes: routes,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func unstructuredObj(t *testing.T, text string) *unstructured.Unstructured {
+	obj := make(map[string]interface{})
+	err := yaml.Unmarshal([]byte(text), &obj)
+	assert.NoError(t, err)
+	un := &unstructured.Unstructured{Object: obj}
+	// round trip through JSON so numbers are decoded as int64 like the dynamic client does
+	data, err := un.MarshalJSON()
+	assert.NoError(t, err)
+	err = un.UnmarshalJSON(data)
+	assert.NoError(t, err)
+	return un
+}
+
+func routeWeights(t *testing.T, obj *unstructured.Unstructured, routeName string) map[string]int64 {
+	httpRoutes, _, err := unstructured.NestedSlice(obj.Object, "spec", "http")
+	assert.NoError(t, err)
+	route, err := getHTTPRoute(httpRoutes, routeName)
+	assert.NoError(t, err)
+	weights := map[string]int64{}
+	for _, d := range route["route"].([]interface{}) {
+		destination := d.(map[string]interface{})
+		host, _, _ := unstructured.NestedString(destination, "destination", "host")
+		weight, _, _ := unstructured.NestedInt64(destination, "weight")
+		weights[host] = weight
+	}
+	return weights
+}
+
+func TestReconcileVirtualService(t *testing.T) {
+	r := NewReconciler(rollout("primary", "secondary"), nil, &record.FakeRecorder{})
+	obj := unstructuredObj(t, regularVsvc)
+	modifiedObj, modified, err := r.reconcileVirtualService(obj, 10)
+	assert.NoError(t, err)
+	assert.True(t, modified)
+	assert.Equal(t, map[string]int64{"stable": 90, "canary": 10}, routeWeights(t, modifiedObj, "primary"))
+	assert.Equal(t, map[string]int64{"stable.default.svc.cluster.local": 90, "canary.default.svc.cluster.local": 10}, routeWeights(t, modifiedObj, "secondary"))
+	// the original object is left untouched
+	assert.Equal(t, map[string]int64{"stable": 100, "canary": 0}, routeWeights(t, obj, "primary"))
+}
+
+func TestReconcileVirtualServiceOnlyModifiesListedRoutes(t *testing.T) {
+	r := NewReconciler(rollout("primary"), nil, &record.FakeRecorder{})
+	modifiedObj, modified, err := r.reconcileVirtualService(unstructuredObj(t, regularVsvc), 50)
+	assert.NoError(t, err)
+	assert.True(t, modified)
+	assert.Equal(t, map[string]int64{"stable": 50, "canary": 50}, routeWeights(t, modifiedObj, "primary"))
+	assert.Equal(t, map[string]int64{"stable.default.svc.cluster.local": 100, "canary.default.svc.cluster.local": 0}, routeWeights(t, modifiedObj, "secondary"))
+}
+
+func TestReconcileVirtualServiceNoChange(t *testing.T) {
+	r := NewReconciler(rollout("primary", "secondary"), nil, &record.FakeRecorder{})
+	_, modified, err := r.reconcileVirtualService(unstructuredObj(t, regularVsvc), 0)
+	assert.NoError(t, err)
+	assert.False(t, modified)
+}
+
+func TestReconcileVirtualServiceMissingRoute(t *testing.T) {
+	r := NewReconciler(rollout("missing"), nil, &record.FakeRecorder{})
+	_, _, err := r.reconcileVirtualService(unstructuredObj(t, regularVsvc), 10)
+	assert.EqualError(t, err, "VirtualService 'vsvc': HTTP route 'missing' is not found")
+}
+
+func TestReconcileVirtualServiceMissingDestination(t *testing.T) {
+	ro := rollout("primary")
+	ro.Spec.Strategy.CanaryStrategy.CanaryService = "other"
+	r := NewReconciler(ro, nil, &record.FakeRecorder{})
+	_, _, err := r.reconcileVirtualService(unstructuredObj(t, regularVsvc), 10)
+	assert.EqualError(t, err, "VirtualService 'vsvc': HTTP route 'primary' must have destinations for the canary service 'other' and the stable service 'stable'")
+}
+
+func TestReconcileUpdatesVirtualService(t *testing.T) {
+	obj := unstructuredObj(t, regularVsvc)
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), obj)
+	r := NewReconciler(rollout("primary"), client, &record.FakeRecorder{})
+	err := r.Reconcile(20)
+	assert.NoError(t, err)
+
+	actions := client.Actions()
+	assert.Len(t, actions, 2)
+	assert.Equal(t, "get", actions[0].GetVerb())
+	assert.Equal(t, "update", actions[1].GetVerb())
+
+	updatedObj, err := client.Resource(GetVirtualServiceGVR()).Namespace(metav1.NamespaceDefault).Get("vsvc", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"stable": 80, "canary": 20}, routeWeights(t, updatedObj, "primary"))
+}
+
+func TestReconcileVirtualServiceNotFound(t *testing.T) {
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	r := NewReconciler(rollout("primary"), client, &record.FakeRecorder{})
+	err := r.Reconcile(20)
+	assert.Error(t, err)
+}
@@ -0,0 +1,170 @@
+package nginx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+)
+
+const (
+	// Type holds this controller type
+	Type = "Nginx"
+
+	// DefaultAnnotationPrefix is the annotation prefix used by the nginx ingress controller by default
+	DefaultAnnotationPrefix = "nginx.ingress.kubernetes.io"
+)
+
+var controllerKind = v1alpha1.SchemeGroupVersion.WithKind("Rollout")
+
+// Reconciler holds required fields to reconcile Nginx resources
+type Reconciler struct {
+	rollout  *v1alpha1.Rollout
+	log      *log.Entry
+	client   kubernetes.Interface
+	recorder record.EventRecorder
+}
+
+// NewReconciler returns a reconciler struct that brings the canary Ingress into the desired state
+func NewReconciler(r *v1alpha1.Rollout, client kubernetes.Interface, recorder record.EventRecorder) *Reconciler {
+	return &Reconciler{
+		rollout:  r,
+		log:      logutil.WithRollout(r),
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+// Type indicates this reconciler is an Nginx reconciler
+func (r *Reconciler) Type() string {
+	return Type
+}
+
+// CanaryIngressName returns the name of the canary Ingress the controller creates from the stable Ingress
+func CanaryIngressName(rollout *v1alpha1.Rollout) string {
+	return fmt.Sprintf("%s-%s-canary", rollout.Name, rollout.Spec.Strategy.CanaryStrategy.TrafficRouting.Nginx.StableIngress)
+}
+
+// Reconcile creates or updates a canary copy of the stable Ingress which sends the desired weight of the traffic to
+// the canary service. The canary Ingress is deleted once no traffic is sent to the canary service.
+func (r *Reconciler) Reconcile(desiredWeight int32) error {
+	ingressClient := r.client.ExtensionsV1beta1().Ingresses(r.rollout.Namespace)
+	canaryIngressName := CanaryIngressName(r.rollout)
+	canaryIngress, err := ingressClient.Get(canaryIngressName, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if errors.IsNotFound(err) {
+		canaryIngress = nil
+	}
+	if canaryIngress != nil && !metav1.IsControlledBy(canaryIngress, r.rollout) {
+		return fmt.Errorf("canary Ingress '%s' already exists and is not owned by the rollout", canaryIngressName)
+	}
+
+	if desiredWeight == 0 {
+		if canaryIngress == nil {
+			return nil
+		}
+		msg := fmt.Sprintf("Deleting canary Ingress '%s'", canaryIngressName)
+		r.log.Info(msg)
+		r.recorder.Event(r.rollout, corev1.EventTypeNormal, "DeletingCanaryIngress", msg)
+		err := ingressClient.Delete(canaryIngressName, &metav1.DeleteOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	stableIngressName := r.rollout.Spec.Strategy.CanaryStrategy.TrafficRouting.Nginx.StableIngress
+	stableIngress, err := ingressClient.Get(stableIngressName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	desiredCanaryIngress, err := r.canaryIngress(stableIngress, canaryIngressName, desiredWeight)
+	if err != nil {
+		return err
+	}
+
+	if canaryIngress == nil {
+		msg := fmt.Sprintf("Creating canary Ingress '%s' with weight '%d'", canaryIngressName, desiredWeight)
+		r.log.Info(msg)
+		r.recorder.Event(r.rollout, corev1.EventTypeNormal, "CreatingCanaryIngress", msg)
+		_, err = ingressClient.Create(desiredCanaryIngress)
+		return err
+	}
+
+	if reflect.DeepEqual(canaryIngress.Annotations, desiredCanaryIngress.Annotations) &&
+		reflect.DeepEqual(canaryIngress.Spec, desiredCanaryIngress.Spec) {
+		return nil
+	}
+	updatedIngress := canaryIngress.DeepCopy()
+	updatedIngress.Annotations = desiredCanaryIngress.Annotations
+	updatedIngress.Spec = desiredCanaryIngress.Spec
+	msg := fmt.Sprintf("Updating canary Ingress '%s' to weight '%d'", canaryIngressName, desiredWeight)
+	r.log.Info(msg)
+	r.recorder.Event(r.rollout, corev1.EventTypeNormal, "UpdatingCanaryIngress", msg)
+	_, err = ingressClient.Update(updatedIngress)
+	return err
+}
+
+// canaryIngress returns a copy of the stable Ingress with the canary annotations, whose backends point at the canary
+// service instead of the stable service
+func (r *Reconciler) canaryIngress(stableIngress *extensionsv1beta1.Ingress, name string, desiredWeight int32) (*extensionsv1beta1.Ingress, error) {
+	stableSvc := r.rollout.Spec.Strategy.CanaryStrategy.StableService
+	canarySvc := r.rollout.Spec.Strategy.CanaryStrategy.CanaryService
+	annotationPrefix := r.rollout.Spec.Strategy.CanaryStrategy.TrafficRouting.Nginx.AnnotationPrefix
+	if annotationPrefix == "" {
+		annotationPrefix = DefaultAnnotationPrefix
+	}
+
+	ingress := &extensionsv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       stableIngress.Namespace,
+			Labels:          stableIngress.Labels,
+			Annotations:     map[string]string{},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(r.rollout, controllerKind)},
+		},
+		Spec: *stableIngress.Spec.DeepCopy(),
+	}
+	for k, v := range stableIngress.Annotations {
+		// The canary annotations of the stable Ingress would conflict with the ones of the canary Ingress
+		if strings.HasPrefix(k, annotationPrefix+"/canary") {
+			continue
+		}
+		ingress.Annotations[k] = v
+	}
+	ingress.Annotations[annotationPrefix+"/canary"] = "true"
+	ingress.Annotations[annotationPrefix+"/canary-weight"] = fmt.Sprintf("%d", desiredWeight)
+
+	hasStableBackend := false
+	switchBackend := func(backend *extensionsv1beta1.IngressBackend) {
+		if backend != nil && backend.ServiceName == stableSvc {
+			backend.ServiceName = canarySvc
+			hasStableBackend = true
+		}
+	}
+	switchBackend(ingress.Spec.Backend)
+	for i := range ingress.Spec.Rules {
+		if ingress.Spec.Rules[i].HTTP == nil {
+			continue
+		}
+		for j := range ingress.Spec.Rules[i].HTTP.Paths {
+			switchBackend(&ingress.Spec.Rules[i].HTTP.Paths[j].Backend)
+		}
+	}
+	if !hasStableBackend {
+		return nil, fmt.Errorf("ingress '%s' has no backend for the stable service '%s'", stableIngress.Name, stableSvc)
+	}
+	return ingress, nil
+}
@@ -0,0 +1,158 @@
+package nginx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+)
+
+func rollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rollout",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "rollout-uid",
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				CanaryStrategy: &v1alpha1.CanaryStrategy{
+					StableService: "stable",
+					CanaryService: "canary",
+					TrafficRouting: &v1alpha1.RolloutTrafficRouting{
+						Nginx: &v1alpha1.NginxTrafficRouting{
+							StableIngress: "ingress",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func stableIngress() *extensionsv1beta1.Ingress {
+	return &extensionsv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingress",
+			Namespace: metav1.NamespaceDefault,
+			Annotations: map[string]string{
+				"kubernetes.io/ingress.class":               "nginx",
+				"nginx.ingress.kubernetes.io/canary-weight": "50",
+			},
+		},
+		Spec: extensionsv1beta1.IngressSpec{
+			Rules: []extensionsv1beta1.IngressRule{{
+				Host: "example.com",
+				IngressRuleValue: extensionsv1beta1.IngressRuleValue{
+					HTTP: &extensionsv1beta1.HTTPIngressRuleValue{
+						Paths: []extensionsv1beta1.HTTPIngressPath{{
+							Path: "/",
+							Backend: extensionsv1beta1.IngressBackend{
+								ServiceName: "stable",
+								ServicePort: intstr.FromInt(80),
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func getCanaryIngress(t *testing.T, client *fake.Clientset) *extensionsv1beta1.Ingress {
+	ingress, err := client.ExtensionsV1beta1().Ingresses(metav1.NamespaceDefault).Get("rollout-ingress-canary", metav1.GetOptions{})
+	assert.NoError(t, err)
+	return ingress
+}
+
+func TestReconcileCreatesCanaryIngress(t *testing.T) {
+	client := fake.NewSimpleClientset(stableIngress())
+	ro := rollout()
+	r := NewReconciler(ro, client, &record.FakeRecorder{})
+	err := r.Reconcile(10)
+	assert.NoError(t, err)
+
+	ingress := getCanaryIngress(t, client)
+	assert.True(t, metav1.IsControlledBy(ingress, ro))
+	assert.Equal(t, map[string]string{
+		"kubernetes.io/ingress.class":               "nginx",
+		"nginx.ingress.kubernetes.io/canary":        "true",
+		"nginx.ingress.kubernetes.io/canary-weight": "10",
+	}, ingress.Annotations)
+	assert.Equal(t, "canary", ingress.Spec.Rules[0].HTTP.Paths[0].Backend.ServiceName)
+}
+
+func TestReconcileUpdatesCanaryIngressWeight(t *testing.T) {
+	client := fake.NewSimpleClientset(stableIngress())
+	r := NewReconciler(rollout(), client, &record.FakeRecorder{})
+	assert.NoError(t, r.Reconcile(10))
+	assert.NoError(t, r.Reconcile(40))
+
+	ingress := getCanaryIngress(t, client)
+	assert.Equal(t, "40", ingress.Annotations["nginx.ingress.kubernetes.io/canary-weight"])
+
+	// reconciling the same weight again does not update the canary Ingress
+	client.ClearActions()
+	assert.NoError(t, r.Reconcile(40))
+	for _, action := range client.Actions() {
+		assert.Equal(t, "get", action.GetVerb())
+	}
+}
+
+func TestReconcileCustomAnnotationPrefix(t *testing.T) {
+	client := fake.NewSimpleClientset(stableIngress())
+	ro := rollout()
+	ro.Spec.Strategy.CanaryStrategy.TrafficRouting.Nginx.AnnotationPrefix = "custom.nginx.io"
+	r := NewReconciler(ro, client, &record.FakeRecorder{})
+	assert.NoError(t, r.Reconcile(10))
+
+	ingress := getCanaryIngress(t, client)
+	assert.Equal(t, "true", ingress.Annotations["custom.nginx.io/canary"])
+	assert.Equal(t, "10", ingress.Annotations["custom.nginx.io/canary-weight"])
+}
+
+func TestReconcileDeletesCanaryIngress(t *testing.T) {
+	client := fake.NewSimpleClientset(stableIngress())
+	r := NewReconciler(rollout(), client, &record.FakeRecorder{})
+	assert.NoError(t, r.Reconcile(10))
+	assert.NoError(t, r.Reconcile(0))
+
+	_, err := client.ExtensionsV1beta1().Ingresses(metav1.NamespaceDefault).Get("rollout-ingress-canary", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+
+	// nothing left to delete
+	assert.NoError(t, r.Reconcile(0))
+}
+
+func TestReconcileCanaryIngressNotOwned(t *testing.T) {
+	existing := stableIngress()
+	existing.Name = "rollout-ingress-canary"
+	client := fake.NewSimpleClientset([]runtime.Object{stableIngress(), existing}...)
+	r := NewReconciler(rollout(), client, &record.FakeRecorder{})
+	err := r.Reconcile(10)
+	assert.EqualError(t, err, "canary Ingress 'rollout-ingress-canary' already exists and is not owned by the rollout")
+}
+
+func TestReconcileNoStableBackend(t *testing.T) {
+	client := fake.NewSimpleClientset(stableIngress())
+	ro := rollout()
+	ro.Spec.Strategy.CanaryStrategy.StableService = "other"
+	r := NewReconciler(ro, client, &record.FakeRecorder{})
+	err := r.Reconcile(10)
+	assert.EqualError(t, err, "ingress 'ingress' has no backend for the stable service 'other'")
+}
+
+func TestReconcileStableIngressNotFound(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	r := NewReconciler(rollout(), client, &record.FakeRecorder{})
+	err := r.Reconcile(10)
+	assert.True(t, errors.IsNotFound(err))
+}
@@ -0,0 +1,197 @@
+package smi
+
+import (
+	"fmt"
+	"strconv"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+)
+
+const (
+	// Type holds this controller type
+	Type = "SMI"
+
+	// TrafficSplitGroup is the API group of the SMI TrafficSplit resource
+	TrafficSplitGroup = "split.smi-spec.io"
+	// TrafficSplitResource is the plural name of the SMI TrafficSplit resource
+	TrafficSplitResource = "trafficsplits"
+	// TrafficSplitKind is the kind of the SMI TrafficSplit resource
+	TrafficSplitKind = "TrafficSplit"
+)
+
+var controllerKind = v1alpha1.SchemeGroupVersion.WithKind("Rollout")
+
+// GetTrafficSplitGVR returns the GroupVersionResource of the SMI TrafficSplit for the configured API version
+func GetTrafficSplitGVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    TrafficSplitGroup,
+		Version:  defaults.GetSMIAPIVersion(),
+		Resource: TrafficSplitResource,
+	}
+}
+
+// Reconciler holds required fields to reconcile SMI resources
+type Reconciler struct {
+	rollout  *v1alpha1.Rollout
+	log      *log.Entry
+	client   dynamic.Interface
+	recorder record.EventRecorder
+}
+
+// NewReconciler returns a reconciler struct that brings the SMI TrafficSplit into the desired state
+func NewReconciler(r *v1alpha1.Rollout, client dynamic.Interface, recorder record.EventRecorder) *Reconciler {
+	return &Reconciler{
+		rollout:  r,
+		log:      logutil.WithRollout(r),
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+// Type indicates this reconciler is an SMI reconciler
+func (r *Reconciler) Type() string {
+	return Type
+}
+
+// TrafficSplitName returns the name of the TrafficSplit managed by the rollout
+func TrafficSplitName(rollout *v1alpha1.Rollout) string {
+	if name := rollout.Spec.Strategy.CanaryStrategy.TrafficRouting.SMI.TrafficSplitName; name != "" {
+		return name
+	}
+	return rollout.Name
+}
+
+// rootService returns the name of the Service clients use to reach the stable and canary services
+func rootService(rollout *v1alpha1.Rollout) string {
+	if rootSvc := rollout.Spec.Strategy.CanaryStrategy.TrafficRouting.SMI.RootService; rootSvc != "" {
+		return rootSvc
+	}
+	return rollout.Spec.Strategy.CanaryStrategy.StableService
+}
+
+// Reconcile creates or updates the SMI TrafficSplit to send the desired weight of the traffic to the canary service
+// and the remaining traffic to the stable service
+func (r *Reconciler) Reconcile(desiredWeight int32) error {
+	apiVersion := defaults.GetSMIAPIVersion()
+	if apiVersion != "v1alpha1" && apiVersion != "v1alpha2" {
+		return fmt.Errorf("unsupported TrafficSplit API version '%s'", apiVersion)
+	}
+	trafficSplitName := TrafficSplitName(r.rollout)
+	client := r.client.Resource(GetTrafficSplitGVR()).Namespace(r.rollout.Namespace)
+	desiredSpec := r.trafficSplitSpec(apiVersion, desiredWeight)
+
+	trafficSplit, err := client.Get(trafficSplitName, metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		trafficSplit = &unstructured.Unstructured{Object: map[string]interface{}{"spec": desiredSpec}}
+		trafficSplit.SetAPIVersion(fmt.Sprintf("%s/%s", TrafficSplitGroup, apiVersion))
+		trafficSplit.SetKind(TrafficSplitKind)
+		trafficSplit.SetName(trafficSplitName)
+		trafficSplit.SetNamespace(r.rollout.Namespace)
+		trafficSplit.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(r.rollout, controllerKind)})
+		msg := fmt.Sprintf("Creating TrafficSplit '%s' with desired weight '%d'", trafficSplitName, desiredWeight)
+		r.log.Info(msg)
+		r.recorder.Event(r.rollout, corev1.EventTypeNormal, "CreatingTrafficSplit", msg)
+		_, err = client.Create(trafficSplit, metav1.CreateOptions{})
+		return err
+	}
+
+	if !metav1.IsControlledBy(trafficSplit, r.rollout) {
+		return fmt.Errorf("TrafficSplit '%s' already exists and is not owned by the rollout", trafficSplitName)
+	}
+	if isTrafficSplitSpecEqual(trafficSplit, desiredSpec) {
+		return nil
+	}
+	modifiedTrafficSplit := trafficSplit.DeepCopy()
+	modifiedTrafficSplit.Object["spec"] = desiredSpec
+	msg := fmt.Sprintf("Updating TrafficSplit '%s' to desired weight '%d'", trafficSplitName, desiredWeight)
+	r.log.Info(msg)
+	r.recorder.Event(r.rollout, corev1.EventTypeNormal, "UpdatingTrafficSplit", msg)
+	_, err = client.Update(modifiedTrafficSplit, metav1.UpdateOptions{})
+	return err
+}
+
+// trafficSplitSpec returns the spec of the TrafficSplit for the API version. The v1alpha1 API uses quantities for the
+// backend weights while the v1alpha2 API uses integers.
+func (r *Reconciler) trafficSplitSpec(apiVersion string, desiredWeight int32) map[string]interface{} {
+	weight := func(w int32) interface{} {
+		if apiVersion == "v1alpha1" {
+			return strconv.Itoa(int(w))
+		}
+		return int64(w)
+	}
+	return map[string]interface{}{
+		"service": rootService(r.rollout),
+		"backends": []interface{}{
+			map[string]interface{}{
+				"service": r.rollout.Spec.Strategy.CanaryStrategy.CanaryService,
+				"weight":  weight(desiredWeight),
+			},
+			map[string]interface{}{
+				"service": r.rollout.Spec.Strategy.CanaryStrategy.StableService,
+				"weight":  weight(100 - desiredWeight),
+			},
+		},
+	}
+}
+
+// isTrafficSplitSpecEqual compares the root service and the backend weights of the TrafficSplit with the desired spec.
+// The weights are compared by value since the API server can normalize the v1alpha1 quantities.
+func isTrafficSplitSpecEqual(trafficSplit *unstructured.Unstructured, desiredSpec map[string]interface{}) bool {
+	rootSvc, _, _ := unstructured.NestedString(trafficSplit.Object, "spec", "service")
+	if rootSvc != desiredSpec["service"] {
+		return false
+	}
+	backends, _, _ := unstructured.NestedSlice(trafficSplit.Object, "spec", "backends")
+	desiredBackends := desiredSpec["backends"].([]interface{})
+	if len(backends) != len(desiredBackends) {
+		return false
+	}
+	for i := range desiredBackends {
+		backend, ok := backends[i].(map[string]interface{})
+		if !ok {
+			return false
+		}
+		desiredBackend := desiredBackends[i].(map[string]interface{})
+		if backend["service"] != desiredBackend["service"] {
+			return false
+		}
+		weight, ok := weightValue(backend["weight"])
+		desiredWeight, _ := weightValue(desiredBackend["weight"])
+		if !ok || weight != desiredWeight {
+			return false
+		}
+	}
+	return true
+}
+
+// weightValue returns the value of a backend weight expressed as an integer or a quantity
+func weightValue(weight interface{}) (int64, bool) {
+	switch w := weight.(type) {
+	case int64:
+		return w, true
+	case float64:
+		return int64(w), true
+	case string:
+		q, err := resource.ParseQuantity(w)
+		if err != nil {
+			return 0, false
+		}
+		return q.Value(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,150 @@
+package smi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/defaults"
+)
+
+func rollout() *v1alpha1.Rollout {
+	return &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rollout",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "rollout-uid",
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				CanaryStrategy: &v1alpha1.CanaryStrategy{
+					StableService: "stable",
+					CanaryService: "canary",
+					TrafficRouting: &v1alpha1.RolloutTrafficRouting{
+						SMI: &v1alpha1.SMITrafficRouting{},
+					},
+				},
+			},
+		},
+	}
+}
+
+func getTrafficSplit(t *testing.T, client *fake.FakeDynamicClient, name string) *unstructured.Unstructured {
+	trafficSplit, err := client.Resource(GetTrafficSplitGVR()).Namespace(metav1.NamespaceDefault).Get(name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	return trafficSplit
+}
+
+func backendWeights(t *testing.T, trafficSplit *unstructured.Unstructured) map[string]interface{} {
+	backends, _, err := unstructured.NestedSlice(trafficSplit.Object, "spec", "backends")
+	assert.NoError(t, err)
+	weights := map[string]interface{}{}
+	for _, b := range backends {
+		backend := b.(map[string]interface{})
+		weights[backend["service"].(string)] = backend["weight"]
+	}
+	return weights
+}
+
+func TestReconcileCreatesTrafficSplit(t *testing.T) {
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	ro := rollout()
+	r := NewReconciler(ro, client, &record.FakeRecorder{})
+	err := r.Reconcile(10)
+	assert.NoError(t, err)
+
+	trafficSplit := getTrafficSplit(t, client, "rollout")
+	assert.Equal(t, "split.smi-spec.io/v1alpha1", trafficSplit.GetAPIVersion())
+	assert.True(t, metav1.IsControlledBy(trafficSplit, ro))
+	rootSvc, _, _ := unstructured.NestedString(trafficSplit.Object, "spec", "service")
+	assert.Equal(t, "stable", rootSvc)
+	assert.Equal(t, map[string]interface{}{"canary": "10", "stable": "90"}, backendWeights(t, trafficSplit))
+}
+
+func TestReconcileCreatesTrafficSplitV1alpha2(t *testing.T) {
+	defaults.SetSMIAPIVersion("v1alpha2")
+	defer defaults.SetSMIAPIVersion(defaults.DefaultSMITrafficSplitVersion)
+
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	ro := rollout()
+	ro.Spec.Strategy.CanaryStrategy.TrafficRouting.SMI.RootService = "root"
+	ro.Spec.Strategy.CanaryStrategy.TrafficRouting.SMI.TrafficSplitName = "split"
+	r := NewReconciler(ro, client, &record.FakeRecorder{})
+	err := r.Reconcile(10)
+	assert.NoError(t, err)
+
+	trafficSplit := getTrafficSplit(t, client, "split")
+	assert.Equal(t, "split.smi-spec.io/v1alpha2", trafficSplit.GetAPIVersion())
+	rootSvc, _, _ := unstructured.NestedString(trafficSplit.Object, "spec", "service")
+	assert.Equal(t, "root", rootSvc)
+	assert.Equal(t, map[string]interface{}{"canary": int64(10), "stable": int64(90)}, backendWeights(t, trafficSplit))
+}
+
+func TestReconcileUpdatesTrafficSplit(t *testing.T) {
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	r := NewReconciler(rollout(), client, &record.FakeRecorder{})
+	assert.NoError(t, r.Reconcile(10))
+	assert.NoError(t, r.Reconcile(50))
+
+	trafficSplit := getTrafficSplit(t, client, "rollout")
+	assert.Equal(t, map[string]interface{}{"canary": "50", "stable": "50"}, backendWeights(t, trafficSplit))
+}
+
+func TestReconcileTrafficSplitNoChange(t *testing.T) {
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	r := NewReconciler(rollout(), client, &record.FakeRecorder{})
+	assert.NoError(t, r.Reconcile(10))
+
+	client.ClearActions()
+	assert.NoError(t, r.Reconcile(10))
+	actions := client.Actions()
+	assert.Len(t, actions, 1)
+	assert.Equal(t, "get", actions[0].GetVerb())
+}
+
+func TestReconcileTrafficSplitNotOwned(t *testing.T) {
+	trafficSplit := &unstructured.Unstructured{}
+	trafficSplit.SetAPIVersion("split.smi-spec.io/v1alpha1")
+	trafficSplit.SetKind(TrafficSplitKind)
+	trafficSplit.SetName("rollout")
+	trafficSplit.SetNamespace(metav1.NamespaceDefault)
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme(), trafficSplit)
+	r := NewReconciler(rollout(), client, &record.FakeRecorder{})
+	err := r.Reconcile(10)
+	assert.EqualError(t, err, "TrafficSplit 'rollout' already exists and is not owned by the rollout")
+}
+
+func TestReconcileUnsupportedAPIVersion(t *testing.T) {
+	defaults.SetSMIAPIVersion("v1beta1")
+	defer defaults.SetSMIAPIVersion(defaults.DefaultSMITrafficSplitVersion)
+
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+	r := NewReconciler(rollout(), client, &record.FakeRecorder{})
+	err := r.Reconcile(10)
+	assert.EqualError(t, err, "unsupported TrafficSplit API version 'v1beta1'")
+	_, err = client.Resource(GetTrafficSplitGVR()).Namespace(metav1.NamespaceDefault).Get("rollout", metav1.GetOptions{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestIsTrafficSplitSpecEqual(t *testing.T) {
+	r := NewReconciler(rollout(), nil, &record.FakeRecorder{})
+	trafficSplit := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"service": "stable",
+			"backends": []interface{}{
+				map[string]interface{}{"service": "canary", "weight": "10"},
+				map[string]interface{}{"service": "stable", "weight": int64(90)},
+			},
+		},
+	}}
+	assert.True(t, isTrafficSplitSpecEqual(trafficSplit, r.trafficSplitSpec("v1alpha1", 10)))
+	assert.True(t, isTrafficSplitSpecEqual(trafficSplit, r.trafficSplitSpec("v1alpha2", 10)))
+	assert.False(t, isTrafficSplitSpecEqual(trafficSplit, r.trafficSplitSpec("v1alpha1", 20)))
+}
@@ -0,0 +1,134 @@
+package rollout
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/istio"
+	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/nginx"
+	"github.com/argoproj/argo-rollouts/rollout/trafficrouting/smi"
+)
+
+type fakeTrafficRoutingReconciler struct {
+	desiredWeights []int32
+}
+
+func (r *fakeTrafficRoutingReconciler) Reconcile(desiredWeight int32) error {
+	r.desiredWeights = append(r.desiredWeights, desiredWeight)
+	return nil
+}
+
+func (r *fakeTrafficRoutingReconciler) Type() string {
+	return "fake"
+}
+
+func newTrafficRoutingController(reconciler TrafficRoutingReconciler) *RolloutController {
+	return &RolloutController{
+		newTrafficRoutingReconciler: func(r *v1alpha1.Rollout) TrafficRoutingReconciler {
+			return reconciler
+		},
+	}
+}
+
+func newTrafficRoutingRollout(stepIndex int32) *v1alpha1.Rollout {
+	steps := []v1alpha1.CanaryStep{{
+		SetWeight: pointer.Int32Ptr(10),
+	}, {
+		Pause: &v1alpha1.RolloutPause{},
+	}}
+	r := newCanaryRollout("foo", 10, nil, steps, pointer.Int32Ptr(stepIndex), intstr.FromInt(1), intstr.FromInt(0))
+	r.Spec.Strategy.CanaryStrategy.CanaryService = "canary"
+	r.Spec.Strategy.CanaryStrategy.StableService = "stable"
+	r.Spec.Strategy.CanaryStrategy.TrafficRouting = &v1alpha1.RolloutTrafficRouting{
+		Istio: &v1alpha1.IstioTrafficRouting{
+			VirtualService: v1alpha1.IstioVirtualService{
+				Name:   "vsvc",
+				Routes: []string{"primary"},
+			},
+		},
+	}
+	return r
+}
+
+func TestReconcileTrafficRoutingSetsCurrentWeight(t *testing.T) {
+	r1 := newTrafficRoutingRollout(1)
+	r2 := bumpVersion(r1)
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+	rs2 := newReplicaSetWithStatus(r2, 1, 1)
+	r2.Status.Canary.StableRS = rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	reconciler := &fakeTrafficRoutingReconciler{}
+	c := newTrafficRoutingController(reconciler)
+	err := c.reconcileTrafficRouting(r2, rs2, rs1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{10}, reconciler.desiredWeights)
+}
+
+func TestReconcileTrafficRoutingWaitsForNewRSAvailability(t *testing.T) {
+	r1 := newTrafficRoutingRollout(1)
+	r2 := bumpVersion(r1)
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+	rs2 := newReplicaSetWithStatus(r2, 1, 0)
+	r2.Status.Canary.StableRS = rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+
+	reconciler := &fakeTrafficRoutingReconciler{}
+	c := newTrafficRoutingController(reconciler)
+	err := c.reconcileTrafficRouting(r2, rs2, rs1)
+	assert.NoError(t, err)
+	assert.Empty(t, reconciler.desiredWeights)
+}
+
+func TestReconcileTrafficRoutingSendsAllTrafficToStableWhenAborted(t *testing.T) {
+	r1 := newTrafficRoutingRollout(1)
+	r2 := bumpVersion(r1)
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+	rs2 := newReplicaSetWithStatus(r2, 0, 0)
+	r2.Status.Canary.StableRS = rs1.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+	r2.Status.Abort = true
+
+	reconciler := &fakeTrafficRoutingReconciler{}
+	c := newTrafficRoutingController(reconciler)
+	err := c.reconcileTrafficRouting(r2, rs2, rs1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{0}, reconciler.desiredWeights)
+}
+
+func TestReconcileTrafficRoutingSendsAllTrafficToStableWithoutStableRS(t *testing.T) {
+	r1 := newTrafficRoutingRollout(2)
+	rs1 := newReplicaSetWithStatus(r1, 10, 10)
+
+	reconciler := &fakeTrafficRoutingReconciler{}
+	c := newTrafficRoutingController(reconciler)
+	err := c.reconcileTrafficRouting(r1, rs1, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{0}, reconciler.desiredWeights)
+}
+
+func TestNewTrafficRoutingReconciler(t *testing.T) {
+	c := &RolloutController{}
+	r := newTrafficRoutingRollout(0)
+	reconciler := c.NewTrafficRoutingReconciler(r)
+	assert.NotNil(t, reconciler)
+	assert.Equal(t, istio.Type, reconciler.Type())
+
+	r.Spec.Strategy.CanaryStrategy.TrafficRouting = &v1alpha1.RolloutTrafficRouting{
+		Nginx: &v1alpha1.NginxTrafficRouting{StableIngress: "ingress"},
+	}
+	reconciler = c.NewTrafficRoutingReconciler(r)
+	assert.NotNil(t, reconciler)
+	assert.Equal(t, nginx.Type, reconciler.Type())
+
+	r.Spec.Strategy.CanaryStrategy.TrafficRouting = &v1alpha1.RolloutTrafficRouting{
+		SMI: &v1alpha1.SMITrafficRouting{},
+	}
+	reconciler = c.NewTrafficRoutingReconciler(r)
+	assert.NotNil(t, reconciler)
+	assert.Equal(t, smi.Type, reconciler.Type())
+
+	r.Spec.Strategy.CanaryStrategy.TrafficRouting = nil
+	assert.Nil(t, c.NewTrafficRoutingReconciler(r))
+}
@@ -0,0 +1,109 @@
+package rollout
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	patchtypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+	logutil "github.com/argoproj/argo-rollouts/utils/log"
+)
+
+const (
+	// workloadRefIndexName is the name of the rollouts index keyed by the Deployment referenced by their workloadRef
+	workloadRefIndexName = "byWorkloadRef"
+
+	scaleDownWorkloadRefPatch = `{"spec":{"replicas":0}}`
+)
+
+// getWorkloadRefKeys returns the namespace/name key of the Deployment referenced by the rollout's workloadRef
+func getWorkloadRefKeys(r *v1alpha1.Rollout) []string {
+	if r.Spec.WorkloadRef == nil || r.Spec.WorkloadRef.Name == "" {
+		return []string{}
+	}
+	return []string{fmt.Sprintf("%s/%s", r.Namespace, r.Spec.WorkloadRef.Name)}
+}
+
+// enqueueWorkloadRefRollouts enqueues the rollouts whose workloadRef references the Deployment
+func (c *RolloutController) enqueueWorkloadRefRollouts(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+	objs, err := c.rolloutsIndexer.ByIndex(workloadRefIndexName, key)
+	if err != nil {
+		return
+	}
+	for _, o := range objs {
+		if r, ok := o.(*v1alpha1.Rollout); ok {
+			c.enqueueRollout(r)
+		}
+	}
+}
+
+// resolveWorkloadRef sets the template of the rollout to the pod template of the Deployment referenced by its
+// workloadRef. The template is remarshalled so the pod template hash matches the one of an equivalent spec.template.
+func (c *RolloutController) resolveWorkloadRef(r *v1alpha1.Rollout) error {
+	if r.Spec.WorkloadRef == nil {
+		return nil
+	}
+	deployment, err := c.deploymentsLister.Deployments(r.Namespace).Get(r.Spec.WorkloadRef.Name)
+	if err != nil {
+		return err
+	}
+	templateBytes, err := json.Marshal(deployment.Spec.Template)
+	if err != nil {
+		return err
+	}
+	var template corev1.PodTemplateSpec
+	err = json.Unmarshal(templateBytes, &template)
+	if err != nil {
+		return err
+	}
+	r.Spec.Template = template
+	return nil
+}
+
+// scaleDownWorkloadRef scales the Deployment referenced by the workloadRef down to zero once the rollout is healthy
+func (c *RolloutController) scaleDownWorkloadRef(r *v1alpha1.Rollout) error {
+	if r.Spec.WorkloadRef == nil || !r.Spec.WorkloadRef.ScaleDown {
+		return nil
+	}
+	if !conditions.RolloutComplete(r, &r.Status) {
+		return nil
+	}
+	deployment, err := c.deploymentsLister.Deployments(r.Namespace).Get(r.Spec.WorkloadRef.Name)
+	if err != nil {
+		return err
+	}
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("Scaled down deployment %s to 0", deployment.Name)
+	logutil.WithRollout(r).Info(msg)
+	_, err = c.kubeclientset.AppsV1().Deployments(deployment.Namespace).Patch(deployment.Name, patchtypes.MergePatchType, []byte(scaleDownWorkloadRefPatch))
+	if err != nil {
+		return err
+	}
+	c.recorder.Event(r, corev1.EventTypeNormal, "ScalingDeployment", msg)
+	return nil
+}
+
+// updateRollout updates the rollout without writing the template resolved from the workloadRef back to the rollout
+func (c *RolloutController) updateRollout(r *v1alpha1.Rollout) (*v1alpha1.Rollout, error) {
+	if r.Spec.WorkloadRef == nil {
+		return c.argoprojclientset.ArgoprojV1alpha1().Rollouts(r.Namespace).Update(r)
+	}
+	rCopy := r.DeepCopy()
+	rCopy.Spec.Template = corev1.PodTemplateSpec{}
+	updated, err := c.argoprojclientset.ArgoprojV1alpha1().Rollouts(r.Namespace).Update(rCopy)
+	if err != nil {
+		return nil, err
+	}
+	updated.Spec.Template = r.Spec.Template
+	return updated, nil
+}
@@ -0,0 +1,204 @@
+package rollout
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	core "k8s.io/client-go/testing"
+	"k8s.io/utils/pointer"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	"github.com/argoproj/argo-rollouts/utils/conditions"
+)
+
+// newWorkloadRefRollout moves the template of the rollout to a Deployment referenced by the rollout's workloadRef
+func newWorkloadRefRollout(r *v1alpha1.Rollout) (*v1alpha1.Rollout, *appsv1.Deployment) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      r.Name + "-deploy",
+			Namespace: r.Namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(1),
+			Selector: r.Spec.Selector.DeepCopy(),
+			Template: *r.Spec.Template.DeepCopy(),
+		},
+	}
+	ref := r.DeepCopy()
+	ref.Spec.Template = corev1.PodTemplateSpec{}
+	ref.Spec.WorkloadRef = &v1alpha1.WorkloadRef{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       deployment.Name,
+	}
+	return ref, deployment
+}
+
+func TestWorkloadRefCreateFirstReplicaSet(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r := newCanaryRollout("foo", 10, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	r.Status.CurrentPodHash = ""
+	rs := newReplicaSet(r, 1)
+	ref, deployment := newWorkloadRefRollout(r)
+	f.rolloutLister = append(f.rolloutLister, ref)
+	f.objects = append(f.objects, ref)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	f.kubeobjects = append(f.kubeobjects, deployment)
+
+	createdRSIndex := f.expectCreateReplicaSetAction(rs)
+	updatedRolloutIndex := f.expectUpdateRolloutAction(ref)
+	patchIndex := f.expectPatchRolloutAction(ref)
+	f.run(getKey(ref, t))
+
+	createdRS := f.getCreatedReplicaSet(createdRSIndex)
+	assert.Equal(t, rs.Name, createdRS.Name)
+	assert.Equal(t, deployment.Spec.Template.Spec.Containers, createdRS.Spec.Template.Spec.Containers)
+
+	updatedRollout := f.getUpdatedRollout(updatedRolloutIndex)
+	assert.Equal(t, corev1.PodTemplateSpec{}, updatedRollout.Spec.Template)
+
+	patchedRollout := &v1alpha1.Rollout{}
+	assert.NoError(t, json.Unmarshal([]byte(f.getPatchedRollout(patchIndex)), patchedRollout))
+	assert.Equal(t, rs.Labels[v1alpha1.DefaultRolloutUniqueLabelKey], patchedRollout.Status.CurrentPodHash)
+	// the observed generation matches the stored rollout, which does not hold the resolved template
+	assert.Equal(t, conditions.ComputeGenerationHash(ref.Spec), patchedRollout.Status.ObservedGeneration)
+}
+
+func TestWorkloadRefDeploymentNotFound(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	ref, deployment := newWorkloadRefRollout(r)
+	f.rolloutLister = append(f.rolloutLister, ref)
+	f.objects = append(f.objects, ref)
+
+	patchIndex := f.expectPatchRolloutAction(ref)
+	f.run(getKey(ref, t))
+
+	patchedRollout := &v1alpha1.Rollout{}
+	assert.NoError(t, json.Unmarshal([]byte(f.getPatchedRollout(patchIndex)), patchedRollout))
+	invalidSpecCond := conditions.GetRolloutCondition(patchedRollout.Status, v1alpha1.InvalidSpec)
+	assert.NotNil(t, invalidSpecCond)
+	assert.Equal(t, conditions.InvalidSpecReason, invalidSpecCond.Reason)
+	assert.Equal(t, fmt.Sprintf(conditions.WorkloadRefNotFoundMessage, deployment.Name), invalidSpecCond.Message)
+}
+
+func TestResolveWorkloadRef(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	ref, deployment := newWorkloadRefRollout(r)
+	f.deploymentLister = append(f.deploymentLister, deployment)
+	c, _, _ := f.newController(noResyncPeriodFunc)
+
+	assert.NoError(t, c.resolveWorkloadRef(ref))
+	assert.Equal(t, r.Spec.Template, ref.Spec.Template)
+
+	// A change of the Deployment's template changes the pod template hash like an edit of the rollout's template
+	deployment.Spec.Template.Spec.Containers[0].Image = "foo/bar2"
+	bumped := bumpVersion(r)
+	assert.NoError(t, c.resolveWorkloadRef(ref))
+	assert.Equal(t, newReplicaSet(bumped, 1).Name, newReplicaSet(ref, 1).Name)
+
+	noRef := r.DeepCopy()
+	assert.NoError(t, c.resolveWorkloadRef(noRef))
+	assert.Equal(t, r.Spec.Template, noRef.Spec.Template)
+}
+
+func TestScaleDownWorkloadRef(t *testing.T) {
+	newCompletedRollout := func() (*v1alpha1.Rollout, *appsv1.Deployment) {
+		r := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+		podHash := newReplicaSet(r, 1).Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+		r.Status.CurrentPodHash = podHash
+		r.Status.Canary.StableRS = podHash
+		r.Status.Replicas = 1
+		r.Status.UpdatedReplicas = 1
+		r.Status.AvailableReplicas = 1
+		ref, deployment := newWorkloadRefRollout(r)
+		ref.Spec.WorkloadRef.ScaleDown = true
+		ref.Status.ObservedGeneration = conditions.ComputeGenerationHash(ref.Spec)
+		ref.Spec.Template = r.Spec.Template
+		return ref, deployment
+	}
+
+	t.Run("ScaleDownWhenComplete", func(t *testing.T) {
+		f := newFixture(t)
+		defer f.Close()
+		ref, deployment := newCompletedRollout()
+		f.deploymentLister = append(f.deploymentLister, deployment)
+		f.kubeobjects = append(f.kubeobjects, deployment)
+		c, _, _ := f.newController(noResyncPeriodFunc)
+
+		assert.NoError(t, c.scaleDownWorkloadRef(ref))
+		actions := filterInformerActions(f.kubeclient.Actions())
+		assert.Len(t, actions, 1)
+		patchAction, ok := actions[0].(core.PatchAction)
+		assert.True(t, ok)
+		assert.Equal(t, "deployments", patchAction.GetResource().Resource)
+		assert.Equal(t, deployment.Name, patchAction.GetName())
+		assert.Equal(t, scaleDownWorkloadRefPatch, string(patchAction.GetPatch()))
+	})
+
+	t.Run("NoScaleDownWhenDisabled", func(t *testing.T) {
+		f := newFixture(t)
+		defer f.Close()
+		ref, deployment := newCompletedRollout()
+		ref.Spec.WorkloadRef.ScaleDown = false
+		f.deploymentLister = append(f.deploymentLister, deployment)
+		f.kubeobjects = append(f.kubeobjects, deployment)
+		c, _, _ := f.newController(noResyncPeriodFunc)
+
+		assert.NoError(t, c.scaleDownWorkloadRef(ref))
+		assert.Len(t, filterInformerActions(f.kubeclient.Actions()), 0)
+	})
+
+	t.Run("NoScaleDownWhenNotComplete", func(t *testing.T) {
+		f := newFixture(t)
+		defer f.Close()
+		ref, deployment := newCompletedRollout()
+		ref.Status.AvailableReplicas = 0
+		f.deploymentLister = append(f.deploymentLister, deployment)
+		f.kubeobjects = append(f.kubeobjects, deployment)
+		c, _, _ := f.newController(noResyncPeriodFunc)
+
+		assert.NoError(t, c.scaleDownWorkloadRef(ref))
+		assert.Len(t, filterInformerActions(f.kubeclient.Actions()), 0)
+	})
+
+	t.Run("NoScaleDownWhenAlreadyScaledDown", func(t *testing.T) {
+		f := newFixture(t)
+		defer f.Close()
+		ref, deployment := newCompletedRollout()
+		deployment.Spec.Replicas = pointer.Int32Ptr(0)
+		f.deploymentLister = append(f.deploymentLister, deployment)
+		f.kubeobjects = append(f.kubeobjects, deployment)
+		c, _, _ := f.newController(noResyncPeriodFunc)
+
+		assert.NoError(t, c.scaleDownWorkloadRef(ref))
+		assert.Len(t, filterInformerActions(f.kubeclient.Actions()), 0)
+	})
+}
+
+func TestEnqueueWorkloadRefRollouts(t *testing.T) {
+	f := newFixture(t)
+	defer f.Close()
+
+	r := newCanaryRollout("foo", 1, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	ref, deployment := newWorkloadRefRollout(r)
+	other := newCanaryRollout("bar", 1, nil, nil, nil, intstr.FromInt(1), intstr.FromInt(0))
+	f.rolloutLister = append(f.rolloutLister, ref, other)
+	c, _, _ := f.newController(noResyncPeriodFunc)
+
+	c.enqueueWorkloadRefRollouts(deployment)
+	assert.Equal(t, map[string]int{getKey(ref, t): 1}, f.enqueuedObjects)
+}
@@ -3,34 +3,78 @@ package analysis
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 )
 
-// BuildArgumentsForRolloutAnalysisRun builds the arguments for a analysis base created by a rollout
-func BuildArgumentsForRolloutAnalysisRun(rolloutAnalysisRun *v1alpha1.RolloutAnalysisStep, stableRS, newRS *appsv1.ReplicaSet) []v1alpha1.Argument {
+// BuildArgumentsForRolloutAnalysisRun builds the arguments for a analysis base created by a rollout. The arguments
+// referencing a secret keep the reference instead of the value, which is only resolved when the measurements are taken.
+func BuildArgumentsForRolloutAnalysisRun(rolloutAnalysisRun *v1alpha1.RolloutAnalysisStep, stableRS, newRS *appsv1.ReplicaSet, r *v1alpha1.Rollout) ([]v1alpha1.Argument, error) {
 	arguments := []v1alpha1.Argument{}
 	for i := range rolloutAnalysisRun.Arguments {
 		arg := rolloutAnalysisRun.Arguments[i]
-		value := arg.Value
-		if arg.ValueFrom != nil {
-			switch *arg.ValueFrom.PodTemplateHashValue {
-			case v1alpha1.Latest:
-				value = newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
-			case v1alpha1.Stable:
-				value = stableRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
-			}
-		}
 		analysisArg := v1alpha1.Argument{
 			Name:  arg.Name,
-			Value: value,
+			Value: arg.Value,
+		}
+		if arg.ValueFrom != nil {
+			switch {
+			case arg.ValueFrom.PodTemplateHashValue != nil:
+				switch *arg.ValueFrom.PodTemplateHashValue {
+				case v1alpha1.Latest:
+					analysisArg.Value = newRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+				case v1alpha1.Stable:
+					analysisArg.Value = stableRS.Labels[v1alpha1.DefaultRolloutUniqueLabelKey]
+				}
+			case arg.ValueFrom.FieldRef != nil:
+				value, err := GetFieldRefValue(r, arg.ValueFrom.FieldRef.FieldPath)
+				if err != nil {
+					return nil, fmt.Errorf("argument '%s': %v", arg.Name, err)
+				}
+				analysisArg.Value = value
+			case arg.ValueFrom.SecretKeyRef != nil:
+				analysisArg.ValueFrom = &v1alpha1.ValueFrom{
+					SecretKeyRef: arg.ValueFrom.SecretKeyRef.DeepCopy(),
+				}
+			}
 		}
 		arguments = append(arguments, analysisArg)
 
 	}
-	return arguments
+	return arguments, nil
+}
+
+// GetFieldRefValue returns the value of a field of the rollout metadata. The supported field paths are the ones of
+// the downward API: metadata.name, metadata.namespace, metadata.uid, metadata.labels['<KEY>'] and
+// metadata.annotations['<KEY>'].
+func GetFieldRefValue(r *v1alpha1.Rollout, fieldPath string) (string, error) {
+	switch fieldPath {
+	case "metadata.name":
+		return r.Name, nil
+	case "metadata.namespace":
+		return r.Namespace, nil
+	case "metadata.uid":
+		return string(r.UID), nil
+	}
+	if key, ok := subscriptKey(fieldPath, "metadata.labels"); ok {
+		return r.Labels[key], nil
+	}
+	if key, ok := subscriptKey(fieldPath, "metadata.annotations"); ok {
+		return r.Annotations[key], nil
+	}
+	return "", fmt.Errorf("unsupported fieldPath '%s'", fieldPath)
+}
+
+// subscriptKey returns the key of a field path of the form <prefix>['<KEY>']
+func subscriptKey(fieldPath, prefix string) (string, bool) {
+	if !strings.HasPrefix(fieldPath, prefix+"['") || !strings.HasSuffix(fieldPath, "']") {
+		return "", false
+	}
+	key := fieldPath[len(prefix)+2 : len(fieldPath)-2]
+	return key, key != ""
 }
 
 // StepLabels returns a map[string]string of common labels for analysisruns created from an analysis step
@@ -43,11 +87,45 @@ func StepLabels(r *v1alpha1.Rollout, index int32, podHash string) map[string]str
 	}
 }
 
+// BackgroundLabels returns a map[string]string of common labels for the background analysis
+func BackgroundLabels(podHash string) map[string]string {
+	return map[string]string{
+		v1alpha1.DefaultRolloutUniqueLabelKey: podHash,
+		v1alpha1.RolloutTypeLabel:             v1alpha1.RolloutTypeBackgroundRunLabel,
+	}
+}
+
+// PrePromotionLabels returns a map[string]string of common labels for the blueGreen pre-promotion analysis
+func PrePromotionLabels(podHash string) map[string]string {
+	return map[string]string{
+		v1alpha1.DefaultRolloutUniqueLabelKey: podHash,
+		v1alpha1.RolloutTypeLabel:             v1alpha1.RolloutTypePrePromotionLabel,
+	}
+}
+
+// PostPromotionLabels returns a map[string]string of common labels for the blueGreen post-promotion analysis
+func PostPromotionLabels(podHash string) map[string]string {
+	return map[string]string{
+		v1alpha1.DefaultRolloutUniqueLabelKey: podHash,
+		v1alpha1.RolloutTypeLabel:             v1alpha1.RolloutTypePostPromotionLabel,
+	}
+}
+
 // ValidateAnalysisTemplateSpec validates an analysis template spec
 func ValidateAnalysisTemplateSpec(spec v1alpha1.AnalysisTemplateSpec) error {
 	if len(spec.Metrics) == 0 {
 		return fmt.Errorf("no metrics specified")
 	}
+	argNames := make(map[string]bool)
+	for i, arg := range spec.Args {
+		if arg.Name == "" {
+			return fmt.Errorf("args[%d]: name is required", i)
+		}
+		if argNames[arg.Name] {
+			return fmt.Errorf("args[%d]: duplicate name '%s'", i, arg.Name)
+		}
+		argNames[arg.Name] = true
+	}
 	duplicateNames := make(map[string]bool)
 	for i, metric := range spec.Metrics {
 		if _, ok := duplicateNames[metric.Name]; ok {
@@ -88,6 +166,15 @@ func ValidateMetric(metric v1alpha1.Metric) error {
 	if metric.Provider.Job != nil {
 		numProviders++
 	}
+	if metric.Provider.Web != nil {
+		numProviders++
+	}
+	if metric.Provider.Datadog != nil {
+		numProviders++
+	}
+	if metric.Provider.Kayenta != nil {
+		numProviders++
+	}
 	if numProviders == 0 {
 		return fmt.Errorf("no provider specified")
 	}
@@ -46,13 +46,99 @@ func TestBuildArgumentsForRolloutAnalysisRun(t *testing.T) {
 			Labels: map[string]string{v1alpha1.DefaultRolloutUniqueLabelKey: "123456"},
 		},
 	}
-	args := BuildArgumentsForRolloutAnalysisRun(rolloutAnalysisStep, stableRS, newRS)
+	rollout := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "guestbook",
+			Namespace:   "default",
+			Labels:      map[string]string{"app": "guestbook"},
+			Annotations: map[string]string{"team": "frontend"},
+		},
+	}
+	args, err := BuildArgumentsForRolloutAnalysisRun(rolloutAnalysisStep, stableRS, newRS, rollout)
+	assert.NoError(t, err)
 	assert.Contains(t, args, v1alpha1.Argument{Name: "hard-coded-value-key", Value: "hard-coded-value"})
 	assert.Contains(t, args, v1alpha1.Argument{Name: "stable-key", Value: "abcdef"})
 	assert.Contains(t, args, v1alpha1.Argument{Name: "new-key", Value: "123456"})
 
 }
 
+func TestBuildArgumentsFromFieldRefAndSecretKeyRef(t *testing.T) {
+	rolloutAnalysisStep := &v1alpha1.RolloutAnalysisStep{
+		Arguments: []v1alpha1.AnalysisRunArgument{
+			{
+				Name: "namespace",
+				ValueFrom: &v1alpha1.ArgumentValueFrom{
+					FieldRef: &v1alpha1.FieldRef{FieldPath: "metadata.namespace"},
+				},
+			},
+			{
+				Name: "app",
+				ValueFrom: &v1alpha1.ArgumentValueFrom{
+					FieldRef: &v1alpha1.FieldRef{FieldPath: "metadata.labels['app']"},
+				},
+			},
+			{
+				Name: "api-token",
+				ValueFrom: &v1alpha1.ArgumentValueFrom{
+					SecretKeyRef: &v1alpha1.SecretKeyRef{Name: "metrics", Key: "token"},
+				},
+			},
+		},
+	}
+	rollout := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook",
+			Namespace: "default",
+			Labels:    map[string]string{"app": "guestbook"},
+		},
+	}
+	args, err := BuildArgumentsForRolloutAnalysisRun(rolloutAnalysisStep, nil, nil, rollout)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1alpha1.Argument{
+		{Name: "namespace", Value: "default"},
+		{Name: "app", Value: "guestbook"},
+		{
+			Name: "api-token",
+			ValueFrom: &v1alpha1.ValueFrom{
+				SecretKeyRef: &v1alpha1.SecretKeyRef{Name: "metrics", Key: "token"},
+			},
+		},
+	}, args)
+
+	rolloutAnalysisStep.Arguments[0].ValueFrom.FieldRef.FieldPath = "spec.replicas"
+	_, err = BuildArgumentsForRolloutAnalysisRun(rolloutAnalysisStep, nil, nil, rollout)
+	assert.EqualError(t, err, "argument 'namespace': unsupported fieldPath 'spec.replicas'")
+}
+
+func TestGetFieldRefValue(t *testing.T) {
+	rollout := &v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "guestbook",
+			Namespace:   "default",
+			UID:         "1234",
+			Labels:      map[string]string{"app": "guestbook"},
+			Annotations: map[string]string{"team": "frontend"},
+		},
+	}
+	tests := map[string]string{
+		"metadata.name":                "guestbook",
+		"metadata.namespace":           "default",
+		"metadata.uid":                 "1234",
+		"metadata.labels['app']":       "guestbook",
+		"metadata.labels['missing']":   "",
+		"metadata.annotations['team']": "frontend",
+	}
+	for fieldPath, expected := range tests {
+		value, err := GetFieldRefValue(rollout, fieldPath)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value, fieldPath)
+	}
+	for _, fieldPath := range []string{"spec.replicas", "metadata.labels", "metadata.labels['']", "metadata.labels[app]"} {
+		_, err := GetFieldRefValue(rollout, fieldPath)
+		assert.EqualError(t, err, "unsupported fieldPath '"+fieldPath+"'")
+	}
+}
+
 func TestStepLabels(t *testing.T) {
 	ro := &v1alpha1.Rollout{
 		ObjectMeta: metav1.ObjectMeta{
@@ -69,6 +155,28 @@ func TestStepLabels(t *testing.T) {
 	assert.Equal(t, expected, generated)
 }
 
+func TestBackgroundLabels(t *testing.T) {
+	podHash := "abcd123"
+	expected := map[string]string{
+		v1alpha1.DefaultRolloutUniqueLabelKey: podHash,
+		v1alpha1.RolloutTypeLabel:             v1alpha1.RolloutTypeBackgroundRunLabel,
+	}
+	generated := BackgroundLabels(podHash)
+	assert.Equal(t, expected, generated)
+}
+
+func TestPromotionLabels(t *testing.T) {
+	podHash := "abcd123"
+	assert.Equal(t, map[string]string{
+		v1alpha1.DefaultRolloutUniqueLabelKey: podHash,
+		v1alpha1.RolloutTypeLabel:             v1alpha1.RolloutTypePrePromotionLabel,
+	}, PrePromotionLabels(podHash))
+	assert.Equal(t, map[string]string{
+		v1alpha1.DefaultRolloutUniqueLabelKey: podHash,
+		v1alpha1.RolloutTypeLabel:             v1alpha1.RolloutTypePostPromotionLabel,
+	}, PostPromotionLabels(podHash))
+}
+
 func TestValidateMetrics(t *testing.T) {
 	{
 		spec := v1alpha1.AnalysisTemplateSpec{
@@ -233,4 +341,42 @@ func TestValidateMetrics(t *testing.T) {
 		err := ValidateAnalysisTemplateSpec(spec)
 		assert.EqualError(t, err, "metrics[0]: multiple providers specified")
 	}
+	{
+		spec := v1alpha1.AnalysisTemplateSpec{
+			Metrics: []v1alpha1.Metric{
+				{
+					Name: "success-rate",
+					Provider: v1alpha1.MetricProvider{
+						Prometheus: &v1alpha1.PrometheusMetric{},
+						Web:        &v1alpha1.WebMetric{},
+						Datadog:    &v1alpha1.DatadogMetric{},
+						Kayenta:    &v1alpha1.KayentaMetric{},
+					},
+				},
+			},
+		}
+		err := ValidateAnalysisTemplateSpec(spec)
+		assert.EqualError(t, err, "metrics[0]: multiple providers specified")
+	}
+}
+
+func TestValidateArgs(t *testing.T) {
+	spec := v1alpha1.AnalysisTemplateSpec{
+		Metrics: []v1alpha1.Metric{
+			{
+				Name: "success-rate",
+				Provider: v1alpha1.MetricProvider{
+					Prometheus: &v1alpha1.PrometheusMetric{},
+				},
+			},
+		},
+		Args: []v1alpha1.AnalysisTemplateArgument{{Name: "service"}, {Name: ""}},
+	}
+	assert.EqualError(t, ValidateAnalysisTemplateSpec(spec), "args[1]: name is required")
+
+	spec.Args[1].Name = "service"
+	assert.EqualError(t, ValidateAnalysisTemplateSpec(spec), "args[1]: duplicate name 'service'")
+
+	spec.Args[1].Name = "namespace"
+	assert.NoError(t, ValidateAnalysisTemplateSpec(spec))
 }
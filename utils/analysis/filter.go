@@ -6,10 +6,29 @@ import (
 
 //GetCurrentStepAnalysisRun filters the currentArs and returns the step based analysis run
 func GetCurrentStepAnalysisRun(currentArs []*v1alpha1.AnalysisRun) *v1alpha1.AnalysisRun {
+	return getCurrentAnalysisRunByType(currentArs, v1alpha1.RolloutTypeStepLabel)
+}
+
+// GetCurrentBackgroundAnalysisRun filters the currentArs and returns the background based analysis run
+func GetCurrentBackgroundAnalysisRun(currentArs []*v1alpha1.AnalysisRun) *v1alpha1.AnalysisRun {
+	return getCurrentAnalysisRunByType(currentArs, v1alpha1.RolloutTypeBackgroundRunLabel)
+}
+
+// GetCurrentPrePromotionAnalysisRun filters the currentArs and returns the blueGreen pre-promotion analysis run
+func GetCurrentPrePromotionAnalysisRun(currentArs []*v1alpha1.AnalysisRun) *v1alpha1.AnalysisRun {
+	return getCurrentAnalysisRunByType(currentArs, v1alpha1.RolloutTypePrePromotionLabel)
+}
+
+// GetCurrentPostPromotionAnalysisRun filters the currentArs and returns the blueGreen post-promotion analysis run
+func GetCurrentPostPromotionAnalysisRun(currentArs []*v1alpha1.AnalysisRun) *v1alpha1.AnalysisRun {
+	return getCurrentAnalysisRunByType(currentArs, v1alpha1.RolloutTypePostPromotionLabel)
+}
+
+func getCurrentAnalysisRunByType(currentArs []*v1alpha1.AnalysisRun, typeFilter string) *v1alpha1.AnalysisRun {
 	for i := range currentArs {
 		ar := currentArs[i]
 		rolloutType, ok := ar.Labels[v1alpha1.RolloutTypeLabel]
-		if ok && rolloutType == v1alpha1.RolloutTypeStepLabel {
+		if ok && rolloutType == typeFilter {
 			return ar
 		}
 	}
@@ -25,6 +44,12 @@ func FilterCurrentRolloutAnalysisRuns(analysisRuns []*v1alpha1.AnalysisRun, r *v
 		if ar.Name == r.Status.Canary.CurrentBackgroundAnalysisRun {
 			return true
 		}
+		if ar.Name == r.Status.BlueGreen.PrePromotionAnalysisRun {
+			return true
+		}
+		if ar.Name == r.Status.BlueGreen.PostPromotionAnalysisRun {
+			return true
+		}
 		return false
 	})
 }
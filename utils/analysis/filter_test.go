@@ -36,6 +36,56 @@ func TestGetCurrentStepAnalysisRun(t *testing.T) {
 	assert.Nil(t, currAr)
 }
 
+func TestGetCurrentBackgroundAnalysisRun(t *testing.T) {
+	arsWithBackground := []*v1alpha1.AnalysisRun{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+				Labels: map[string]string{
+					v1alpha1.RolloutTypeLabel: v1alpha1.RolloutTypeStepLabel,
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "bar",
+				Labels: map[string]string{
+					v1alpha1.RolloutTypeLabel: v1alpha1.RolloutTypeBackgroundRunLabel,
+				},
+			},
+		},
+	}
+	currAr := GetCurrentBackgroundAnalysisRun(arsWithBackground)
+	assert.Equal(t, arsWithBackground[1], currAr)
+	currAr = GetCurrentBackgroundAnalysisRun(arsWithBackground[:1])
+	assert.Nil(t, currAr)
+}
+
+func TestGetCurrentPromotionAnalysisRuns(t *testing.T) {
+	ars := []*v1alpha1.AnalysisRun{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+				Labels: map[string]string{
+					v1alpha1.RolloutTypeLabel: v1alpha1.RolloutTypePrePromotionLabel,
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "bar",
+				Labels: map[string]string{
+					v1alpha1.RolloutTypeLabel: v1alpha1.RolloutTypePostPromotionLabel,
+				},
+			},
+		},
+	}
+	assert.Equal(t, ars[0], GetCurrentPrePromotionAnalysisRun(ars))
+	assert.Equal(t, ars[1], GetCurrentPostPromotionAnalysisRun(ars))
+	assert.Nil(t, GetCurrentPrePromotionAnalysisRun(ars[1:]))
+	assert.Nil(t, GetCurrentPostPromotionAnalysisRun(ars[:1]))
+}
+
 func TestFilterCurrentRolloutAnalysisRuns(t *testing.T) {
 	ars := []*v1alpha1.AnalysisRun{
 		{
@@ -68,6 +118,20 @@ func TestFilterCurrentRolloutAnalysisRuns(t *testing.T) {
 	assert.Len(t, nonCurrentArs, 1)
 	assert.Contains(t, currentArs, ars[0])
 	assert.Contains(t, currentArs, ars[1])
+
+	blueGreen := &v1alpha1.Rollout{
+		Status: v1alpha1.RolloutStatus{
+			BlueGreen: v1alpha1.BlueGreenStatus{
+				PrePromotionAnalysisRun:  "foo",
+				PostPromotionAnalysisRun: "baz",
+			},
+		},
+	}
+	currentArs, nonCurrentArs = FilterCurrentRolloutAnalysisRuns(ars, blueGreen)
+	assert.Len(t, currentArs, 2)
+	assert.Len(t, nonCurrentArs, 1)
+	assert.Contains(t, currentArs, ars[0])
+	assert.Contains(t, currentArs, ars[2])
 }
 
 func TestFilterAnalysisRunsByName(t *testing.T) {
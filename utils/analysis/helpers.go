@@ -1,6 +1,9 @@
 package analysis
 
 import (
+	"fmt"
+	"reflect"
+
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 )
 
@@ -89,3 +92,161 @@ func LastMeasurement(run *v1alpha1.AnalysisRun, metricName string) *v1alpha1.Mea
 	}
 	return nil
 }
+
+// GetTemplateRefs returns the references to the templates of an analysis step, including the single templateName
+func GetTemplateRefs(step *v1alpha1.RolloutAnalysisStep) []v1alpha1.RolloutAnalysisTemplate {
+	var refs []v1alpha1.RolloutAnalysisTemplate
+	if step.TemplateName != "" {
+		refs = append(refs, v1alpha1.RolloutAnalysisTemplate{
+			TemplateName: step.TemplateName,
+			ClusterScope: step.ClusterScope,
+		})
+	}
+	return append(refs, step.Templates...)
+}
+
+// MergeAnalysisTemplateSpecs combines the metrics and the arguments of multiple templates into a single spec. An error
+// is returned if two templates define a metric with the same name, or declare the same argument with different defaults.
+func MergeAnalysisTemplateSpecs(specs []*v1alpha1.AnalysisTemplateSpec) (*v1alpha1.AnalysisTemplateSpec, error) {
+	merged := &v1alpha1.AnalysisTemplateSpec{}
+	metricNames := map[string]bool{}
+	for _, spec := range specs {
+		for _, metric := range spec.Metrics {
+			if metricNames[metric.Name] {
+				return nil, fmt.Errorf("duplicate metric name '%s' across analysis templates", metric.Name)
+			}
+			metricNames[metric.Name] = true
+			merged.Metrics = append(merged.Metrics, *metric.DeepCopy())
+		}
+	}
+	args, err := MergeAnalysisTemplateArgs(specs)
+	if err != nil {
+		return nil, err
+	}
+	merged.Args = args
+	return merged, nil
+}
+
+// ArgumentErrorReason is the reason why the arguments passed to an analysis do not match its templates
+type ArgumentErrorReason string
+
+const (
+	// ArgumentConflicting indicates the templates declare the argument with different defaults
+	ArgumentConflicting ArgumentErrorReason = "Conflicting"
+	// ArgumentUndeclared indicates the argument is passed but is not declared by the templates
+	ArgumentUndeclared ArgumentErrorReason = "Undeclared"
+	// ArgumentMissing indicates the argument is required by the templates but is not passed
+	ArgumentMissing ArgumentErrorReason = "Missing"
+)
+
+// ArgumentError is returned when the arguments passed to an analysis do not match the arguments declared by its
+// templates
+type ArgumentError struct {
+	Name   string
+	Reason ArgumentErrorReason
+}
+
+func (e *ArgumentError) Error() string {
+	switch e.Reason {
+	case ArgumentConflicting:
+		return fmt.Sprintf("argument '%s' is declared with different defaults across analysis templates", e.Name)
+	case ArgumentUndeclared:
+		return fmt.Sprintf("argument '%s' is not declared by the analysis templates", e.Name)
+	default:
+		return fmt.Sprintf("argument '%s' is required but was not passed", e.Name)
+	}
+}
+
+// MergeAnalysisTemplateArgs combines the arguments declared by multiple templates. An argument declared by several
+// templates is only kept once, and an error is returned if the templates declare it with different defaults.
+func MergeAnalysisTemplateArgs(specs []*v1alpha1.AnalysisTemplateSpec) ([]v1alpha1.AnalysisTemplateArgument, error) {
+	var merged []v1alpha1.AnalysisTemplateArgument
+	declared := map[string]*v1alpha1.AnalysisTemplateArgument{}
+	for _, spec := range specs {
+		for i := range spec.Args {
+			arg := spec.Args[i]
+			if prev, ok := declared[arg.Name]; ok {
+				if !reflect.DeepEqual(prev.Default, arg.Default) {
+					return nil, &ArgumentError{Name: arg.Name, Reason: ArgumentConflicting}
+				}
+				continue
+			}
+			declared[arg.Name] = &arg
+			merged = append(merged, *arg.DeepCopy())
+		}
+	}
+	return merged, nil
+}
+
+// ResolveArgs validates the arguments passed to an analysis run against the arguments declared by its templates, and
+// adds the default value of the declared arguments which are not passed. The arguments are returned unchanged when the
+// templates do not declare any argument.
+func ResolveArgs(declared []v1alpha1.AnalysisTemplateArgument, args []v1alpha1.Argument) ([]v1alpha1.Argument, error) {
+	if len(declared) == 0 {
+		return args, nil
+	}
+	declaredNames := map[string]bool{}
+	for _, arg := range declared {
+		declaredNames[arg.Name] = true
+	}
+	passed := map[string]bool{}
+	resolved := make([]v1alpha1.Argument, 0, len(declared))
+	for _, arg := range args {
+		if !declaredNames[arg.Name] {
+			return nil, &ArgumentError{Name: arg.Name, Reason: ArgumentUndeclared}
+		}
+		passed[arg.Name] = true
+		resolved = append(resolved, *arg.DeepCopy())
+	}
+	for _, arg := range declared {
+		if passed[arg.Name] {
+			continue
+		}
+		if arg.Default == nil {
+			return nil, &ArgumentError{Name: arg.Name, Reason: ArgumentMissing}
+		}
+		resolved = append(resolved, v1alpha1.Argument{
+			Name:  arg.Name,
+			Value: *arg.Default,
+		})
+	}
+	return resolved, nil
+}
+
+// ResolveTemplateArgs merges the arguments declared by multiple templates and resolves the passed arguments against
+// them. A template which does not declare any argument accepts any argument, so when only some of the templates
+// declare arguments, the passed arguments they do not declare are added to the declared arguments without a default
+// instead of being rejected. It returns the declared arguments along with the resolved arguments.
+func ResolveTemplateArgs(specs []*v1alpha1.AnalysisTemplateSpec, args []v1alpha1.Argument) ([]v1alpha1.AnalysisTemplateArgument, []v1alpha1.Argument, error) {
+	declared, err := MergeAnalysisTemplateArgs(specs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(declared) > 0 && acceptsAnyArg(specs) {
+		declaredNames := map[string]bool{}
+		for _, arg := range declared {
+			declaredNames[arg.Name] = true
+		}
+		for _, arg := range args {
+			if !declaredNames[arg.Name] {
+				declaredNames[arg.Name] = true
+				declared = append(declared, v1alpha1.AnalysisTemplateArgument{Name: arg.Name})
+			}
+		}
+	}
+	resolved, err := ResolveArgs(declared, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return declared, resolved, nil
+}
+
+// acceptsAnyArg returns whether one of the templates does not declare any argument
+func acceptsAnyArg(specs []*v1alpha1.AnalysisTemplateSpec) bool {
+	for _, spec := range specs {
+		if len(spec.Args) == 0 {
+			return true
+		}
+	}
+	return false
+}
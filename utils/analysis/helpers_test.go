@@ -171,3 +171,136 @@ func TestIsTerminating(t *testing.T) {
 	run.Status.MetricResults[1] = successRate
 	assert.True(t, IsTerminating(run))
 }
+
+func TestGetTemplateRefs(t *testing.T) {
+	step := &v1alpha1.RolloutAnalysisStep{
+		TemplateName: "foo",
+		ClusterScope: true,
+		Templates: []v1alpha1.RolloutAnalysisTemplate{
+			{TemplateName: "bar"},
+		},
+	}
+	refs := GetTemplateRefs(step)
+	assert.Equal(t, []v1alpha1.RolloutAnalysisTemplate{
+		{TemplateName: "foo", ClusterScope: true},
+		{TemplateName: "bar"},
+	}, refs)
+
+	assert.Empty(t, GetTemplateRefs(&v1alpha1.RolloutAnalysisStep{}))
+}
+
+func TestMergeAnalysisTemplateSpecs(t *testing.T) {
+	foo := &v1alpha1.AnalysisTemplateSpec{
+		Metrics: []v1alpha1.Metric{{Name: "foo"}},
+	}
+	bar := &v1alpha1.AnalysisTemplateSpec{
+		Metrics: []v1alpha1.Metric{{Name: "bar"}, {Name: "baz"}},
+	}
+	merged, err := MergeAnalysisTemplateSpecs([]*v1alpha1.AnalysisTemplateSpec{foo, bar})
+	assert.NoError(t, err)
+	assert.Len(t, merged.Metrics, 3)
+	assert.Equal(t, "foo", merged.Metrics[0].Name)
+	assert.Equal(t, "baz", merged.Metrics[2].Name)
+
+	merged, err = MergeAnalysisTemplateSpecs([]*v1alpha1.AnalysisTemplateSpec{foo, foo})
+	assert.Nil(t, merged)
+	assert.EqualError(t, err, "duplicate metric name 'foo' across analysis templates")
+}
+
+func TestMergeAnalysisTemplateArgs(t *testing.T) {
+	defaultValue := "guestbook"
+	otherValue := "other"
+	foo := &v1alpha1.AnalysisTemplateSpec{
+		Args: []v1alpha1.AnalysisTemplateArgument{{Name: "service"}, {Name: "app", Default: &defaultValue}},
+	}
+	bar := &v1alpha1.AnalysisTemplateSpec{
+		Args: []v1alpha1.AnalysisTemplateArgument{{Name: "app", Default: &defaultValue}, {Name: "namespace"}},
+	}
+	args, err := MergeAnalysisTemplateArgs([]*v1alpha1.AnalysisTemplateSpec{foo, bar})
+	assert.NoError(t, err)
+	assert.Equal(t, []v1alpha1.AnalysisTemplateArgument{
+		{Name: "service"},
+		{Name: "app", Default: &defaultValue},
+		{Name: "namespace"},
+	}, args)
+
+	bar.Args[0].Default = &otherValue
+	_, err = MergeAnalysisTemplateArgs([]*v1alpha1.AnalysisTemplateSpec{foo, bar})
+	assert.EqualError(t, err, "argument 'app' is declared with different defaults across analysis templates")
+
+	merged, err := MergeAnalysisTemplateSpecs([]*v1alpha1.AnalysisTemplateSpec{foo, bar})
+	assert.Nil(t, merged)
+	assert.EqualError(t, err, "argument 'app' is declared with different defaults across analysis templates")
+}
+
+func TestResolveArgs(t *testing.T) {
+	defaultValue := "guestbook"
+	declared := []v1alpha1.AnalysisTemplateArgument{
+		{Name: "service"},
+		{Name: "app", Default: &defaultValue},
+	}
+
+	args, err := ResolveArgs(declared, []v1alpha1.Argument{{Name: "service", Value: "guestbook-svc"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []v1alpha1.Argument{
+		{Name: "service", Value: "guestbook-svc"},
+		{Name: "app", Value: "guestbook"},
+	}, args)
+
+	args, err = ResolveArgs(declared, []v1alpha1.Argument{{Name: "app", Value: "other"}, {Name: "service", Value: "guestbook-svc"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []v1alpha1.Argument{
+		{Name: "app", Value: "other"},
+		{Name: "service", Value: "guestbook-svc"},
+	}, args)
+
+	_, err = ResolveArgs(declared, nil)
+	assert.EqualError(t, err, "argument 'service' is required but was not passed")
+
+	_, err = ResolveArgs(declared, []v1alpha1.Argument{{Name: "service", Value: "guestbook-svc"}, {Name: "unknown", Value: "foo"}})
+	assert.EqualError(t, err, "argument 'unknown' is not declared by the analysis templates")
+
+	// the arguments are not validated when the templates do not declare any
+	undeclared := []v1alpha1.Argument{{Name: "unknown", Value: "foo"}}
+	args, err = ResolveArgs(nil, undeclared)
+	assert.NoError(t, err)
+	assert.Equal(t, undeclared, args)
+}
+
+func TestResolveTemplateArgs(t *testing.T) {
+	defaultValue := "guestbook"
+	foo := &v1alpha1.AnalysisTemplateSpec{
+		Args: []v1alpha1.AnalysisTemplateArgument{{Name: "service"}, {Name: "app", Default: &defaultValue}},
+	}
+	bar := &v1alpha1.AnalysisTemplateSpec{}
+	passed := []v1alpha1.Argument{{Name: "service", Value: "guestbook-svc"}, {Name: "namespace", Value: "default"}}
+
+	_, _, err := ResolveTemplateArgs([]*v1alpha1.AnalysisTemplateSpec{foo}, passed)
+	assert.EqualError(t, err, "argument 'namespace' is not declared by the analysis templates")
+	assert.Equal(t, &ArgumentError{Name: "namespace", Reason: ArgumentUndeclared}, err)
+
+	// bar accepts any argument, so the arguments foo does not declare are declared without a default
+	declared, args, err := ResolveTemplateArgs([]*v1alpha1.AnalysisTemplateSpec{foo, bar}, passed)
+	assert.NoError(t, err)
+	assert.Equal(t, []v1alpha1.AnalysisTemplateArgument{
+		{Name: "service"},
+		{Name: "app", Default: &defaultValue},
+		{Name: "namespace"},
+	}, declared)
+	assert.Equal(t, []v1alpha1.Argument{
+		{Name: "service", Value: "guestbook-svc"},
+		{Name: "namespace", Value: "default"},
+		{Name: "app", Value: "guestbook"},
+	}, args)
+	resolved, err := ResolveArgs(declared, args)
+	assert.NoError(t, err)
+	assert.Equal(t, args, resolved)
+
+	_, _, err = ResolveTemplateArgs([]*v1alpha1.AnalysisTemplateSpec{foo, bar}, passed[1:])
+	assert.Equal(t, &ArgumentError{Name: "service", Reason: ArgumentMissing}, err)
+
+	declared, args, err = ResolveTemplateArgs([]*v1alpha1.AnalysisTemplateSpec{bar}, passed)
+	assert.NoError(t, err)
+	assert.Nil(t, declared)
+	assert.Equal(t, passed, args)
+}
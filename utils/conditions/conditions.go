@@ -11,6 +11,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/rand"
@@ -18,6 +19,7 @@ import (
 	hashutil "k8s.io/kubernetes/pkg/util/hash"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
+	analysisutil "github.com/argoproj/argo-rollouts/utils/analysis"
 	"github.com/argoproj/argo-rollouts/utils/defaults"
 	logutil "github.com/argoproj/argo-rollouts/utils/log"
 )
@@ -47,8 +49,34 @@ const (
 	InvalidStrategyMessage = "Multiple Strategies can not be listed"
 	// DuplicatedServicesMessage the message to indicate that the rollout uses the same service for the active and preview services
 	DuplicatedServicesMessage = "This rollout uses the same service for the active and preview services, but two different services are required."
+	// DuplicatedCanaryServicesMessage the message to indicate that the rollout uses the same service for the stable and canary services
+	DuplicatedCanaryServicesMessage = "This rollout uses the same service for the stable and canary services, but two different services are required."
 	// ScaleDownLimitLargerThanRevisionLimit the message to indicate that the rollout's revision history limit can not be smaller than the rollout's scale down limit
 	ScaleDownLimitLargerThanRevisionLimit = "This rollout's revision history limit can not be smaller than the rollout's scale down limit"
+	// InvalidAntiAffinityMessage indicates the anti-affinity of the rollout strategy does not set exactly one mode
+	InvalidAntiAffinityMessage = "AntiAffinity in '%s' must set exactly one of requiredDuringSchedulingIgnoredDuringExecution or preferredDuringSchedulingIgnoredDuringExecution"
+	// InvalidAntiAffinityWeightMessage indicates the weight of the preferred anti-affinity is out of range
+	InvalidAntiAffinityWeightMessage = "AntiAffinity in '%s' must have a weight between 1 and 100"
+	// InvalidWorkloadRefMessage indicates the workloadRef references an unsupported kind of workload
+	InvalidWorkloadRefMessage = "WorkloadRef must reference a Deployment with the apiVersion apps/v1"
+	// WorkloadRefWithTemplateMessage indicates the rollout sets both the workloadRef and the template
+	WorkloadRefWithTemplateMessage = "Template must be empty when WorkloadRef is set"
+	// WorkloadRefNotFoundMessage indicates the workload referenced by the workloadRef does not exist
+	WorkloadRefNotFoundMessage = "Deployment %q referenced by WorkloadRef is not found"
+	// DuplicatedAnalysisTemplateMessage indicates an analysis references the same template more than once
+	DuplicatedAnalysisTemplateMessage = "Analysis '%s' references the template '%s' multiple times"
+	// DuplicatedAnalysisArgumentMessage indicates an analysis declares the same argument more than once
+	DuplicatedAnalysisArgumentMessage = "Analysis '%s' declares the argument '%s' multiple times"
+	// InvalidAnalysisArgumentMessage indicates an argument of an analysis has an invalid value source
+	InvalidAnalysisArgumentMessage = "Analysis '%s' has an invalid argument '%s': %s"
+	// ConflictingAnalysisArgumentMessage indicates the templates of an analysis declare the same argument with different defaults
+	ConflictingAnalysisArgumentMessage = "Analysis '%s' has templates which declare the argument '%s' with different defaults"
+	// UndeclaredAnalysisArgumentMessage indicates an analysis passes an argument which its templates do not declare
+	UndeclaredAnalysisArgumentMessage = "Analysis '%s' passes the argument '%s' which is not declared by its templates"
+	// MissingAnalysisArgumentMessage indicates an analysis does not pass an argument its templates require
+	MissingAnalysisArgumentMessage = "Analysis '%s' does not pass the argument '%s' required by its templates"
+	// DuplicatedAnalysisMetricMessage indicates the templates of an analysis define metrics with the same name
+	DuplicatedAnalysisMetricMessage = "Analysis '%s' has templates which define the metric '%s' multiple times"
 	// AvailableReason the reason to indicate that the rollout is serving traffic from the active service
 	AvailableReason = "AvailableReason"
 	// NotAvailableMessage the message to indicate that the Rollout does not have min availability
@@ -81,6 +109,25 @@ const (
 	// FoundNewRSMessage is added in a rollout when it adopts an existing replica set.
 	FoundNewRSMessage = "Found new replica set %q"
 
+	// RollbackDoneReason is added in an event when the rollout is rolled back to a previous revision
+	RollbackDoneReason = "RollbackDone"
+	// RollbackDoneMessage is added in an event when the rollout is rolled back to a previous revision
+	RollbackDoneMessage = "Rolled back rollout to revision %d"
+	// RollbackRevisionNotFoundReason is added in an event when the revision to roll back to is not found
+	RollbackRevisionNotFoundReason = "RollbackRevisionNotFound"
+	// RollbackLastRevisionNotFoundMessage is added in an event when the rollout has no previous revision
+	RollbackLastRevisionNotFoundMessage = "Unable to find last revision"
+	// RollbackRevisionNotFoundMessage is added in an event when the revision to roll back to is not found
+	RollbackRevisionNotFoundMessage = "Unable to find revision %d to roll back to"
+	// RollbackTemplateUnchangedReason is added in an event when the revision to roll back to is the current revision
+	RollbackTemplateUnchangedReason = "RollbackTemplateUnchanged"
+	// RollbackTemplateUnchangedMessage is added in an event when the revision to roll back to is the current revision
+	RollbackTemplateUnchangedMessage = "The rollback revision contains the same template as the current rollout"
+	// RollbackWorkloadRefReason is added in an event when a rollout using a workloadRef is rolled back
+	RollbackWorkloadRefReason = "RollbackWorkloadRef"
+	// RollbackWorkloadRefMessage is added in an event when a rollout using a workloadRef is rolled back
+	RollbackWorkloadRefMessage = "A rollout referencing the template of a workload cannot be rolled back, roll back the workload instead"
+
 	// NewRSAvailableReason is added in a rollout when its newest replica set is made available
 	// ie. the number of new pods that have passed readiness checks and run for at least minReadySeconds
 	// is at least the minimum available pods that need to run for the rollout.
@@ -89,6 +136,10 @@ const (
 	RolloutAnalysisRunFailedReason = "AnalysisRunFailed"
 	// RolloutAnalysisRunFailedMessage is added in a rollout when the analysisRun owned by a rollout fails or errors out
 	RolloutAnalysisRunFailedMessage = "AnalysisRun '%s' owned by the Rollout '%q' failed."
+	// RolloutAbortedReason is added in a rollout when it is aborted because an AnalysisRun owned by the rollout failed
+	RolloutAbortedReason = "RolloutAborted"
+	// RolloutAbortedMessage is added in a rollout when it is aborted because an AnalysisRun owned by the rollout failed
+	RolloutAbortedMessage = "Rollout is aborted"
 	// RolloutExperimentFailedReason is added in a rollout when the analysisRun owned by a rollout fails to show any progress
 	RolloutExperimentFailedReason = "ExperimentFailed"
 	// RolloutExperimentFailedMessage is added in a rollout when the experiment owned by a rollout fails to show any progress
@@ -265,8 +316,13 @@ func ComputeStepHash(rollout *v1alpha1.Rollout) string {
 }
 
 // ComputeGenerationHash returns a hash value calculated from the Rollout Spec. The hash will
-// be safe encoded to avoid bad words.
+// be safe encoded to avoid bad words. The template of a rollout with a workloadRef is left out, since
+// the controller resolves it from the Deployment and never stores it in the rollout. Changes to the
+// resolved template are picked up through the pod template hash instead.
 func ComputeGenerationHash(spec v1alpha1.RolloutSpec) string {
+	if spec.WorkloadRef != nil {
+		spec.Template = corev1.PodTemplateSpec{}
+	}
 	rolloutSpecHasher := fnv.New32a()
 	hashutil.DeepHashObject(rolloutSpecHasher, spec)
 	return rand.SafeEncodeString(fmt.Sprint(rolloutSpecHasher.Sum32()))
@@ -292,6 +348,19 @@ func VerifyRolloutSpec(rollout *v1alpha1.Rollout, prevCond *v1alpha1.RolloutCond
 		return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, RolloutSelectAllMessage)
 	}
 
+	if workloadRef := rollout.Spec.WorkloadRef; workloadRef != nil {
+		if workloadRef.Name == "" {
+			message := fmt.Sprintf(MissingFieldMessage, ".Spec.WorkloadRef.Name")
+			return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+		}
+		if workloadRef.APIVersion != "apps/v1" || workloadRef.Kind != "Deployment" {
+			return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, InvalidWorkloadRefMessage)
+		}
+		if !reflect.DeepEqual(rollout.Spec.Template, corev1.PodTemplateSpec{}) {
+			return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, WorkloadRefWithTemplateMessage)
+		}
+	}
+
 	if rollout.Spec.Strategy.CanaryStrategy == nil && rollout.Spec.Strategy.BlueGreenStrategy == nil {
 		message := fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.CanaryStrategy or .Spec.Strategy.BlueGreen")
 		return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
@@ -317,12 +386,45 @@ func VerifyRolloutSpec(rollout *v1alpha1.Rollout, prevCond *v1alpha1.RolloutCond
 		if rollout.Spec.Strategy.BlueGreenStrategy.ScaleDownDelayRevisionLimit != nil && revisionHistoryLimit < *rollout.Spec.Strategy.BlueGreenStrategy.ScaleDownDelayRevisionLimit {
 			return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, ScaleDownLimitLargerThanRevisionLimit)
 		}
+		if message := verifyAntiAffinity(".Spec.Strategy.BlueGreenStrategy.AntiAffinity", rollout.Spec.Strategy.BlueGreenStrategy.AntiAffinity); message != "" {
+			return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+		}
 	}
 
 	if rollout.Spec.Strategy.CanaryStrategy != nil {
 		if invalidMaxSurgeMaxUnavailable(rollout) {
 			return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, InvalidMaxSurgeMaxUnavailable)
 		}
+		if message := verifyAntiAffinity(".Spec.Strategy.CanaryStrategy.AntiAffinity", rollout.Spec.Strategy.CanaryStrategy.AntiAffinity); message != "" {
+			return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+		}
+		if trafficRouting := rollout.Spec.Strategy.CanaryStrategy.TrafficRouting; trafficRouting != nil {
+			if rollout.Spec.Strategy.CanaryStrategy.CanaryService == "" {
+				message := fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.CanaryStrategy.CanaryService")
+				return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+			}
+			if rollout.Spec.Strategy.CanaryStrategy.StableService == "" {
+				message := fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.CanaryStrategy.StableService")
+				return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+			}
+			if rollout.Spec.Strategy.CanaryStrategy.CanaryService == rollout.Spec.Strategy.CanaryStrategy.StableService {
+				return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, DuplicatedCanaryServicesMessage)
+			}
+			if trafficRouting.Istio != nil {
+				if trafficRouting.Istio.VirtualService.Name == "" {
+					message := fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.CanaryStrategy.TrafficRouting.Istio.VirtualService.Name")
+					return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+				}
+				if len(trafficRouting.Istio.VirtualService.Routes) == 0 {
+					message := fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.CanaryStrategy.TrafficRouting.Istio.VirtualService.Routes")
+					return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+				}
+			}
+			if trafficRouting.Nginx != nil && trafficRouting.Nginx.StableIngress == "" {
+				message := fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.CanaryStrategy.TrafficRouting.Nginx.StableIngress")
+				return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+			}
+		}
 		for _, step := range rollout.Spec.Strategy.CanaryStrategy.Steps {
 			if hasMultipleStepsType(step) {
 				return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, InvalidStepMessage)
@@ -339,6 +441,177 @@ func VerifyRolloutSpec(rollout *v1alpha1.Rollout, prevCond *v1alpha1.RolloutCond
 		}
 	}
 
+	for _, analysis := range getRolloutAnalyses(rollout) {
+		if message := verifyRolloutAnalysis(analysis.path, analysis.step); message != "" {
+			return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+		}
+	}
+
+	return nil
+}
+
+// verifyAntiAffinity returns a message if the anti-affinity does not set exactly one mode or has an invalid weight
+func verifyAntiAffinity(path string, antiAffinity *v1alpha1.AntiAffinity) string {
+	if antiAffinity == nil {
+		return ""
+	}
+	preferred := antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	required := antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if (preferred == nil) == (required == nil) {
+		return fmt.Sprintf(InvalidAntiAffinityMessage, path)
+	}
+	if preferred != nil && (preferred.Weight < 1 || preferred.Weight > 100) {
+		return fmt.Sprintf(InvalidAntiAffinityWeightMessage, path)
+	}
+	return ""
+}
+
+// VerifyRolloutAnalysisTemplates checks that the templates referenced by each analysis of the rollout can be merged
+// into a single AnalysisRun otherwise returns a invalidSpec condition. The getTemplateSpec func resolves a template
+// reference, and the references which cannot be resolved are skipped.
+func VerifyRolloutAnalysisTemplates(rollout *v1alpha1.Rollout, prevCond *v1alpha1.RolloutCondition, getTemplateSpec func(v1alpha1.RolloutAnalysisTemplate) (*v1alpha1.AnalysisTemplateSpec, error)) *v1alpha1.RolloutCondition {
+	for _, analysis := range getRolloutAnalyses(rollout) {
+		metricNames := map[string]bool{}
+		var specs []*v1alpha1.AnalysisTemplateSpec
+		for _, ref := range analysisutil.GetTemplateRefs(analysis.step) {
+			spec, err := getTemplateSpec(ref)
+			if err != nil || spec == nil {
+				continue
+			}
+			for _, metric := range spec.Metrics {
+				if metricNames[metric.Name] {
+					message := fmt.Sprintf(DuplicatedAnalysisMetricMessage, analysis.path, metric.Name)
+					return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+				}
+				metricNames[metric.Name] = true
+			}
+			specs = append(specs, spec)
+		}
+		if message := verifyRolloutAnalysisArguments(analysis, specs); message != "" {
+			return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+		}
+	}
+	return nil
+}
+
+// VerifyRolloutWorkloadRef checks the Deployment referenced by the workloadRef exists, otherwise returns an
+// invalidSpec condition.
+func VerifyRolloutWorkloadRef(rollout *v1alpha1.Rollout, prevCond *v1alpha1.RolloutCondition, getDeployment func(name string) (*appsv1.Deployment, error)) *v1alpha1.RolloutCondition {
+	if rollout.Spec.WorkloadRef == nil {
+		return nil
+	}
+	_, err := getDeployment(rollout.Spec.WorkloadRef.Name)
+	if k8serrors.IsNotFound(err) {
+		message := fmt.Sprintf(WorkloadRefNotFoundMessage, rollout.Spec.WorkloadRef.Name)
+		return newInvalidSpecRolloutCondition(prevCond, InvalidSpecReason, message)
+	}
+	return nil
+}
+
+// verifyRolloutAnalysisArguments returns the message describing why the arguments of the analysis do not match the
+// arguments declared by its templates, or an empty string if they match
+func verifyRolloutAnalysisArguments(analysis rolloutAnalysis, specs []*v1alpha1.AnalysisTemplateSpec) string {
+	args := make([]v1alpha1.Argument, len(analysis.step.Arguments))
+	for i, arg := range analysis.step.Arguments {
+		args[i] = v1alpha1.Argument{Name: arg.Name}
+	}
+	_, _, err := analysisutil.ResolveTemplateArgs(specs, args)
+	argErr, ok := err.(*analysisutil.ArgumentError)
+	if !ok {
+		return ""
+	}
+	switch argErr.Reason {
+	case analysisutil.ArgumentConflicting:
+		return fmt.Sprintf(ConflictingAnalysisArgumentMessage, analysis.path, argErr.Name)
+	case analysisutil.ArgumentUndeclared:
+		return fmt.Sprintf(UndeclaredAnalysisArgumentMessage, analysis.path, argErr.Name)
+	default:
+		return fmt.Sprintf(MissingAnalysisArgumentMessage, analysis.path, argErr.Name)
+	}
+}
+
+type rolloutAnalysis struct {
+	path string
+	step *v1alpha1.RolloutAnalysisStep
+}
+
+// getRolloutAnalyses returns all the analyses defined in the rollout's strategy along with their path in the spec
+func getRolloutAnalyses(rollout *v1alpha1.Rollout) []rolloutAnalysis {
+	var analyses []rolloutAnalysis
+	if blueGreen := rollout.Spec.Strategy.BlueGreenStrategy; blueGreen != nil {
+		if blueGreen.PrePromotionAnalysis != nil {
+			analyses = append(analyses, rolloutAnalysis{".Spec.Strategy.BlueGreenStrategy.PrePromotionAnalysis", blueGreen.PrePromotionAnalysis})
+		}
+		if blueGreen.PostPromotionAnalysis != nil {
+			analyses = append(analyses, rolloutAnalysis{".Spec.Strategy.BlueGreenStrategy.PostPromotionAnalysis", blueGreen.PostPromotionAnalysis})
+		}
+	}
+	if canary := rollout.Spec.Strategy.CanaryStrategy; canary != nil {
+		if canary.Analysis != nil {
+			analyses = append(analyses, rolloutAnalysis{".Spec.Strategy.CanaryStrategy.Analysis", canary.Analysis})
+		}
+		for i, step := range canary.Steps {
+			if step.Analysis != nil {
+				analyses = append(analyses, rolloutAnalysis{fmt.Sprintf(".Spec.Strategy.CanaryStrategy.Steps[%d].Analysis", i), step.Analysis})
+			}
+		}
+	}
+	return analyses
+}
+
+// verifyRolloutAnalysis returns the message describing why the analysis is invalid, or an empty string if it is valid
+func verifyRolloutAnalysis(path string, step *v1alpha1.RolloutAnalysisStep) string {
+	refs := analysisutil.GetTemplateRefs(step)
+	if len(refs) == 0 {
+		return fmt.Sprintf(MissingFieldMessage, path+".TemplateName or "+path+".Templates")
+	}
+	templates := map[v1alpha1.RolloutAnalysisTemplate]bool{}
+	for _, ref := range refs {
+		if ref.TemplateName == "" {
+			return fmt.Sprintf(MissingFieldMessage, path+".Templates.TemplateName")
+		}
+		if templates[ref] {
+			return fmt.Sprintf(DuplicatedAnalysisTemplateMessage, path, ref.TemplateName)
+		}
+		templates[ref] = true
+	}
+	arguments := map[string]bool{}
+	for _, arg := range step.Arguments {
+		if arguments[arg.Name] {
+			return fmt.Sprintf(DuplicatedAnalysisArgumentMessage, path, arg.Name)
+		}
+		arguments[arg.Name] = true
+		if err := verifyAnalysisArgumentValueFrom(arg.ValueFrom); err != nil {
+			return fmt.Sprintf(InvalidAnalysisArgumentMessage, path, arg.Name, err.Error())
+		}
+	}
+	return ""
+}
+
+// verifyAnalysisArgumentValueFrom checks the value source of an argument sets exactly one supported reference
+func verifyAnalysisArgumentValueFrom(valueFrom *v1alpha1.ArgumentValueFrom) error {
+	if valueFrom == nil {
+		return nil
+	}
+	sources := 0
+	if valueFrom.PodTemplateHashValue != nil {
+		sources++
+	}
+	if valueFrom.FieldRef != nil {
+		sources++
+		if _, err := analysisutil.GetFieldRefValue(&v1alpha1.Rollout{}, valueFrom.FieldRef.FieldPath); err != nil {
+			return err
+		}
+	}
+	if valueFrom.SecretKeyRef != nil {
+		sources++
+		if valueFrom.SecretKeyRef.Name == "" || valueFrom.SecretKeyRef.Key == "" {
+			return fmt.Errorf("secretKeyRef requires a name and a key")
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("valueFrom must set exactly one of podTemplateHashValue, fieldRef or secretKeyRef")
+	}
 	return nil
 }
 
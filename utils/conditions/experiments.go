@@ -37,6 +37,10 @@ const (
 	ExperimentSelectAllMessage = "This experiment is selecting all pods at index %d. A non-empty selector is required."
 	// ExperimentMinReadyLongerThanDeadlineMessage indicates the MinReadySeconds is longer than ProgressDeadlineSeconds
 	ExperimentMinReadyLongerThanDeadlineMessage = "MinReadySeconds cannot be longer than ProgressDeadlineSeconds. Check template index %d"
+	// ExperimentAnalysisNameRepeatedMessage message when name in spec.analyses is repeated
+	ExperimentAnalysisNameRepeatedMessage = "Experiment %s has repeated analysis name '%s' in analyses"
+	// ExperimentAnalysisNameEmpty message when name in analysis is empty
+	ExperimentAnalysisNameEmpty = "Experiment %s has empty analysis name at index %d"
 )
 
 // NewExperimentConditions takes arguments to create new Condition
@@ -226,5 +230,23 @@ func VerifyExperimentSpec(experiment *v1alpha1.Experiment, prevCond *v1alpha1.Ex
 		}
 		templateNameSet[template.Name] = true
 	}
+	analysisNameSet := make(map[string]bool)
+	for i := range experiment.Spec.Analyses {
+		analysis := experiment.Spec.Analyses[i]
+		if analysis.Name == "" {
+			message := fmt.Sprintf(ExperimentAnalysisNameEmpty, experiment.Name, i)
+			return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+		}
+		if analysis.TemplateName == "" {
+			missingFieldPath := fmt.Sprintf(".Spec.Analyses[%d].TemplateName", i)
+			message := fmt.Sprintf(MissingFieldMessage, missingFieldPath)
+			return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+		}
+		if ok := analysisNameSet[analysis.Name]; ok {
+			message := fmt.Sprintf(ExperimentAnalysisNameRepeatedMessage, experiment.Name, analysis.Name)
+			return newInvalidSpecExperimentCondition(prevCond, InvalidSpecReason, message)
+		}
+		analysisNameSet[analysis.Name] = true
+	}
 	return nil
 }
@@ -529,3 +529,46 @@ func TestVerifyExperimentSpecBaseCases(t *testing.T) {
 	assert.Equal(t, InvalidSpecReason, sameInvalidSpec.Reason)
 	assert.NotEqual(t, prevLastUpdateTime, sameInvalidSpec.LastUpdateTime)
 }
+
+func TestVerifyExperimentSpecAnalyses(t *testing.T) {
+	ex := &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: v1alpha1.ExperimentSpec{
+			Templates: []v1alpha1.TemplateSpec{{
+				Name: "test",
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"key": "value"},
+				},
+			}},
+			Analyses: []v1alpha1.ExperimentAnalysisTemplateRef{{
+				Name:         "analysis",
+				TemplateName: "analysis-template",
+			}},
+		},
+	}
+	cond := VerifyExperimentSpec(ex, nil)
+	assert.Nil(t, cond)
+
+	sameNameAnalysis := ex.DeepCopy()
+	sameNameAnalysis.Spec.Analyses = append(sameNameAnalysis.Spec.Analyses, sameNameAnalysis.Spec.Analyses[0])
+	sameNameAnalysisCond := VerifyExperimentSpec(sameNameAnalysis, nil)
+	assert.NotNil(t, sameNameAnalysisCond)
+	assert.Equal(t, fmt.Sprintf(ExperimentAnalysisNameRepeatedMessage, ex.Name, "analysis"), sameNameAnalysisCond.Message)
+	assert.Equal(t, InvalidSpecReason, sameNameAnalysisCond.Reason)
+
+	noNameAnalysis := ex.DeepCopy()
+	noNameAnalysis.Spec.Analyses[0].Name = ""
+	noNameAnalysisCond := VerifyExperimentSpec(noNameAnalysis, nil)
+	assert.NotNil(t, noNameAnalysisCond)
+	assert.Equal(t, fmt.Sprintf(ExperimentAnalysisNameEmpty, ex.Name, 0), noNameAnalysisCond.Message)
+	assert.Equal(t, InvalidSpecReason, noNameAnalysisCond.Reason)
+
+	noTemplateName := ex.DeepCopy()
+	noTemplateName.Spec.Analyses[0].TemplateName = ""
+	noTemplateNameCond := VerifyExperimentSpec(noTemplateName, nil)
+	assert.NotNil(t, noTemplateNameCond)
+	assert.Equal(t, fmt.Sprintf(MissingFieldMessage, ".Spec.Analyses[0].TemplateName"), noTemplateNameCond.Message)
+	assert.Equal(t, InvalidSpecReason, noTemplateNameCond.Reason)
+}
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/kubernetes/pkg/controller"
@@ -265,6 +267,157 @@ func TestVerifyRolloutSpecBlueGreen(t *testing.T) {
 	assert.Equal(t, InvalidSpecReason, sameSvcsCond.Reason)
 }
 
+func TestVerifyRolloutSpecAntiAffinity(t *testing.T) {
+	path := ".Spec.Strategy.BlueGreenStrategy.AntiAffinity"
+	rollout := &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"key": "value"}},
+			Strategy: v1alpha1.RolloutStrategy{
+				BlueGreenStrategy: &v1alpha1.BlueGreenStrategy{
+					ActiveService: "active",
+					AntiAffinity: &v1alpha1.AntiAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &v1alpha1.RequiredDuringSchedulingIgnoredDuringExecution{},
+					},
+				},
+			},
+		},
+	}
+	assert.Nil(t, VerifyRolloutSpec(rollout, nil))
+
+	rollout.Spec.Strategy.BlueGreenStrategy.AntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = &v1alpha1.PreferredDuringSchedulingIgnoredDuringExecution{Weight: 50}
+	cond := VerifyRolloutSpec(rollout, nil)
+	assert.NotNil(t, cond)
+	assert.Equal(t, fmt.Sprintf(InvalidAntiAffinityMessage, path), cond.Message)
+
+	rollout.Spec.Strategy.BlueGreenStrategy.AntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = nil
+	assert.Nil(t, VerifyRolloutSpec(rollout, nil))
+
+	rollout.Spec.Strategy.BlueGreenStrategy.AntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution.Weight = 0
+	cond = VerifyRolloutSpec(rollout, nil)
+	assert.NotNil(t, cond)
+	assert.Equal(t, fmt.Sprintf(InvalidAntiAffinityWeightMessage, path), cond.Message)
+
+	rollout.Spec.Strategy.BlueGreenStrategy.AntiAffinity = &v1alpha1.AntiAffinity{}
+	cond = VerifyRolloutSpec(rollout, nil)
+	assert.NotNil(t, cond)
+	assert.Equal(t, fmt.Sprintf(InvalidAntiAffinityMessage, path), cond.Message)
+}
+
+func TestVerifyRolloutSpecWorkloadRef(t *testing.T) {
+	rollout := &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"key": "value"}},
+			WorkloadRef: &v1alpha1.WorkloadRef{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "foo",
+			},
+			Strategy: v1alpha1.RolloutStrategy{
+				BlueGreenStrategy: &v1alpha1.BlueGreenStrategy{
+					ActiveService: "active",
+				},
+			},
+		},
+	}
+	assert.Nil(t, VerifyRolloutSpec(rollout, nil))
+
+	missingName := rollout.DeepCopy()
+	missingName.Spec.WorkloadRef.Name = ""
+	cond := VerifyRolloutSpec(missingName, nil)
+	assert.NotNil(t, cond)
+	assert.Equal(t, fmt.Sprintf(MissingFieldMessage, ".Spec.WorkloadRef.Name"), cond.Message)
+
+	invalidKind := rollout.DeepCopy()
+	invalidKind.Spec.WorkloadRef.Kind = "StatefulSet"
+	cond = VerifyRolloutSpec(invalidKind, nil)
+	assert.NotNil(t, cond)
+	assert.Equal(t, InvalidWorkloadRefMessage, cond.Message)
+
+	withTemplate := rollout.DeepCopy()
+	withTemplate.Spec.Template.Spec.Containers = []v1.Container{{Image: "foo/bar"}}
+	cond = VerifyRolloutSpec(withTemplate, nil)
+	assert.NotNil(t, cond)
+	assert.Equal(t, WorkloadRefWithTemplateMessage, cond.Message)
+}
+
+func TestVerifyRolloutWorkloadRef(t *testing.T) {
+	rollout := &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			WorkloadRef: &v1alpha1.WorkloadRef{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "foo",
+			},
+		},
+	}
+	found := func(name string) (*appsv1.Deployment, error) {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+	}
+	notFound := func(name string) (*appsv1.Deployment, error) {
+		return nil, k8serrors.NewNotFound(appsv1.Resource("deployments"), name)
+	}
+	assert.Nil(t, VerifyRolloutWorkloadRef(rollout, nil, found))
+
+	cond := VerifyRolloutWorkloadRef(rollout, nil, notFound)
+	assert.NotNil(t, cond)
+	assert.Equal(t, fmt.Sprintf(WorkloadRefNotFoundMessage, "foo"), cond.Message)
+
+	rollout.Spec.WorkloadRef = nil
+	assert.Nil(t, VerifyRolloutWorkloadRef(rollout, nil, notFound))
+}
+
+func TestVerifyRolloutSpecTrafficRouting(t *testing.T) {
+	validRollout := &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"key": "value"},
+			},
+			Strategy: v1alpha1.RolloutStrategy{
+				CanaryStrategy: &v1alpha1.CanaryStrategy{
+					CanaryService: "canary",
+					StableService: "stable",
+					TrafficRouting: &v1alpha1.RolloutTrafficRouting{
+						Istio: &v1alpha1.IstioTrafficRouting{
+							VirtualService: v1alpha1.IstioVirtualService{
+								Name:   "vsvc",
+								Routes: []string{"primary"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Nil(t, VerifyRolloutSpec(validRollout, nil))
+
+	noStableSvc := validRollout.DeepCopy()
+	noStableSvc.Spec.Strategy.CanaryStrategy.StableService = ""
+	noStableSvcCond := VerifyRolloutSpec(noStableSvc, nil)
+	assert.NotNil(t, noStableSvcCond)
+	assert.Equal(t, fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.CanaryStrategy.StableService"), noStableSvcCond.Message)
+	assert.Equal(t, InvalidSpecReason, noStableSvcCond.Reason)
+
+	sameSvcs := validRollout.DeepCopy()
+	sameSvcs.Spec.Strategy.CanaryStrategy.StableService = "canary"
+	sameSvcsCond := VerifyRolloutSpec(sameSvcs, nil)
+	assert.NotNil(t, sameSvcsCond)
+	assert.Equal(t, DuplicatedCanaryServicesMessage, sameSvcsCond.Message)
+
+	noRoutes := validRollout.DeepCopy()
+	noRoutes.Spec.Strategy.CanaryStrategy.TrafficRouting.Istio.VirtualService.Routes = nil
+	noRoutesCond := VerifyRolloutSpec(noRoutes, nil)
+	assert.NotNil(t, noRoutesCond)
+	assert.Equal(t, fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.CanaryStrategy.TrafficRouting.Istio.VirtualService.Routes"), noRoutesCond.Message)
+
+	noStableIngress := validRollout.DeepCopy()
+	noStableIngress.Spec.Strategy.CanaryStrategy.TrafficRouting = &v1alpha1.RolloutTrafficRouting{
+		Nginx: &v1alpha1.NginxTrafficRouting{},
+	}
+	noStableIngressCond := VerifyRolloutSpec(noStableIngress, nil)
+	assert.NotNil(t, noStableIngressCond)
+	assert.Equal(t, fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.CanaryStrategy.TrafficRouting.Nginx.StableIngress"), noStableIngressCond.Message)
+}
+
 func TestVerifyRolloutSpecBaseCases(t *testing.T) {
 	ro := &v1alpha1.Rollout{
 		Spec: v1alpha1.RolloutSpec{
@@ -413,6 +566,190 @@ func TestVerifyRolloutSpecCanary(t *testing.T) {
 	}
 }
 
+func TestVerifyRolloutSpecAnalysis(t *testing.T) {
+	validRollout := &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"key": "value"},
+			},
+			Strategy: v1alpha1.RolloutStrategy{
+				CanaryStrategy: &v1alpha1.CanaryStrategy{
+					Steps: []v1alpha1.CanaryStep{{
+						Analysis: &v1alpha1.RolloutAnalysisStep{
+							TemplateName: "foo",
+							Templates: []v1alpha1.RolloutAnalysisTemplate{
+								{TemplateName: "bar"},
+								{TemplateName: "foo", ClusterScope: true},
+							},
+							Arguments: []v1alpha1.AnalysisRunArgument{
+								{Name: "service-name", Value: "guestbook"},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+	assert.Nil(t, VerifyRolloutSpec(validRollout, nil))
+
+	noTemplates := validRollout.DeepCopy()
+	noTemplates.Spec.Strategy.CanaryStrategy.Steps[0].Analysis = &v1alpha1.RolloutAnalysisStep{}
+	noTemplatesCond := VerifyRolloutSpec(noTemplates, nil)
+	assert.NotNil(t, noTemplatesCond)
+	assert.Equal(t, fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.CanaryStrategy.Steps[0].Analysis.TemplateName or .Spec.Strategy.CanaryStrategy.Steps[0].Analysis.Templates"), noTemplatesCond.Message)
+	assert.Equal(t, InvalidSpecReason, noTemplatesCond.Reason)
+
+	duplicateTemplates := validRollout.DeepCopy()
+	duplicateTemplates.Spec.Strategy.CanaryStrategy.Steps[0].Analysis.Templates[0].TemplateName = "foo"
+	duplicateTemplatesCond := VerifyRolloutSpec(duplicateTemplates, nil)
+	assert.NotNil(t, duplicateTemplatesCond)
+	assert.Equal(t, fmt.Sprintf(DuplicatedAnalysisTemplateMessage, ".Spec.Strategy.CanaryStrategy.Steps[0].Analysis", "foo"), duplicateTemplatesCond.Message)
+
+	duplicateArgs := validRollout.DeepCopy()
+	analysis := duplicateArgs.Spec.Strategy.CanaryStrategy.Steps[0].Analysis
+	analysis.Arguments = append(analysis.Arguments, v1alpha1.AnalysisRunArgument{Name: "service-name", Value: "other"})
+	duplicateArgsCond := VerifyRolloutSpec(duplicateArgs, nil)
+	assert.NotNil(t, duplicateArgsCond)
+	assert.Equal(t, fmt.Sprintf(DuplicatedAnalysisArgumentMessage, ".Spec.Strategy.CanaryStrategy.Steps[0].Analysis", "service-name"), duplicateArgsCond.Message)
+
+	invalidFieldRef := validRollout.DeepCopy()
+	invalidFieldRef.Spec.Strategy.CanaryStrategy.Steps[0].Analysis.Arguments[0] = v1alpha1.AnalysisRunArgument{
+		Name:      "service-name",
+		ValueFrom: &v1alpha1.ArgumentValueFrom{FieldRef: &v1alpha1.FieldRef{FieldPath: "spec.replicas"}},
+	}
+	invalidFieldRefCond := VerifyRolloutSpec(invalidFieldRef, nil)
+	assert.NotNil(t, invalidFieldRefCond)
+	assert.Equal(t, fmt.Sprintf(InvalidAnalysisArgumentMessage, ".Spec.Strategy.CanaryStrategy.Steps[0].Analysis", "service-name", "unsupported fieldPath 'spec.replicas'"), invalidFieldRefCond.Message)
+
+	multipleSources := validRollout.DeepCopy()
+	stable := v1alpha1.Stable
+	multipleSources.Spec.Strategy.CanaryStrategy.Steps[0].Analysis.Arguments[0] = v1alpha1.AnalysisRunArgument{
+		Name: "service-name",
+		ValueFrom: &v1alpha1.ArgumentValueFrom{
+			PodTemplateHashValue: &stable,
+			SecretKeyRef:         &v1alpha1.SecretKeyRef{Name: "secret", Key: "key"},
+		},
+	}
+	multipleSourcesCond := VerifyRolloutSpec(multipleSources, nil)
+	assert.NotNil(t, multipleSourcesCond)
+	assert.Equal(t, fmt.Sprintf(InvalidAnalysisArgumentMessage, ".Spec.Strategy.CanaryStrategy.Steps[0].Analysis", "service-name", "valueFrom must set exactly one of podTemplateHashValue, fieldRef or secretKeyRef"), multipleSourcesCond.Message)
+
+	blueGreen := &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			Selector: validRollout.Spec.Selector,
+			Strategy: v1alpha1.RolloutStrategy{
+				BlueGreenStrategy: &v1alpha1.BlueGreenStrategy{
+					ActiveService:         "active",
+					PostPromotionAnalysis: &v1alpha1.RolloutAnalysisStep{},
+				},
+			},
+		},
+	}
+	blueGreenCond := VerifyRolloutSpec(blueGreen, nil)
+	assert.NotNil(t, blueGreenCond)
+	assert.Equal(t, fmt.Sprintf(MissingFieldMessage, ".Spec.Strategy.BlueGreenStrategy.PostPromotionAnalysis.TemplateName or .Spec.Strategy.BlueGreenStrategy.PostPromotionAnalysis.Templates"), blueGreenCond.Message)
+}
+
+func TestVerifyRolloutAnalysisTemplates(t *testing.T) {
+	rollout := &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				CanaryStrategy: &v1alpha1.CanaryStrategy{
+					Analysis: &v1alpha1.RolloutAnalysisStep{
+						Templates: []v1alpha1.RolloutAnalysisTemplate{
+							{TemplateName: "foo"},
+							{TemplateName: "bar"},
+							{TemplateName: "missing"},
+						},
+					},
+				},
+			},
+		},
+	}
+	specs := map[string]*v1alpha1.AnalysisTemplateSpec{
+		"foo": {Metrics: []v1alpha1.Metric{{Name: "success-rate"}}},
+		"bar": {Metrics: []v1alpha1.Metric{{Name: "latency"}}},
+	}
+	getTemplateSpec := func(ref v1alpha1.RolloutAnalysisTemplate) (*v1alpha1.AnalysisTemplateSpec, error) {
+		if spec, ok := specs[ref.TemplateName]; ok {
+			return spec, nil
+		}
+		return nil, fmt.Errorf("template '%s' not found", ref.TemplateName)
+	}
+	assert.Nil(t, VerifyRolloutAnalysisTemplates(rollout, nil, getTemplateSpec))
+
+	specs["bar"].Metrics = append(specs["bar"].Metrics, v1alpha1.Metric{Name: "success-rate"})
+	cond := VerifyRolloutAnalysisTemplates(rollout, nil, getTemplateSpec)
+	assert.NotNil(t, cond)
+	assert.Equal(t, InvalidSpecReason, cond.Reason)
+	assert.Equal(t, fmt.Sprintf(DuplicatedAnalysisMetricMessage, ".Spec.Strategy.CanaryStrategy.Analysis", "success-rate"), cond.Message)
+}
+
+func TestVerifyRolloutAnalysisTemplateArguments(t *testing.T) {
+	rollout := &v1alpha1.Rollout{
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				CanaryStrategy: &v1alpha1.CanaryStrategy{
+					Steps: []v1alpha1.CanaryStep{{
+						Analysis: &v1alpha1.RolloutAnalysisStep{
+							Templates: []v1alpha1.RolloutAnalysisTemplate{
+								{TemplateName: "foo"},
+								{TemplateName: "bar"},
+							},
+							Arguments: []v1alpha1.AnalysisRunArgument{
+								{Name: "service", Value: "guestbook"},
+							},
+						},
+					}},
+				},
+			},
+		},
+	}
+	defaultValue := "guestbook"
+	specs := map[string]*v1alpha1.AnalysisTemplateSpec{
+		"foo": {
+			Metrics: []v1alpha1.Metric{{Name: "success-rate"}},
+			Args:    []v1alpha1.AnalysisTemplateArgument{{Name: "service"}},
+		},
+		"bar": {
+			Metrics: []v1alpha1.Metric{{Name: "latency"}},
+			Args:    []v1alpha1.AnalysisTemplateArgument{{Name: "service"}, {Name: "app", Default: &defaultValue}},
+		},
+	}
+	getTemplateSpec := func(ref v1alpha1.RolloutAnalysisTemplate) (*v1alpha1.AnalysisTemplateSpec, error) {
+		return specs[ref.TemplateName], nil
+	}
+	path := ".Spec.Strategy.CanaryStrategy.Steps[0].Analysis"
+	assert.Nil(t, VerifyRolloutAnalysisTemplates(rollout, nil, getTemplateSpec))
+
+	undeclared := rollout.DeepCopy()
+	analysis := undeclared.Spec.Strategy.CanaryStrategy.Steps[0].Analysis
+	analysis.Arguments = append(analysis.Arguments, v1alpha1.AnalysisRunArgument{Name: "unknown", Value: "foo"})
+	cond := VerifyRolloutAnalysisTemplates(undeclared, nil, getTemplateSpec)
+	assert.NotNil(t, cond)
+	assert.Equal(t, InvalidSpecReason, cond.Reason)
+	assert.Equal(t, fmt.Sprintf(UndeclaredAnalysisArgumentMessage, path, "unknown"), cond.Message)
+
+	missing := rollout.DeepCopy()
+	missing.Spec.Strategy.CanaryStrategy.Steps[0].Analysis.Arguments = nil
+	cond = VerifyRolloutAnalysisTemplates(missing, nil, getTemplateSpec)
+	assert.NotNil(t, cond)
+	assert.Equal(t, fmt.Sprintf(MissingAnalysisArgumentMessage, path, "service"), cond.Message)
+
+	otherValue := "other"
+	specs["foo"].Args = append(specs["foo"].Args, v1alpha1.AnalysisTemplateArgument{Name: "app", Default: &otherValue})
+	cond = VerifyRolloutAnalysisTemplates(rollout, nil, getTemplateSpec)
+	assert.NotNil(t, cond)
+	assert.Equal(t, fmt.Sprintf(ConflictingAnalysisArgumentMessage, path, "app"), cond.Message)
+
+	// a template without declared arguments accepts the arguments the other templates do not declare
+	specs["foo"].Args = nil
+	assert.Nil(t, VerifyRolloutAnalysisTemplates(undeclared, nil, getTemplateSpec))
+	cond = VerifyRolloutAnalysisTemplates(missing, nil, getTemplateSpec)
+	assert.NotNil(t, cond)
+	assert.Equal(t, fmt.Sprintf(MissingAnalysisArgumentMessage, path, "service"), cond.Message)
+}
+
 func TestInvalidMaxSurgeMaxUnavailable(t *testing.T) {
 	r := func(maxSurge, maxUnavailable intstr.IntOrString) *v1alpha1.Rollout {
 		return &v1alpha1.Rollout{
@@ -778,6 +1115,14 @@ func TestComputeGenerationHash(t *testing.T) {
 	roPausedHash := ComputeGenerationHash(roPaused.Spec)
 
 	assert.NotEqual(t, baseline, roPausedHash)
+
+	// the template resolved from the workloadRef is not stored, so it does not change the hash
+	roWorkloadRef := ro.DeepCopy()
+	roWorkloadRef.Spec.WorkloadRef = &v1alpha1.WorkloadRef{APIVersion: "apps/v1", Kind: "Deployment", Name: "guestbook"}
+	resolvedHash := ComputeGenerationHash(roWorkloadRef.Spec)
+	roWorkloadRef.Spec.Template = v1.PodTemplateSpec{}
+	assert.Equal(t, resolvedHash, ComputeGenerationHash(roWorkloadRef.Spec))
+	assert.NotEqual(t, baseline, resolvedHash)
 }
 
 // TestComputeStableStepHash verifies we generate different hashes for various step definitions.
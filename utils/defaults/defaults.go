@@ -1,6 +1,8 @@
 package defaults
 
 import (
+	"os"
+
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
@@ -21,6 +23,14 @@ const (
 	DefaultScaleDownDelaySeconds = int32(30)
 	// DefaultAutoPromotionEnabled default value for auto promoting a blueGreen strategy
 	DefaultAutoPromotionEnabled = true
+	// DefaultSMITrafficSplitVersion default version of the SMI TrafficSplit resource modified by the controller
+	DefaultSMITrafficSplitVersion = "v1alpha1"
+	// DefaultRolloutsNamespace default namespace of the controller if the POD_NAMESPACE environment variable is not set
+	DefaultRolloutsNamespace = "argo-rollouts"
+)
+
+var (
+	defaultSMITrafficSplitVersion = DefaultSMITrafficSplitVersion
 )
 
 // GetRolloutReplicasOrDefault returns the specified number of replicas in a rollout or the default number
@@ -107,4 +117,22 @@ func GetAutoPromotionEnabledOrDefault(rollout *v1alpha1.Rollout) bool {
 		return DefaultAutoPromotionEnabled
 	}
 	return *rollout.Spec.Strategy.BlueGreenStrategy.AutoPromotionEnabled
-}
\ No newline at end of file
+}
+
+// GetSMIAPIVersion returns the version of the SMI TrafficSplit resource modified by the controller
+func GetSMIAPIVersion() string {
+	return defaultSMITrafficSplitVersion
+}
+
+// SetSMIAPIVersion sets the version of the SMI TrafficSplit resource modified by the controller
+func SetSMIAPIVersion(apiVersion string) {
+	defaultSMITrafficSplitVersion = apiVersion
+}
+
+// Namespace returns the namespace of the controller from the POD_NAMESPACE environment variable or the default namespace
+func Namespace() string {
+	if ns := os.Getenv("POD_NAMESPACE"); ns != "" {
+		return ns
+	}
+	return DefaultRolloutsNamespace
+}
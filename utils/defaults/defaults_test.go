@@ -1,6 +1,7 @@
 package defaults
 
 import (
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -181,4 +182,18 @@ func TestGetExperimentProgressDeadlineSecondsOrDefault(t *testing.T) {
 	assert.Equal(t, seconds, GetExperimentProgressDeadlineSecondsOrDefault(nonDefaultValue))
 	defaultValue := &v1alpha1.Experiment{}
 	assert.Equal(t, DefaultProgressDeadlineSeconds, GetExperimentProgressDeadlineSecondsOrDefault(defaultValue))
-}
\ No newline at end of file
+}
+func TestSetSMIAPIVersion(t *testing.T) {
+	assert.Equal(t, DefaultSMITrafficSplitVersion, GetSMIAPIVersion())
+	SetSMIAPIVersion("v1alpha2")
+	assert.Equal(t, "v1alpha2", GetSMIAPIVersion())
+	SetSMIAPIVersion(DefaultSMITrafficSplitVersion)
+}
+
+func TestNamespace(t *testing.T) {
+	os.Unsetenv("POD_NAMESPACE")
+	assert.Equal(t, DefaultRolloutsNamespace, Namespace())
+	os.Setenv("POD_NAMESPACE", "custom")
+	defer os.Unsetenv("POD_NAMESPACE")
+	assert.Equal(t, "custom", Namespace())
+}
@@ -2,8 +2,36 @@ package evaluate
 
 import (
 	"github.com/antonmedv/expr"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 )
 
+// EvaluateResult returns the status of a measurement by evaluating the success and failure conditions of the metric
+// against its result. The failure condition takes precedence, and the measurement is inconclusive when neither
+// condition is met.
+func EvaluateResult(result interface{}, metric v1alpha1.Metric, logCtx log.Entry) v1alpha1.AnalysisStatus {
+	successCondition, err := EvalCondition(result, metric.SuccessCondition)
+	if err != nil {
+		logCtx.Warning(err.Error())
+		return v1alpha1.AnalysisStatusError
+	}
+
+	failCondition, err := EvalCondition(result, metric.FailureCondition)
+	if err != nil {
+		logCtx.Warning(err.Error())
+		return v1alpha1.AnalysisStatusError
+	}
+	if failCondition {
+		return v1alpha1.AnalysisStatusFailed
+	}
+
+	if !successCondition {
+		return v1alpha1.AnalysisStatusInconclusive
+	}
+	return v1alpha1.AnalysisStatusSuccessful
+}
+
 // EvalCondition evaluates the condition with the resultValue as an input
 func EvalCondition(resultValue interface{}, condition string) (bool, error) {
 	env := map[string]interface{}{
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/argoproj/argo-rollouts/pkg/apis/rollouts/v1alpha1"
 )
 
 func TestEvaluateConditonWithSucces(t *testing.T) {
@@ -56,3 +59,25 @@ func TestEvaluateInvalidStruct(t *testing.T) {
 	assert.Errorf(t, err, "")
 	assert.False(t, b)
 }
+
+func TestEvaluateResult(t *testing.T) {
+	logCtx := *log.NewEntry(log.New())
+	tests := []struct {
+		successCondition string
+		failureCondition string
+		status           v1alpha1.AnalysisStatus
+	}{
+		{"result > 0.9", "result < 0.5", v1alpha1.AnalysisStatusSuccessful},
+		{"result > 0.9", "result > 0.5", v1alpha1.AnalysisStatusFailed},
+		{"result > 1", "result < 0.5", v1alpha1.AnalysisStatusInconclusive},
+		{"invalid > 0.9", "result < 0.5", v1alpha1.AnalysisStatusError},
+		{"result > 0.9", "invalid < 0.5", v1alpha1.AnalysisStatusError},
+	}
+	for _, test := range tests {
+		metric := v1alpha1.Metric{
+			SuccessCondition: test.successCondition,
+			FailureCondition: test.failureCondition,
+		}
+		assert.Equal(t, test.status, EvaluateResult(0.95, metric, logCtx))
+	}
+}
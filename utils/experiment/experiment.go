@@ -122,3 +122,22 @@ func (o ExperimentByCreationTimestamp) Less(i, j int) bool {
 	}
 	return o[i].CreationTimestamp.Before(&o[j].CreationTimestamp)
 }
+
+// AnalysisRunNameFromExperiment gets the name of the AnalysisRun of an analysis of the experiment
+func AnalysisRunNameFromExperiment(experiment *v1alpha1.Experiment, analysis v1alpha1.ExperimentAnalysisTemplateRef) string {
+	return fmt.Sprintf("%s-%s", experiment.Name, analysis.Name)
+}
+
+// HasFailed indicates if an analysis of the experiment failed, errored or was inconclusive
+func HasFailed(experiment *v1alpha1.Experiment) bool {
+	switch experiment.Status.Status {
+	case v1alpha1.AnalysisStatusFailed, v1alpha1.AnalysisStatusError, v1alpha1.AnalysisStatusInconclusive:
+		return true
+	}
+	return false
+}
+
+// AnalysesSuccessful indicates if the experiment has no analyses or if all of its analyses were successful
+func AnalysesSuccessful(experiment *v1alpha1.Experiment) bool {
+	return len(experiment.Spec.Analyses) == 0 || experiment.Status.Status == v1alpha1.AnalysisStatusSuccessful
+}
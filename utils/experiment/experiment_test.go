@@ -32,6 +32,46 @@ func TestHasFinished(t *testing.T) {
 	assert.True(t, HasFinished(e))
 }
 
+func TestHasFailed(t *testing.T) {
+	e := &v1alpha1.Experiment{}
+	assert.False(t, HasFailed(e))
+
+	e.Status.Status = v1alpha1.AnalysisStatusRunning
+	assert.False(t, HasFailed(e))
+
+	e.Status.Status = v1alpha1.AnalysisStatusSuccessful
+	assert.False(t, HasFailed(e))
+
+	for _, status := range []v1alpha1.AnalysisStatus{v1alpha1.AnalysisStatusFailed, v1alpha1.AnalysisStatusError, v1alpha1.AnalysisStatusInconclusive} {
+		e.Status.Status = status
+		assert.True(t, HasFailed(e))
+	}
+}
+
+func TestAnalysesSuccessful(t *testing.T) {
+	e := &v1alpha1.Experiment{}
+	assert.True(t, AnalysesSuccessful(e))
+
+	e.Spec.Analyses = []v1alpha1.ExperimentAnalysisTemplateRef{{Name: "success-rate", TemplateName: "success-rate"}}
+	assert.False(t, AnalysesSuccessful(e))
+
+	e.Status.Status = v1alpha1.AnalysisStatusRunning
+	assert.False(t, AnalysesSuccessful(e))
+
+	e.Status.Status = v1alpha1.AnalysisStatusSuccessful
+	assert.True(t, AnalysesSuccessful(e))
+}
+
+func TestAnalysisRunNameFromExperiment(t *testing.T) {
+	e := &v1alpha1.Experiment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+	}
+	analysis := v1alpha1.ExperimentAnalysisTemplateRef{Name: "bar", TemplateName: "baz"}
+	assert.Equal(t, "foo-bar", AnalysisRunNameFromExperiment(e, analysis))
+}
+
 func TestCalculateTemplateReplicasCount(t *testing.T) {
 	e := &v1alpha1.Experiment{}
 	template := v1alpha1.TemplateSpec{
@@ -36,6 +36,10 @@ func DesiredReplicaCountsForCanary(rollout *v1alpha1.Rollout, newRS, stableRS *a
 		// Instead the controller tries to get the newRS to 100% traffic.
 		desiredNewRSReplicaCount = rolloutSpecReplica
 		desiredStableRSReplicaCount = 0
+	} else if rollout.Spec.Strategy.CanaryStrategy.TrafficRouting != nil {
+		// The traffic routing splits the traffic independently of the replica counts, so the stableRS keeps all its
+		// replicas to handle the traffic shifted back to it.
+		desiredStableRSReplicaCount = rolloutSpecReplica
 	}
 	return desiredNewRSReplicaCount, desiredStableRSReplicaCount
 
@@ -98,6 +102,15 @@ func CalculateReplicaCountsForCanary(rollout *v1alpha1.Rollout, newRS *appsv1.Re
 		desiredStableRSReplicaCount = 0
 	}
 
+	if rollout.Spec.Strategy.CanaryStrategy.TrafficRouting != nil {
+		// The traffic routing controls how much traffic each ReplicaSet receives, so the replica counts do not need
+		// to approximate the setWeight within the maxSurge and maxUnavailable limits.
+		if scaleStableRS {
+			desiredStableRSReplicaCount = rolloutSpecReplica
+		}
+		return desiredNewRSReplicaCount, desiredStableRSReplicaCount
+	}
+
 	maxSurge := MaxSurge(rollout)
 
 	if extraReplicaAdded(rolloutSpecReplica, setWeight) {
@@ -239,7 +252,11 @@ func GetCurrentCanaryStep(rollout *v1alpha1.Rollout) (*v1alpha1.CanaryStep, *int
 // GetCurrentSetWeight grabs the current setWeight used by the rollout by iterating backwards from the current step
 // until it finds a setWeight step. The controller defaults to 100 if it iterates through all the steps with no
 // setWeight or if there is no current step (i.e. the controller has already stepped through all the steps).
+// An aborted rollout always has a setWeight of 0 so that all the traffic goes back to the stable ReplicaSet.
 func GetCurrentSetWeight(rollout *v1alpha1.Rollout) int32 {
+	if rollout.Status.Abort {
+		return 0
+	}
 	currentStep, currentStepIndex := GetCurrentCanaryStep(rollout)
 	if currentStep == nil {
 		return 100
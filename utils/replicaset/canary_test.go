@@ -388,6 +388,26 @@ func TestCalculateReplicaCountsForCanaryStableRSdEdgeCases(t *testing.T) {
 	assert.Equal(t, int32(0), stableRSReplicaCount)
 }
 
+func TestCalculateReplicaCountsForCanaryWithTrafficRouting(t *testing.T) {
+	rollout := newRollout(10, 1, intstr.FromInt(0), intstr.FromInt(1), "canary", "stable")
+	rollout.Spec.Strategy.CanaryStrategy.TrafficRouting = &v1alpha1.RolloutTrafficRouting{
+		Istio: &v1alpha1.IstioTrafficRouting{},
+	}
+	stableRS := newRS("stable", 10, 10)
+	canaryRS := newRS("canary", 0, 0)
+	newRSReplicaCount, stableRSReplicaCount := CalculateReplicaCountsForCanary(rollout, canaryRS, stableRS, []*appsv1.ReplicaSet{})
+	assert.Equal(t, int32(1), newRSReplicaCount)
+	assert.Equal(t, int32(10), stableRSReplicaCount)
+
+	newRSReplicaCount, stableRSReplicaCount = DesiredReplicaCountsForCanary(rollout, canaryRS, stableRS)
+	assert.Equal(t, int32(1), newRSReplicaCount)
+	assert.Equal(t, int32(10), stableRSReplicaCount)
+
+	newRSReplicaCount, stableRSReplicaCount = CalculateReplicaCountsForCanary(rollout, canaryRS, nil, []*appsv1.ReplicaSet{})
+	assert.Equal(t, int32(10), newRSReplicaCount)
+	assert.Equal(t, int32(0), stableRSReplicaCount)
+}
+
 func TestGetCurrentCanaryStep(t *testing.T) {
 	rollout := newRollout(10, 10, intstr.FromInt(0), intstr.FromInt(1), "", "")
 	rollout.Spec.Strategy.CanaryStrategy.Steps = nil
@@ -420,6 +440,9 @@ func TestGetCurrentSetWeight(t *testing.T) {
 	setWeight = GetCurrentSetWeight(rollout)
 	assert.Equal(t, setWeight, int32(10))
 
+	rollout.Status.Abort = true
+	setWeight = GetCurrentSetWeight(rollout)
+	assert.Equal(t, setWeight, int32(0))
 }
 
 func TestGetCurrentExperiment(t *testing.T) {
@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 	corev1defaults "k8s.io/kubernetes/pkg/apis/core/v1"
@@ -115,6 +116,23 @@ func MaxRevision(allRSs []*appsv1.ReplicaSet) int64 {
 	return max
 }
 
+// LastRevision finds the second max revision number in all replica sets (the last revision)
+func LastRevision(allRSs []*appsv1.ReplicaSet) int64 {
+	max, secMax := int64(0), int64(0)
+	for _, rs := range allRSs {
+		if v, err := Revision(rs); err != nil {
+			// Skip the replica sets when it failed to parse their revision information
+			log.WithError(err).Info("Couldn't parse revision, rollout controller will skip it when reconciling revisions.")
+		} else if v >= max {
+			secMax = max
+			max = v
+		} else if v > secMax {
+			secMax = v
+		}
+	}
+	return secMax
+}
+
 // Revision returns the revision number of the input object.
 func Revision(obj runtime.Object) (int64, error) {
 	acc, err := meta.Accessor(obj)
@@ -319,9 +337,14 @@ func ResetCurrentStepIndex(rollout *v1alpha1.Rollout) *int32 {
 func PodTemplateEqualIgnoreHash(live, desired *corev1.PodTemplateSpec) bool {
 	live = live.DeepCopy()
 	desired = desired.DeepCopy()
-	// Remove hash labels from template.Labels before comparing
+	// Remove hash labels from template.Labels before comparing. The Deployment hash label is kept by the
+	// ReplicaSets adopted from a Deployment
 	delete(live.Labels, v1alpha1.DefaultRolloutUniqueLabelKey)
 	delete(desired.Labels, v1alpha1.DefaultRolloutUniqueLabelKey)
+	delete(live.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	delete(desired.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+	// Remove the anti-affinity term injected by the controller since the rollout template does not have it
+	live.Spec.Affinity = RemoveInjectedAntiAffinityRule(live.Spec.Affinity)
 
 	podTemplate := corev1.PodTemplate{
 		Template: *desired,
@@ -331,6 +354,116 @@ func PodTemplateEqualIgnoreHash(live, desired *corev1.PodTemplateSpec) bool {
 	return apiequality.Semantic.DeepEqual(live, desired)
 }
 
+// GetAntiAffinity returns the anti-affinity configuration of the rollout strategy
+func GetAntiAffinity(rollout *v1alpha1.Rollout) *v1alpha1.AntiAffinity {
+	if rollout.Spec.Strategy.BlueGreenStrategy != nil {
+		return rollout.Spec.Strategy.BlueGreenStrategy.AntiAffinity
+	}
+	if rollout.Spec.Strategy.CanaryStrategy != nil {
+		return rollout.Spec.Strategy.CanaryStrategy.AntiAffinity
+	}
+	return nil
+}
+
+// GetStableHash returns the pod template hash of the stable ReplicaSet: the active selector of a blue-green rollout
+// or the stable ReplicaSet of a canary rollout
+func GetStableHash(rollout *v1alpha1.Rollout) string {
+	if rollout.Spec.Strategy.BlueGreenStrategy != nil {
+		return rollout.Status.BlueGreen.ActiveSelector
+	}
+	if rollout.Spec.Strategy.CanaryStrategy != nil {
+		return rollout.Status.Canary.StableRS
+	}
+	return ""
+}
+
+// GenerateReplicaSetAffinity returns the affinity of the ReplicaSet with the given pod template hash. If the rollout
+// strategy enables anti-affinity, a pod anti-affinity term keeps the pods of the ReplicaSet off the nodes running the
+// stable pods. The term is added after the pod template hash is computed so it does not change the hash.
+func GenerateReplicaSetAffinity(rollout *v1alpha1.Rollout, podHash string) *corev1.Affinity {
+	affinity := rollout.Spec.Template.Spec.Affinity.DeepCopy()
+	antiAffinity := GetAntiAffinity(rollout)
+	stableHash := GetStableHash(rollout)
+	if antiAffinity == nil || stableHash == "" || stableHash == podHash {
+		return affinity
+	}
+	term := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      v1alpha1.DefaultRolloutUniqueLabelKey,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{stableHash},
+			}},
+		},
+		TopologyKey: corev1.LabelHostname,
+	}
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	if affinity.PodAntiAffinity == nil {
+		affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+	if antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, term)
+	} else if antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution != nil {
+		affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, corev1.WeightedPodAffinityTerm{
+			Weight:          antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution.Weight,
+			PodAffinityTerm: term,
+		})
+	}
+	return affinity
+}
+
+// RemoveInjectedAntiAffinityRule returns a copy of the affinity without the pod anti-affinity terms injected by
+// the controller, which are the terms selecting pods by the rollouts-pod-template-hash label
+func RemoveInjectedAntiAffinityRule(affinity *corev1.Affinity) *corev1.Affinity {
+	affinity = affinity.DeepCopy()
+	if affinity == nil || affinity.PodAntiAffinity == nil {
+		return affinity
+	}
+	removed := false
+	var required []corev1.PodAffinityTerm
+	for _, term := range affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		if isInjectedAntiAffinityTerm(term) {
+			removed = true
+			continue
+		}
+		required = append(required, term)
+	}
+	var preferred []corev1.WeightedPodAffinityTerm
+	for _, term := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		if isInjectedAntiAffinityTerm(term.PodAffinityTerm) {
+			removed = true
+			continue
+		}
+		preferred = append(preferred, term)
+	}
+	if !removed {
+		return affinity
+	}
+	affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = required
+	affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = preferred
+	if len(required) == 0 && len(preferred) == 0 {
+		affinity.PodAntiAffinity = nil
+	}
+	if affinity.NodeAffinity == nil && affinity.PodAffinity == nil && affinity.PodAntiAffinity == nil {
+		return nil
+	}
+	return affinity
+}
+
+func isInjectedAntiAffinityTerm(term corev1.PodAffinityTerm) bool {
+	if term.LabelSelector == nil {
+		return false
+	}
+	for _, expr := range term.LabelSelector.MatchExpressions {
+		if expr.Key == v1alpha1.DefaultRolloutUniqueLabelKey {
+			return true
+		}
+	}
+	return false
+}
+
 // GetPodTemplateHash returns the rollouts-pod-template-hash value from a ReplicaSet's labels
 func GetPodTemplateHash(rs *appsv1.ReplicaSet) string {
 	if rs.Labels == nil {
@@ -13,6 +13,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	corev1defaults "k8s.io/kubernetes/pkg/apis/core/v1"
 	"k8s.io/kubernetes/pkg/controller"
 	"k8s.io/utils/pointer"
 
@@ -327,6 +328,28 @@ func TestMaxRevision(t *testing.T) {
 	assert.Equal(t, int64(2), MaxRevision(allRs))
 }
 
+func TestLastRevision(t *testing.T) {
+	newRSWithRevision := func(revision string) *appsv1.ReplicaSet {
+		return &appsv1.ReplicaSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					annotations.RevisionAnnotation: revision,
+				},
+			},
+		}
+	}
+	assert.Equal(t, int64(0), LastRevision(nil))
+	assert.Equal(t, int64(0), LastRevision([]*appsv1.ReplicaSet{newRSWithRevision("3")}))
+	allRs := []*appsv1.ReplicaSet{
+		newRSWithRevision("3"),
+		newRSWithRevision("5"),
+		newRSWithRevision("invalid"),
+		newRSWithRevision("4"),
+		{},
+	}
+	assert.Equal(t, int64(4), LastRevision(allRs))
+}
+
 func rs(replicas int32, creationTimestamp metav1.Time) *appsv1.ReplicaSet {
 	return &appsv1.ReplicaSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -740,3 +763,81 @@ func TestReplicaSetsByRevisionNumber(t *testing.T) {
 		assert.Equal(t, expected, replicaSets)
 	})
 }
+
+func TestGenerateReplicaSetAffinity(t *testing.T) {
+	ro := generateRollout("ngnix")
+	ro.Spec.Strategy.CanaryStrategy = &v1alpha1.CanaryStrategy{}
+	ro.Status.Canary.StableRS = "stable"
+	podHash := controller.ComputeHash(&ro.Spec.Template, nil)
+
+	// Anti-affinity is not enabled
+	assert.Nil(t, GenerateReplicaSetAffinity(&ro, podHash))
+
+	ro.Spec.Strategy.CanaryStrategy.AntiAffinity = &v1alpha1.AntiAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &v1alpha1.RequiredDuringSchedulingIgnoredDuringExecution{},
+	}
+	affinity := GenerateReplicaSetAffinity(&ro, podHash)
+	expectedTerm := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      v1alpha1.DefaultRolloutUniqueLabelKey,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{"stable"},
+			}},
+		},
+		TopologyKey: corev1.LabelHostname,
+	}
+	assert.Equal(t, []corev1.PodAffinityTerm{expectedTerm}, affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+	assert.Nil(t, RemoveInjectedAntiAffinityRule(affinity))
+	// The injected term does not change the pod template hash
+	assert.Equal(t, podHash, controller.ComputeHash(&ro.Spec.Template, nil))
+	assert.Nil(t, ro.Spec.Template.Spec.Affinity)
+
+	// The stable ReplicaSet does not avoid itself
+	assert.Nil(t, GenerateReplicaSetAffinity(&ro, "stable"))
+
+	ro.Spec.Strategy.CanaryStrategy = nil
+	ro.Spec.Strategy.BlueGreenStrategy = &v1alpha1.BlueGreenStrategy{
+		AntiAffinity: &v1alpha1.AntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: &v1alpha1.PreferredDuringSchedulingIgnoredDuringExecution{Weight: 50},
+		},
+	}
+	// No active ReplicaSet yet
+	assert.Nil(t, GenerateReplicaSetAffinity(&ro, podHash))
+
+	ro.Status.BlueGreen.ActiveSelector = "stable"
+	userTerm := corev1.WeightedPodAffinityTerm{
+		Weight: 10,
+		PodAffinityTerm: corev1.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+			TopologyKey:   corev1.LabelHostname,
+		},
+	}
+	ro.Spec.Template.Spec.Affinity = &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{userTerm},
+		},
+	}
+	affinity = GenerateReplicaSetAffinity(&ro, podHash)
+	assert.Equal(t, []corev1.WeightedPodAffinityTerm{userTerm, {Weight: 50, PodAffinityTerm: expectedTerm}}, affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+	assert.Len(t, ro.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 1)
+	assert.Equal(t, ro.Spec.Template.Spec.Affinity, RemoveInjectedAntiAffinityRule(affinity))
+}
+
+func TestPodTemplateEqualIgnoreHash(t *testing.T) {
+	ro := generateRollout("ngnix")
+	podTemplate := corev1.PodTemplate{
+		Template: *ro.Spec.Template.DeepCopy(),
+	}
+	corev1defaults.SetObjectDefaults_PodTemplate(&podTemplate)
+	live := podTemplate.Template
+	live.Labels = map[string]string{
+		"name":                                 "ngnix",
+		v1alpha1.DefaultRolloutUniqueLabelKey:  "abc",
+		appsv1.DefaultDeploymentUniqueLabelKey: "def",
+	}
+	assert.True(t, PodTemplateEqualIgnoreHash(&live, &ro.Spec.Template))
+
+	live.Labels["name"] = "other"
+	assert.False(t, PodTemplateEqualIgnoreHash(&live, &ro.Spec.Template))
+}
@@ -33,6 +33,9 @@ func GetRolloutServiceKeys(rollout *v1alpha1.Rollout) []string {
 		if rollout.Spec.Strategy.CanaryStrategy.CanaryService != "" {
 			servicesSet[fmt.Sprintf("%s/%s", rollout.Namespace, rollout.Spec.Strategy.CanaryStrategy.CanaryService)] = true
 		}
+		if rollout.Spec.Strategy.CanaryStrategy.StableService != "" {
+			servicesSet[fmt.Sprintf("%s/%s", rollout.Namespace, rollout.Spec.Strategy.CanaryStrategy.StableService)] = true
+		}
 	}
 	var services []string
 	for svc := range servicesSet {
@@ -61,6 +61,23 @@ func TestGetRolloutServiceKeysForCanaryWithCanaryService(t *testing.T) {
 	assert.ElementsMatch(t, keys, []string{"default/canary-service"})
 }
 
+func TestGetRolloutServiceKeysForCanaryWithStableService(t *testing.T) {
+	keys := GetRolloutServiceKeys(&v1alpha1.Rollout{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+		},
+		Spec: v1alpha1.RolloutSpec{
+			Strategy: v1alpha1.RolloutStrategy{
+				CanaryStrategy: &v1alpha1.CanaryStrategy{
+					CanaryService: "canary-service",
+					StableService: "stable-service",
+				},
+			},
+		},
+	})
+	assert.ElementsMatch(t, keys, []string{"default/canary-service", "default/stable-service"})
+}
+
 func TestGetRolloutServiceKeysForBlueGreen(t *testing.T) {
 	keys := GetRolloutServiceKeys(&v1alpha1.Rollout{
 		ObjectMeta: metav1.ObjectMeta{